@@ -0,0 +1,98 @@
+// Package authorship checks that a recipe was submitted by the wallet it names as
+// its creator. The creator signs an EIP-712 typed-data message over the recipe
+// title and content hash (eth_signTypedData_v4, or signTypedData in ethers);
+// src/lib/creatorSignature.ts builds the same message in the frontend.
+package authorship
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// EIP-712 domain and primary type of the creator signature. The domain has no
+// chainId: the signature is checked by the backend, not a contract, and must
+// verify while the chain connection is down.
+const (
+	DomainName    = "ProofPot"
+	DomainVersion = "1"
+	PrimaryType   = "RecipeSubmission"
+)
+
+var (
+	ErrInvalidSignature = errors.New("malformed creator signature")
+	ErrCreatorMismatch  = errors.New("creator signature was not made by the creator address")
+)
+
+// TypedData returns the EIP-712 message the creator signs for a recipe.
+func TypedData(title string, contentHash [32]byte) apitypes.TypedData {
+	return apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+			},
+			PrimaryType: {
+				{Name: "title", Type: "string"},
+				{Name: "contentHash", Type: "bytes32"},
+			},
+		},
+		PrimaryType: PrimaryType,
+		Domain:      apitypes.TypedDataDomain{Name: DomainName, Version: DomainVersion},
+		Message: apitypes.TypedDataMessage{
+			"title":       title,
+			"contentHash": hexutil.Encode(contentHash[:]),
+		},
+	}
+}
+
+// Hash returns the EIP-712 digest of the recipe message, the value the wallet signs.
+func Hash(title string, contentHash [32]byte) (common.Hash, error) {
+	digest, _, err := apitypes.TypedDataAndHash(TypedData(title, contentHash))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+	return common.BytesToHash(digest), nil
+}
+
+// Recover returns the address that signed the recipe message. Signatures with the
+// legacy V values 27/28 that wallets return are accepted.
+func Recover(title string, contentHash [32]byte, signature []byte) (common.Address, error) {
+	if len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("%w: expected %d bytes, got %d", ErrInvalidSignature, crypto.SignatureLength, len(signature))
+	}
+	digest, err := Hash(title, contentHash)
+	if err != nil {
+		return common.Address{}, err
+	}
+	sig := make([]byte, len(signature))
+	copy(sig, signature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	pub, err := crypto.SigToPub(digest.Bytes(), sig)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// Verify checks a hex-encoded creator signature against the creator address.
+func Verify(title string, contentHash [32]byte, creator common.Address, signatureHex string) error {
+	signature, err := hexutil.Decode(signatureHex)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+	signer, err := Recover(title, contentHash, signature)
+	if err != nil {
+		return err
+	}
+	if signer != creator {
+		return fmt.Errorf("%w: signed by %s", ErrCreatorMismatch, signer.Hex())
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package authorship
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var testHash = [32]byte{0x12, 0x34}
+
+// sign signs the recipe message the way a wallet does, with a legacy V value.
+func sign(t *testing.T, title string, contentHash [32]byte) (string, common.Address) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest, err := Hash(title, contentHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := crypto.Sign(digest.Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature[64] += 27
+	return hexutil.Encode(signature), crypto.PubkeyToAddress(key.PublicKey)
+}
+
+func TestVerify(t *testing.T) {
+	signature, creator := sign(t, "Pancakes", testHash)
+
+	if err := Verify("Pancakes", testHash, creator, signature); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+
+	someoneElse := common.HexToAddress("0x00000000000000000000000000000000000000c2")
+	tests := []struct {
+		name      string
+		title     string
+		hash      [32]byte
+		creator   common.Address
+		signature string
+		want      error
+	}{
+		{"other creator", "Pancakes", testHash, someoneElse, signature, ErrCreatorMismatch},
+		{"other title", "Crepes", testHash, creator, signature, ErrCreatorMismatch},
+		{"other content", "Pancakes", [32]byte{0x56}, creator, signature, ErrCreatorMismatch},
+		{"not hex", "Pancakes", testHash, creator, "signed", ErrInvalidSignature},
+		{"truncated", "Pancakes", testHash, creator, signature[:100], ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify(tt.title, tt.hash, tt.creator, tt.signature); !errors.Is(err, tt.want) {
+				t.Errorf("Verify error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// The digest must not change silently: wallets and the frontend compute it independently.
+func TestHashIsStable(t *testing.T) {
+	digest, err := Hash("Pancakes", testHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	domain := crypto.Keccak256(
+		crypto.Keccak256([]byte("EIP712Domain(string name,string version)")),
+		crypto.Keccak256([]byte(DomainName)),
+		crypto.Keccak256([]byte(DomainVersion)),
+	)
+	message := crypto.Keccak256(
+		crypto.Keccak256([]byte("RecipeSubmission(string title,bytes32 contentHash)")),
+		crypto.Keccak256([]byte("Pancakes")),
+		testHash[:],
+	)
+	want := crypto.Keccak256Hash([]byte("\x19\x01"), domain, message)
+	if digest != want {
+		t.Errorf("digest = %s, want %s", digest.Hex(), want.Hex())
+	}
+}
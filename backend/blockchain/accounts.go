@@ -0,0 +1,238 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ErrNoSignerAvailable is returned when every signer account is unauthorized or
+// below the minimum balance. It is retryable: topping up or granting the
+// registrar role brings the accounts back on the next refresh.
+var ErrNoSignerAvailable = errors.New("no signer account available")
+
+// defaultMinSignerBalance is the balance below which a signer stops receiving jobs (0.01 ETH).
+var defaultMinSignerBalance = new(big.Int).Div(big.NewInt(params.Ether), big.NewInt(100))
+
+// signerAccount is one key of the signer pool. Each key has its own nonce
+// sequence, so a stuck transaction only holds up registrations sent from that key.
+type signerAccount struct {
+	signer Signer
+	opts   *bind.TransactOpts
+	nonces *NonceManager
+
+	// Guarded by signerPool.mu
+	inFlight   int      // Registrations currently using this key
+	balance    *big.Int // Last known balance, nil until the first refresh
+	authorized bool     // Owner or registrar according to the last refresh
+}
+
+func (a *signerAccount) address() common.Address {
+	return a.signer.Address()
+}
+
+// signerPool distributes registrations over the configured signer accounts.
+type signerPool struct {
+	mu         sync.Mutex
+	accounts   []*signerAccount
+	next       int // Round-robin start, so ties don't always go to the first key
+	minBalance *big.Int
+}
+
+// SignerStatus describes one signer account for the health endpoint.
+type SignerStatus struct {
+	Address    string `json:"address"`
+	Balance    string `json:"balance,omitempty"` // Wei, empty until the first refresh
+	Authorized bool   `json:"authorized"`
+	LowBalance bool   `json:"lowBalance"`
+	InFlight   int    `json:"inFlight"`
+}
+
+func newSignerPool(client Client, list []Signer, chainID *big.Int, minBalance *big.Int) *signerPool {
+	pool := &signerPool{minBalance: minBalance}
+	for _, s := range list {
+		pool.accounts = append(pool.accounts, &signerAccount{
+			signer:     s,
+			opts:       transactOpts(s, chainID),
+			nonces:     NewNonceManager(client, s.Address()),
+			authorized: true, // Until the first refresh says otherwise
+		})
+	}
+	return pool
+}
+
+// loadMinSignerBalance reads SIGNER_MIN_BALANCE_ETH.
+func loadMinSignerBalance() (*big.Int, error) {
+	raw := os.Getenv("SIGNER_MIN_BALANCE_ETH")
+	if raw == "" {
+		return defaultMinSignerBalance, nil
+	}
+	eth, ok := new(big.Float).SetString(raw)
+	if !ok || eth.Sign() < 0 {
+		return nil, fmt.Errorf("invalid SIGNER_MIN_BALANCE_ETH=%q", raw)
+	}
+	wei, _ := new(big.Float).Mul(eth, big.NewFloat(params.Ether)).Int(nil)
+	return wei, nil
+}
+
+// eligible reports whether a may receive new registrations. Must hold p.mu.
+func (p *signerPool) eligible(a *signerAccount) bool {
+	return a.authorized && (a.balance == nil || a.balance.Cmp(p.minBalance) >= 0)
+}
+
+// acquire picks the eligible account with the fewest registrations in flight.
+// The caller must release it when the registration is finished.
+func (p *signerPool) acquire() (*signerAccount, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var best *signerAccount
+	for i := range p.accounts {
+		a := p.accounts[(p.next+i)%len(p.accounts)]
+		if p.eligible(a) && (best == nil || a.inFlight < best.inFlight) {
+			best = a
+		}
+	}
+	if best == nil {
+		return nil, fmt.Errorf("%w: all %d signer accounts are unauthorized or below the minimum balance", ErrNoSignerAvailable, len(p.accounts))
+	}
+	best.inFlight++
+	p.next = (p.next + 1) % len(p.accounts)
+	return best, nil
+}
+
+// acquireAddress is acquire for the account with the given address, whether it is
+// eligible or not, to finish a registration it started. It returns nil if the
+// address is not in the pool (any more).
+func (p *signerPool) acquireAddress(address common.Address) *signerAccount {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, a := range p.accounts {
+		if a.address() == address {
+			a.inFlight++
+			return a
+		}
+	}
+	return nil
+}
+
+func (p *signerPool) release(a *signerAccount) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	a.inFlight--
+}
+
+// revoke marks a as unauthorized after the contract rejected it and reports
+// whether another account can take over.
+func (p *signerPool) revoke(a *signerAccount) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if a.authorized {
+		log.Printf("WARNING: Signer %s is not an authorized registrar, removing it from the pool", a.address().Hex())
+	}
+	a.authorized = false
+	for _, other := range p.accounts {
+		if p.eligible(other) {
+			return true
+		}
+	}
+	return false
+}
+
+// refresh reloads balances and registrar authorization of all accounts. Failed
+// lookups keep the previous values.
+func (p *signerPool) refresh(ctx context.Context) {
+	owner, ownerErr := registry.Owner(ctx)
+	if ownerErr != nil {
+		log.Printf("Warning: signer refresh could not read the registry owner: %v", ownerErr)
+	}
+
+	for _, a := range p.accounts {
+		addr := a.address()
+		balance, balanceErr := ethClient.BalanceAt(ctx, addr, nil)
+		if balanceErr != nil {
+			log.Printf("Warning: could not read balance of signer %s: %v", addr.Hex(), balanceErr)
+		}
+
+		// The owner may always register; everyone else needs the registrar role
+		var authorized, authKnown bool
+		if ownerErr == nil && addr == owner {
+			authorized, authKnown = true, true
+		} else if isRegistrar, err := registry.IsRegistrar(ctx, addr); err != nil {
+			log.Printf("Warning: could not check registrar role of signer %s: %v", addr.Hex(), err)
+		} else {
+			// Without the owner, "not a registrar" could still be the owner
+			authorized, authKnown = isRegistrar, isRegistrar || ownerErr == nil
+		}
+
+		p.mu.Lock()
+		if balanceErr == nil {
+			wasLow := a.balance != nil && a.balance.Cmp(p.minBalance) < 0
+			isLow := balance.Cmp(p.minBalance) < 0
+			if isLow && !wasLow {
+				log.Printf("WARNING: Signer %s balance %s wei is below the minimum %s wei, pausing it", addr.Hex(), balance, p.minBalance)
+			} else if wasLow && !isLow {
+				log.Printf("Signer %s topped up to %s wei, resuming it", addr.Hex(), balance)
+			}
+			a.balance = balance
+		}
+		if authKnown {
+			if a.authorized != authorized {
+				log.Printf("Signer %s registrar authorization changed to %t", addr.Hex(), authorized)
+			}
+			a.authorized = authorized
+		}
+		p.mu.Unlock()
+	}
+}
+
+// statuses returns a snapshot of all accounts.
+func (p *signerPool) statuses() []SignerStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]SignerStatus, 0, len(p.accounts))
+	for _, a := range p.accounts {
+		status := SignerStatus{
+			Address:    a.address().Hex(),
+			Authorized: a.authorized,
+			InFlight:   a.inFlight,
+		}
+		if a.balance != nil {
+			status.Balance = a.balance.String()
+			status.LowBalance = a.balance.Cmp(p.minBalance) < 0
+		}
+		out = append(out, status)
+	}
+	return out
+}
+
+// addresses lists the pool's accounts for log messages.
+func (p *signerPool) addresses() string {
+	names := make([]string, len(p.accounts))
+	for i, a := range p.accounts {
+		names[i] = a.address().Hex()
+	}
+	return strings.Join(names, ", ")
+}
+
+// IssuerSigner returns the signer that vouches for documents the backend hands out,
+// such as recipe certificates: the first configured signer account. It does not
+// send transactions, so it is usable whatever its balance.
+func IssuerSigner() (Signer, error) {
+	if err := ready(); err != nil {
+		return nil, err
+	}
+	return signers.accounts[0].signer, nil
+}
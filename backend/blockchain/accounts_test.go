@@ -0,0 +1,178 @@
+package blockchain
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"proofpot-backend/blockchain/contracts"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// newTestPool starts a simulated chain and configures the package to sign with
+// n registrar keys (not the owner).
+func newTestPool(t *testing.T, n int) (*SimulatedChain, []*ecdsa.PrivateKey) {
+	t.Helper()
+
+	owner, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []*ecdsa.PrivateKey
+	var addresses []common.Address
+	var signerList []Signer
+	for i := 0; i < n; i++ {
+		key, err := crypto.GenerateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		keys = append(keys, key)
+		addresses = append(addresses, crypto.PubkeyToAddress(key.PublicKey))
+		signerList = append(signerList, NewKeySigner(key))
+	}
+
+	chain, err := NewSimulatedChain(owner, addresses...)
+	if err != nil {
+		t.Fatalf("NewSimulatedChain: %v", err)
+	}
+	t.Cleanup(func() { chain.Close() })
+	if err := chain.GrantRegistrars(addresses...); err != nil {
+		t.Fatalf("GrantRegistrars: %v", err)
+	}
+	if err := configure(chain.Client(), signerList, chain.Contract); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	return chain, keys
+}
+
+func TestSignerPoolDistributesRegistrations(t *testing.T) {
+	_, keys := newTestPool(t, 3)
+	ctx := testContext(t)
+
+	var mu sync.Mutex
+	senders := make(map[string]int)
+	var wg sync.WaitGroup
+	errs := make(chan error, 9)
+	for i := 0; i < 9; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			hashHex, _ := testHash(fmt.Sprintf("burst %d", i))
+			_, err := RegisterRecipeOnChain(ctx, hashHex, common.Address{0xc0, byte(i)}.Hex(), func(sub SubmittedTx) {
+				mu.Lock()
+				senders[sub.From]++
+				mu.Unlock()
+			})
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("RegisterRecipeOnChain: %v", err)
+		}
+	}
+
+	for _, key := range keys {
+		address := crypto.PubkeyToAddress(key.PublicKey).Hex()
+		if senders[address] == 0 {
+			t.Errorf("signer %s sent no registrations (senders: %v)", address, senders)
+		}
+	}
+	for _, status := range CurrentStatus().Signers {
+		if status.InFlight != 0 || !status.Authorized || status.Balance == "" {
+			t.Errorf("signer status after burst = %+v", status)
+		}
+	}
+}
+
+func TestSignerPoolSkipsRevokedRegistrar(t *testing.T) {
+	chain, keys := newTestPool(t, 2)
+	ctx := testContext(t)
+	revoked := crypto.PubkeyToAddress(keys[0].PublicKey)
+
+	// Revoke the role on chain behind the pool's back
+	revokeRegistrar(t, chain, revoked)
+
+	for i := 0; i < 3; i++ {
+		hashHex, _ := testHash(fmt.Sprintf("after revoke %d", i))
+		var from string
+		_, err := RegisterRecipeOnChain(ctx, hashHex, common.Address{0xc1, byte(i)}.Hex(), func(sub SubmittedTx) { from = sub.From })
+		if err != nil {
+			t.Fatalf("registration %d: %v", i, err)
+		}
+		if from == revoked.Hex() {
+			t.Errorf("registration %d sent from revoked registrar", i)
+		}
+	}
+	for _, status := range CurrentStatus().Signers {
+		if status.Address == revoked.Hex() && status.Authorized {
+			t.Errorf("revoked registrar still marked authorized")
+		}
+	}
+}
+
+func TestSignerPoolLastRevokedRegistrarIsRetryable(t *testing.T) {
+	chain, keys := newTestPool(t, 1)
+	ctx := testContext(t)
+	revoked := crypto.PubkeyToAddress(keys[0].PublicKey)
+	revokeRegistrar(t, chain, revoked)
+
+	hashHex, _ := testHash("no registrar left")
+	_, err := RegisterRecipeOnChain(ctx, hashHex, common.Address{0xc3}.Hex(), nil)
+	if !errors.Is(err, ErrNoSignerAvailable) || !errors.Is(err, ErrNotRegistrar) {
+		t.Fatalf("error = %v, want ErrNoSignerAvailable caused by ErrNotRegistrar", err)
+	}
+	if !IsRetryable(err) {
+		t.Errorf("IsRetryable(%v) = false", err)
+	}
+
+	// Granting the role again brings the signer back on the next refresh
+	if err := chain.GrantRegistrars(revoked); err != nil {
+		t.Fatalf("GrantRegistrars: %v", err)
+	}
+	signers.refresh(ctx)
+	if _, err := RegisterRecipeOnChain(ctx, hashHex, common.Address{0xc3}.Hex(), nil); err != nil {
+		t.Fatalf("registration after granting the role again: %v", err)
+	}
+}
+
+func TestSignerPoolPausesLowBalance(t *testing.T) {
+	// Every simulated account starts with 1000 ETH
+	t.Setenv("SIGNER_MIN_BALANCE_ETH", "5000")
+	newTestPool(t, 2)
+	hashHex, _ := testHash("no funds")
+
+	_, err := RegisterRecipeOnChain(testContext(t), hashHex, common.Address{0xc2}.Hex(), nil)
+	if !errors.Is(err, ErrNoSignerAvailable) {
+		t.Fatalf("error = %v, want ErrNoSignerAvailable", err)
+	}
+	for _, status := range CurrentStatus().Signers {
+		if !status.LowBalance {
+			t.Errorf("signer %s not reported as low balance", status.Address)
+		}
+	}
+}
+
+// revokeRegistrar takes the registrar role away from account on chain.
+func revokeRegistrar(t *testing.T, chain *SimulatedChain, account common.Address) {
+	t.Helper()
+	contract, err := contracts.NewRecipeRegistry(chain.Contract, chain.Backend.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, err := bind.NewKeyedTransactorWithChainID(chain.Owner, chainIDValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := contract.SetRegistrar(owner, account, false); err != nil {
+		t.Fatalf("SetRegistrar: %v", err)
+	}
+	chain.Backend.Commit()
+}
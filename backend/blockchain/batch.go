@@ -0,0 +1,88 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BatchRecord is what the RecipeRegistry contract stores for a batch Merkle root.
+type BatchRecord struct {
+	Root      common.Hash
+	Timestamp time.Time // Block timestamp of the anchoring, zero if never anchored
+}
+
+// Anchored reports whether the root was anchored on chain.
+func (r *BatchRecord) Anchored() bool {
+	return !r.Timestamp.IsZero()
+}
+
+// AnchorBatchOnChain anchors the Merkle root of a batch of recipe hashes with a
+// single anchorBatch transaction. onSubmitted and the blocking behaviour are the
+// same as for RegisterRecipeOnChain.
+func AnchorBatchOnChain(ctx context.Context, root common.Hash, leafCount int, onSubmitted func(SubmittedTx)) (*Registration, error) {
+	if err := ready(); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Attempting to anchor batch root %s (%d recipes) on chain", root.Hex(), leafCount)
+
+	callData, build, err := anchorBatchCall(root, leafCount)
+	if err != nil {
+		return nil, err
+	}
+	return submitTransaction(ctx, callData, build, onSubmitted)
+}
+
+// ResumeBatchAnchor is AnchorBatchOnChain for a batch whose earlier attempt already
+// broadcast the transactions in previous. Like ResumeRecipeRegistration, it waits
+// for, speeds up or re-sends those rather than sending a second anchorBatch, and
+// only anchors the root from scratch if their nonce went to another transaction.
+func ResumeBatchAnchor(ctx context.Context, root common.Hash, leafCount int, previous []SubmittedTx, onSubmitted func(SubmittedTx)) (*Registration, error) {
+	if len(previous) == 0 {
+		return AnchorBatchOnChain(ctx, root, leafCount, onSubmitted)
+	}
+	if err := ready(); err != nil {
+		return nil, err
+	}
+
+	callData, build, err := anchorBatchCall(root, leafCount)
+	if err != nil {
+		return nil, err
+	}
+	reg, resumed, err := resumeTransaction(ctx, callData, build, previous, onSubmitted)
+	if resumed || err != nil {
+		return reg, err
+	}
+	log.Printf("Previous transactions for batch root %s can no longer be mined, anchoring it again", root.Hex())
+	return submitTransaction(ctx, callData, build, onSubmitted)
+}
+
+// anchorBatchCall validates the arguments of anchorBatch and returns its call data
+// and a builder for the transaction, as submitTransaction takes them.
+func anchorBatchCall(root common.Hash, leafCount int) ([]byte, func(opts *bind.TransactOpts) (*types.Transaction, error), error) {
+	if root == (common.Hash{}) || leafCount <= 0 {
+		return nil, nil, fmt.Errorf("%w: empty batch", ErrInvalidRegistration)
+	}
+	callData, err := contractABI.Pack("anchorBatch", root, new(big.Int).SetUint64(uint64(leafCount)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to pack data for anchorBatch: %w", err)
+	}
+	return callData, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return registry.AnchorBatch(opts, root, uint64(leafCount))
+	}, nil
+}
+
+// GetBatchRecord reads batchRoots for a batch Merkle root from the contract.
+func GetBatchRecord(ctx context.Context, root common.Hash) (*BatchRecord, error) {
+	if err := ready(); err != nil {
+		return nil, err
+	}
+	return registry.GetBatchRecord(ctx, root)
+}
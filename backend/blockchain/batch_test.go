@@ -0,0 +1,153 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"proofpot-backend/merkle"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func TestAnchorBatchOnChain(t *testing.T) {
+	newTestChain(t, nil)
+	ctx := testContext(t)
+
+	var hashes [][32]byte
+	var creators []common.Address
+	var leaves []common.Hash
+	for i := 0; i < 5; i++ {
+		_, hash := testHash(fmt.Sprintf("batched %d", i))
+		creator := common.Address{0xba, byte(i)}
+		hashes = append(hashes, hash)
+		creators = append(creators, creator)
+		leaves = append(leaves, merkle.Leaf(hash, creator))
+	}
+	tree, err := merkle.Build(leaves)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var submitted []SubmittedTx
+	reg, err := AnchorBatchOnChain(ctx, tree.Root(), tree.Len(), func(sub SubmittedTx) {
+		submitted = append(submitted, sub)
+	})
+	if err != nil {
+		t.Fatalf("AnchorBatchOnChain: %v", err)
+	}
+	if len(submitted) != 1 || submitted[0].Hash != reg.TxHash {
+		t.Fatalf("onSubmitted calls = %+v, want one call for %s", submitted, reg.TxHash)
+	}
+
+	record, err := GetBatchRecord(ctx, tree.Root())
+	if err != nil {
+		t.Fatalf("GetBatchRecord: %v", err)
+	}
+	if !record.Anchored() || !record.Timestamp.Equal(reg.BlockTime) {
+		t.Errorf("batch record = %+v, want anchored at %s", record, reg.BlockTime)
+	}
+
+	// Proofs built in Go verify on chain, and only for the creator they were built for
+	for i := range leaves {
+		proof, err := tree.Proof(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		siblings := make([][32]byte, len(proof))
+		for j, sibling := range proof {
+			siblings[j] = sibling
+		}
+		included, err := Registry().VerifyBatchInclusion(ctx, tree.Root(), hashes[i], creators[i], siblings)
+		if err != nil || !included {
+			t.Errorf("leaf %d: verifyBatchInclusion = %t, %v; want true", i, included, err)
+		}
+		included, err = Registry().VerifyBatchInclusion(ctx, tree.Root(), hashes[i], common.Address{0xee}, siblings)
+		if err != nil || included {
+			t.Errorf("leaf %d with another creator: verifyBatchInclusion = %t, %v; want false", i, included, err)
+		}
+	}
+
+	_, err = AnchorBatchOnChain(ctx, tree.Root(), tree.Len(), nil)
+	if !errors.Is(err, ErrBatchAlreadyAnchored) {
+		t.Fatalf("second anchoring error = %v, want ErrBatchAlreadyAnchored", err)
+	}
+	if IsRetryable(err) {
+		t.Errorf("re-anchoring reported as retryable")
+	}
+}
+
+func TestGetBatchRecordUnanchored(t *testing.T) {
+	newTestChain(t, nil)
+	_, root := testHash("never anchored")
+
+	record, err := GetBatchRecord(testContext(t), root)
+	if err != nil {
+		t.Fatalf("GetBatchRecord: %v", err)
+	}
+	if record.Anchored() {
+		t.Errorf("unanchored root reported as anchored at %s", record.Timestamp)
+	}
+}
+
+func TestResumeBatchAnchorWaitsForInterruptedTx(t *testing.T) {
+	chain := newTestChain(t, nil)
+	// Without auto-mining, the transaction stays pending until the test commits a block
+	if err := configure(chain.Backend.Client(), []Signer{NewKeySigner(chain.Owner)}, chain.Contract); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	replacementCfg.PollInterval = 10 * time.Millisecond
+	ctx := testContext(t)
+	_, hash := testHash("interrupted batch")
+	root := merkle.Leaf(hash, common.Address{0xba})
+
+	// The attempt is cancelled right after the broadcast, like on shutdown
+	attemptCtx, cancel := context.WithCancel(ctx)
+	var first SubmittedTx
+	_, err := AnchorBatchOnChain(attemptCtx, root, 1, func(sub SubmittedTx) {
+		first = sub
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("AnchorBatchOnChain = %v, want context.Canceled", err)
+	}
+
+	type result struct {
+		reg *Registration
+		err error
+	}
+	var sent []SubmittedTx
+	done := make(chan result)
+	go func() {
+		reg, err := ResumeBatchAnchor(ctx, root, 1, []SubmittedTx{first}, func(sub SubmittedTx) {
+			sent = append(sent, sub)
+		})
+		done <- result{reg, err}
+	}()
+	var res result
+	for mined := false; !mined; {
+		select {
+		case res = <-done:
+			mined = true
+		case <-time.After(20 * time.Millisecond):
+			chain.Backend.Commit()
+		}
+	}
+
+	if res.err != nil {
+		t.Fatalf("ResumeBatchAnchor: %v", res.err)
+	}
+	if res.reg.TxHash != first.Hash || len(sent) != 0 {
+		t.Errorf("resumed anchoring mined %s and sent %d transactions, want %s and none", res.reg.TxHash, len(sent), first.Hash)
+	}
+	owner := crypto.PubkeyToAddress(chain.Owner.PublicKey)
+	if nonce, err := ethClient.NonceAt(ctx, owner, nil); err != nil || nonce != first.Nonce+1 {
+		t.Errorf("owner nonce = %d, %v, want %d (one anchorBatch)", nonce, err, first.Nonce+1)
+	}
+	if record, err := GetBatchRecord(ctx, root); err != nil || !record.Anchored() {
+		t.Errorf("GetBatchRecord = %+v, %v, want anchored", record, err)
+	}
+}
@@ -0,0 +1,227 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrChainConfig marks InitBlockchain failures caused by the configuration
+	// (missing variables, malformed key, bad fee caps). Retrying cannot fix those.
+	ErrChainConfig = errors.New("invalid blockchain configuration")
+	// ErrChainUnavailable is returned while the backend has no chain connection.
+	ErrChainUnavailable = errors.New("blockchain not connected")
+)
+
+// ConnectionConfig controls reconnection and health checks. Values are read from
+// the environment by LoadConnectionConfig.
+type ConnectionConfig struct {
+	BaseBackoff    time.Duration // Delay before the first reconnect attempt, doubled on every failure
+	MaxBackoff     time.Duration // Cap for the reconnect backoff (CHAIN_RECONNECT_MAX_SECONDS)
+	HealthInterval time.Duration // How often a connected chain is probed (CHAIN_HEALTH_INTERVAL_SECONDS)
+}
+
+const (
+	defaultReconnectBackoff    = 2 * time.Second
+	defaultMaxReconnectBackoff = 5 * time.Minute
+	defaultHealthInterval      = 30 * time.Second
+	healthProbeTimeout         = 10 * time.Second
+)
+
+// LoadConnectionConfig reads the reconnect and health check settings from the environment.
+func LoadConnectionConfig() ConnectionConfig {
+	cfg := ConnectionConfig{
+		BaseBackoff:    defaultReconnectBackoff,
+		MaxBackoff:     defaultMaxReconnectBackoff,
+		HealthInterval: defaultHealthInterval,
+	}
+	if secs, ok := envSeconds("CHAIN_RECONNECT_MAX_SECONDS"); ok {
+		cfg.MaxBackoff = secs
+	}
+	if secs, ok := envSeconds("CHAIN_HEALTH_INTERVAL_SECONDS"); ok {
+		cfg.HealthInterval = secs
+	}
+	return cfg
+}
+
+func envSeconds(key string) (time.Duration, bool) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return 0, false
+	}
+	secs, err := strconv.Atoi(raw)
+	if err != nil || secs <= 0 {
+		log.Printf("Warning: invalid %s=%q, using default", key, raw)
+		return 0, false
+	}
+	return time.Duration(secs) * time.Second, true
+}
+
+// Status describes the backend's view of the chain, as reported by the health endpoint.
+type Status struct {
+	Connected       bool           `json:"connected"`
+	Backend         string         `json:"backend"`
+	ContractAddress string         `json:"contractAddress,omitempty"`
+	LatestBlock     *uint64        `json:"latestBlock,omitempty"`
+	LastCheckedAt   *time.Time     `json:"lastCheckedAt,omitempty"`
+	ConnectedSince  *time.Time     `json:"connectedSince,omitempty"`
+	LastError       string         `json:"lastError,omitempty"`
+	Signers         []SignerStatus `json:"signers,omitempty"`
+}
+
+// SignersAvailable reports whether at least one signer account can send registrations.
+func (s Status) SignersAvailable() bool {
+	for _, signer := range s.Signers {
+		if signer.Authorized && !signer.LowBalance {
+			return true
+		}
+	}
+	return false
+}
+
+// connection tracks whether InitBlockchain has succeeded and whether the chain
+// answered the last health probe. The package globals are written only before
+// initialized is set, so readers that observe initialized under mu also see them.
+var connection struct {
+	mu             sync.RWMutex
+	initialized    bool
+	healthy        bool
+	latestBlock    *uint64
+	lastCheckedAt  *time.Time
+	connectedSince *time.Time
+	lastError      string
+}
+
+// markInitialized records a successful InitBlockchain.
+func markInitialized() {
+	now := time.Now().UTC()
+	connection.mu.Lock()
+	defer connection.mu.Unlock()
+	connection.initialized = true
+	connection.healthy = true
+	connection.lastCheckedAt = &now
+	connection.connectedSince = &now
+	connection.lastError = ""
+}
+
+// recordCheck records the outcome of a connection attempt or health probe.
+func recordCheck(latestBlock uint64, err error) {
+	now := time.Now().UTC()
+	connection.mu.Lock()
+	defer connection.mu.Unlock()
+	connection.lastCheckedAt = &now
+	if err != nil {
+		if connection.healthy {
+			connection.connectedSince = nil
+		}
+		connection.healthy = false
+		connection.lastError = err.Error()
+		return
+	}
+	if !connection.healthy {
+		connection.connectedSince = &now
+	}
+	connection.healthy = true
+	connection.latestBlock = &latestBlock
+	connection.lastError = ""
+}
+
+// ready returns ErrChainUnavailable until InitBlockchain has succeeded.
+func ready() error {
+	connection.mu.RLock()
+	defer connection.mu.RUnlock()
+	if !connection.initialized {
+		return ErrChainUnavailable
+	}
+	return nil
+}
+
+// Connected reports whether the chain is set up and answered the last health probe.
+// Background jobs that need the chain wait for this instead of failing attempts.
+func Connected() bool {
+	connection.mu.RLock()
+	defer connection.mu.RUnlock()
+	return connection.initialized && connection.healthy
+}
+
+// CurrentStatus returns a snapshot of the chain connection state.
+func CurrentStatus() Status {
+	connection.mu.RLock()
+	defer connection.mu.RUnlock()
+
+	status := Status{
+		Connected:      connection.initialized && connection.healthy,
+		Backend:        os.Getenv("CHAIN_BACKEND"),
+		LatestBlock:    connection.latestBlock,
+		LastCheckedAt:  connection.lastCheckedAt,
+		ConnectedSince: connection.connectedSince,
+		LastError:      connection.lastError,
+	}
+	if status.Backend == "" {
+		status.Backend = "rpc"
+	}
+	if connection.initialized {
+		status.ContractAddress = contractAddress.Hex()
+		status.Signers = signers.statuses()
+	}
+	return status
+}
+
+// KeepConnected runs until ctx is cancelled. While the chain is not set up it
+// retries InitBlockchain with exponential backoff; once it is, it probes the
+// node every HealthInterval so Connected and CurrentStatus stay current.
+func KeepConnected(ctx context.Context, cfg ConnectionConfig) {
+	backoff := cfg.BaseBackoff
+	for {
+		delay := cfg.HealthInterval
+		if ready() != nil {
+			if err := InitBlockchain(); err != nil {
+				recordCheck(0, err)
+				log.Printf("Blockchain still unavailable, retrying in %s: %v", backoff, err)
+				delay = backoff
+				backoff = min(backoff*2, cfg.MaxBackoff)
+			} else {
+				log.Println("Blockchain connection established, leaving degraded mode")
+				backoff = cfg.BaseBackoff
+			}
+		} else {
+			probe(ctx)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+	}
+}
+
+// probe checks that the node still answers and records the head block.
+func probe(ctx context.Context) {
+	wasHealthy := Connected()
+	probeCtx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	head, err := ethClient.BlockNumber(probeCtx)
+	if err != nil {
+		err = fmt.Errorf("health check failed: %w", err)
+	}
+	recordCheck(head, err)
+	if err == nil {
+		// Balances and registrar roles change outside our control
+		signers.refresh(probeCtx)
+	}
+
+	switch {
+	case wasHealthy && err != nil:
+		log.Printf("WARNING: Lost connection to the blockchain: %v", err)
+	case !wasHealthy && err == nil:
+		log.Printf("Blockchain connection restored at block %d", head)
+	}
+}
@@ -0,0 +1,261 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"proofpot-backend/blockchain/contracts"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RecipeRegistry is what the backend needs from the RecipeRegistry contract.
+// The default implementation wraps the abigen bindings in the contracts package,
+// so a change to RecipeRegistry.sol that is not re-exported fails to compile
+// instead of drifting from a hand-maintained ABI.
+type RecipeRegistry interface {
+	// Address returns the deployed contract address.
+	Address() common.Address
+	// Owner returns the account allowed to call addRecipe.
+	Owner(ctx context.Context) (common.Address, error)
+	// GetRecipeRecord reads recipeOwners and recipeTimestamps for a content hash.
+	GetRecipeRecord(ctx context.Context, recipeHash [32]byte) (*RecipeRecord, error)
+	// AddRecipe builds, signs and (unless opts.NoSend is set) sends an addRecipe transaction.
+	AddRecipe(opts *bind.TransactOpts, recipeHash [32]byte, creator common.Address) (*types.Transaction, error)
+	// TransferOwnership builds, signs and (unless opts.NoSend is set) sends a transferOwnership transaction.
+	TransferOwnership(opts *bind.TransactOpts, newOwner common.Address) (*types.Transaction, error)
+	// IsRegistrar reports whether account holds the registrar role (the owner does not need it).
+	IsRegistrar(ctx context.Context, account common.Address) (bool, error)
+	// SetRegistrar builds, signs and (unless opts.NoSend is set) sends a setRegistrar transaction.
+	SetRegistrar(opts *bind.TransactOpts, account common.Address, authorized bool) (*types.Transaction, error)
+	// AnchorBatch builds, signs and (unless opts.NoSend is set) sends an anchorBatch transaction.
+	AnchorBatch(opts *bind.TransactOpts, root [32]byte, leafCount uint64) (*types.Transaction, error)
+	// GetBatchRecord reads batchRoots for a batch Merkle root.
+	GetBatchRecord(ctx context.Context, root [32]byte) (*BatchRecord, error)
+	// VerifyBatchInclusion asks the contract whether proof places the recipe in an anchored batch.
+	VerifyBatchInclusion(ctx context.Context, root, recipeHash [32]byte, creator common.Address, proof [][32]byte) (bool, error)
+	// FilterRecipeAdded returns the RecipeAdded events emitted in the inclusive block range.
+	FilterRecipeAdded(ctx context.Context, fromBlock, toBlock uint64) ([]RecipeAddedEvent, error)
+}
+
+// boundRegistry implements RecipeRegistry on top of the generated bindings.
+type boundRegistry struct {
+	address  common.Address
+	contract *contracts.RecipeRegistry
+}
+
+// NewRecipeRegistry binds the RecipeRegistry deployed at address.
+func NewRecipeRegistry(address common.Address, backend bind.ContractBackend) (RecipeRegistry, error) {
+	contract, err := contracts.NewRecipeRegistry(address, backend)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind RecipeRegistry at %s: %w", address.Hex(), err)
+	}
+	return &boundRegistry{address: address, contract: contract}, nil
+}
+
+func (r *boundRegistry) Address() common.Address {
+	return r.address
+}
+
+func (r *boundRegistry) Owner(ctx context.Context) (common.Address, error) {
+	owner, err := r.contract.Owner(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to call owner: %w", err)
+	}
+	return owner, nil
+}
+
+func (r *boundRegistry) GetRecipeRecord(ctx context.Context, recipeHash [32]byte) (*RecipeRecord, error) {
+	opts := &bind.CallOpts{Context: ctx}
+	creator, err := r.contract.RecipeOwners(opts, recipeHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call recipeOwners: %w", err)
+	}
+	timestamp, err := r.contract.RecipeTimestamps(opts, recipeHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call recipeTimestamps: %w", err)
+	}
+	return &RecipeRecord{
+		Creator:   creator,
+		Timestamp: time.Unix(timestamp.Int64(), 0).UTC(),
+	}, nil
+}
+
+func (r *boundRegistry) AddRecipe(opts *bind.TransactOpts, recipeHash [32]byte, creator common.Address) (*types.Transaction, error) {
+	return r.contract.AddRecipe(opts, recipeHash, creator)
+}
+
+func (r *boundRegistry) TransferOwnership(opts *bind.TransactOpts, newOwner common.Address) (*types.Transaction, error) {
+	return r.contract.TransferOwnership(opts, newOwner)
+}
+
+func (r *boundRegistry) IsRegistrar(ctx context.Context, account common.Address) (bool, error) {
+	authorized, err := r.contract.Registrars(&bind.CallOpts{Context: ctx}, account)
+	if err != nil {
+		return false, fmt.Errorf("failed to call registrars: %w", err)
+	}
+	return authorized, nil
+}
+
+func (r *boundRegistry) SetRegistrar(opts *bind.TransactOpts, account common.Address, authorized bool) (*types.Transaction, error) {
+	return r.contract.SetRegistrar(opts, account, authorized)
+}
+
+func (r *boundRegistry) AnchorBatch(opts *bind.TransactOpts, root [32]byte, leafCount uint64) (*types.Transaction, error) {
+	return r.contract.AnchorBatch(opts, root, new(big.Int).SetUint64(leafCount))
+}
+
+func (r *boundRegistry) GetBatchRecord(ctx context.Context, root [32]byte) (*BatchRecord, error) {
+	timestamp, err := r.contract.BatchRoots(&bind.CallOpts{Context: ctx}, root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call batchRoots: %w", err)
+	}
+	record := &BatchRecord{Root: root}
+	if timestamp.Sign() > 0 {
+		record.Timestamp = time.Unix(timestamp.Int64(), 0).UTC()
+	}
+	return record, nil
+}
+
+func (r *boundRegistry) VerifyBatchInclusion(ctx context.Context, root, recipeHash [32]byte, creator common.Address, proof [][32]byte) (bool, error) {
+	included, err := r.contract.VerifyBatchInclusion(&bind.CallOpts{Context: ctx}, root, recipeHash, creator, proof)
+	if err != nil {
+		return false, fmt.Errorf("failed to call verifyBatchInclusion: %w", err)
+	}
+	return included, nil
+}
+
+func (r *boundRegistry) FilterRecipeAdded(ctx context.Context, fromBlock, toBlock uint64) ([]RecipeAddedEvent, error) {
+	it, err := r.contract.FilterRecipeAdded(&bind.FilterOpts{Start: fromBlock, End: &toBlock, Context: ctx}, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to filter RecipeAdded logs: %w", err)
+	}
+	defer it.Close()
+
+	var events []RecipeAddedEvent
+	for it.Next() {
+		ev := it.Event
+		events = append(events, RecipeAddedEvent{
+			RecipeHash:  ev.RecipeHash,
+			Creator:     ev.Creator,
+			Timestamp:   time.Unix(ev.Timestamp.Int64(), 0).UTC(),
+			BlockNumber: ev.Raw.BlockNumber,
+			BlockHash:   ev.Raw.BlockHash,
+			TxHash:      ev.Raw.TxHash,
+			LogIndex:    ev.Raw.Index,
+		})
+	}
+	if err := it.Error(); err != nil {
+		return nil, fmt.Errorf("failed to decode RecipeAdded logs: %w", err)
+	}
+	return events, nil
+}
+
+// Registry returns the RecipeRegistry configured by InitBlockchain. The value can be
+// handed out before the chain is connected: until then (and after a failed start,
+// until a reconnect succeeds) its methods return ErrChainUnavailable.
+func Registry() RecipeRegistry {
+	return connectedRegistry{}
+}
+
+// connectedRegistry forwards to the registry set up by InitBlockchain.
+type connectedRegistry struct{}
+
+func (connectedRegistry) current() (RecipeRegistry, error) {
+	if err := ready(); err != nil {
+		return nil, err
+	}
+	return registry, nil
+}
+
+func (r connectedRegistry) Address() common.Address {
+	current, err := r.current()
+	if err != nil {
+		return common.Address{}
+	}
+	return current.Address()
+}
+
+func (r connectedRegistry) Owner(ctx context.Context) (common.Address, error) {
+	current, err := r.current()
+	if err != nil {
+		return common.Address{}, err
+	}
+	return current.Owner(ctx)
+}
+
+func (r connectedRegistry) GetRecipeRecord(ctx context.Context, recipeHash [32]byte) (*RecipeRecord, error) {
+	current, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return current.GetRecipeRecord(ctx, recipeHash)
+}
+
+func (r connectedRegistry) AddRecipe(opts *bind.TransactOpts, recipeHash [32]byte, creator common.Address) (*types.Transaction, error) {
+	current, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return current.AddRecipe(opts, recipeHash, creator)
+}
+
+func (r connectedRegistry) TransferOwnership(opts *bind.TransactOpts, newOwner common.Address) (*types.Transaction, error) {
+	current, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return current.TransferOwnership(opts, newOwner)
+}
+
+func (r connectedRegistry) IsRegistrar(ctx context.Context, account common.Address) (bool, error) {
+	current, err := r.current()
+	if err != nil {
+		return false, err
+	}
+	return current.IsRegistrar(ctx, account)
+}
+
+func (r connectedRegistry) SetRegistrar(opts *bind.TransactOpts, account common.Address, authorized bool) (*types.Transaction, error) {
+	current, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return current.SetRegistrar(opts, account, authorized)
+}
+
+func (r connectedRegistry) AnchorBatch(opts *bind.TransactOpts, root [32]byte, leafCount uint64) (*types.Transaction, error) {
+	current, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return current.AnchorBatch(opts, root, leafCount)
+}
+
+func (r connectedRegistry) GetBatchRecord(ctx context.Context, root [32]byte) (*BatchRecord, error) {
+	current, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return current.GetBatchRecord(ctx, root)
+}
+
+func (r connectedRegistry) VerifyBatchInclusion(ctx context.Context, root, recipeHash [32]byte, creator common.Address, proof [][32]byte) (bool, error) {
+	current, err := r.current()
+	if err != nil {
+		return false, err
+	}
+	return current.VerifyBatchInclusion(ctx, root, recipeHash, creator, proof)
+}
+
+func (r connectedRegistry) FilterRecipeAdded(ctx context.Context, fromBlock, toBlock uint64) ([]RecipeAddedEvent, error) {
+	current, err := r.current()
+	if err != nil {
+		return nil, err
+	}
+	return current.FilterRecipeAdded(ctx, fromBlock, toBlock)
+}
@@ -0,0 +1,13 @@
+// Package contracts holds the Go bindings for the ProofPot smart contracts.
+//
+// The bindings are generated by abigen from the ABI and bytecode exported from
+// the Hardhat build (see smart-contract/scripts/export-abi.ts). To refresh them
+// after changing RecipeRegistry.sol:
+//
+//	cd smart-contract && npx hardhat compile && npx hardhat run scripts/export-abi.ts
+//	cd ../backend && go generate ./blockchain/contracts
+//
+// Do not edit recipe_registry.go by hand.
+package contracts
+
+//go:generate go run github.com/ethereum/go-ethereum/cmd/abigen@v1.15.7 --abi RecipeRegistry.abi --bin RecipeRegistry.bin --pkg contracts --type RecipeRegistry --out recipe_registry.go
@@ -0,0 +1,1092 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package contracts
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// RecipeRegistryMetaData contains all meta data concerning the RecipeRegistry contract.
+var RecipeRegistryMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[{\"internalType\":\"address\",\"name\":\"initialOwner\",\"type\":\"address\"}],\"stateMutability\":\"nonpayable\",\"type\":\"constructor\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"}],\"name\":\"OwnableInvalidOwner\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"OwnableUnauthorizedAccount\",\"type\":\"error\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"}],\"name\":\"UnauthorizedRegistrar\",\"type\":\"error\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"root\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"leafCount\",\"type\":\"uint256\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"}],\"name\":\"BatchAnchored\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"previousOwner\",\"type\":\"address\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"newOwner\",\"type\":\"address\"}],\"name\":\"OwnershipTransferred\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"recipeHash\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"creator\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"timestamp\",\"type\":\"uint256\"}],\"name\":\"RecipeAdded\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":true,\"internalType\":\"address\",\"name\":\"account\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"bool\",\"name\":\"authorized\",\"type\":\"bool\"}],\"name\":\"RegistrarUpdated\",\"type\":\"event\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_recipeHash\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"_creator\",\"type\":\"address\"}],\"name\":\"addRecipe\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_root\",\"type\":\"bytes32\"},{\"internalType\":\"uint256\",\"name\":\"_leafCount\",\"type\":\"uint256\"}],\"name\":\"anchorBatch\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"name\":\"batchRoots\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"owner\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"name\":\"recipeOwners\",\"outputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"\",\"type\":\"bytes32\"}],\"name\":\"recipeTimestamps\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"\",\"type\":\"address\"}],\"name\":\"registrars\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[],\"name\":\"renounceOwnership\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"_account\",\"type\":\"address\"},{\"internalType\":\"bool\",\"name\":\"_authorized\",\"type\":\"bool\"}],\"name\":\"setRegistrar\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"address\",\"name\":\"newOwner\",\"type\":\"address\"}],\"name\":\"transferOwnership\",\"outputs\":[],\"stateMutability\":\"nonpayable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"bytes32\",\"name\":\"_root\",\"type\":\"bytes32\"},{\"internalType\":\"bytes32\",\"name\":\"_recipeHash\",\"type\":\"bytes32\"},{\"internalType\":\"address\",\"name\":\"_creator\",\"type\":\"address\"},{\"internalType\":\"bytes32[]\",\"name\":\"_proof\",\"type\":\"bytes32[]\"}],\"name\":\"verifyBatchInclusion\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"}]",
+	Bin: "0x608060405234801561000f575f5ffd5b50604051611412380380611412833981810160405281019061003191906101d7565b805f73ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff16036100a2575f6040517f1e4fbdf70000000000000000000000000000000000000000000000000000000081526004016100999190610211565b60405180910390fd5b6100b1816100b860201b60201c565b505061022a565b5f5f5f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff169050815f5f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055508173ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff167f8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e060405160405180910390a35050565b5f5ffd5b5f73ffffffffffffffffffffffffffffffffffffffff82169050919050565b5f6101a68261017d565b9050919050565b6101b68161019c565b81146101c0575f5ffd5b50565b5f815190506101d1816101ad565b92915050565b5f602082840312156101ec576101eb610179565b5b5f6101f9848285016101c3565b91505092915050565b61020b8161019c565b82525050565b5f6020820190506102245f830184610202565b92915050565b6111db806102375f395ff3fe608060405234801561000f575f5ffd5b50600436106100a7575f3560e01c806396ac15431161006f57806396ac1543146101635780639a1d0d4114610193578063c1bf1dcf146101af578063d5cc4ae9146101cb578063e7a315a4146101e7578063f2fde38b14610217576100a7565b806348b5b1e9146100ab57806367832467146100db578063715018a61461010b57806389aeca76146101155780638da5cb5b14610145575b5f5ffd5b6100c560048036038101906100c09190610c0a565b610233565b6040516100d29190610ca8565b60405180910390f35b6100f560048036038101906100f09190610cc1565b61032d565b6040516101029190610d04565b60405180910390f35b610113610342565b005b61012f600480360381019061012a9190610d1d565b610355565b60405161013c9190610ca8565b60405180910390f35b61014d610372565b60405161015a9190610d57565b60405180910390f35b61017d60048036038101906101789190610cc1565b610399565b60405161018a9190610d57565b60405180910390f35b6101ad60048036038101906101a89190610d9a565b6103c9565b005b6101c960048036038101906101c49190610e02565b610580565b005b6101e560048036038101906101e09190610e40565b61069c565b005b61020160048036038101906101fc9190610cc1565b61092c565b60405161020e9190610d04565b60405180910390f35b610231600480360381019061022c9190610d1d565b610941565b005b5f5f60045f8881526020019081526020015f205403610254575f9050610324565b5f8585604051602001610268929190610ee3565b6040516020818303038152906040528051906020012090505f5f90505b8484905081101561031c575f8585838181106102a4576102a3610f0e565b5b9050602002013590508083106102e25780836040516020016102c7929190610f3b565b6040516020818303038152906040528051906020012061030c565b82816040516020016102f5929190610f3b565b604051602081830303815290604052805190602001205b9250508080600101915050610285565b508681149150505b95945050505050565b6002602052805f5260405f205f915090505481565b61034a6109c5565b6103535f610a4c565b565b6003602052805f5260405f205f915054906101000a900460ff1681565b5f5f5f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff16905090565b6001602052805f5260405f205f915054906101000a900473ffffffffffffffffffffffffffffffffffffffff1681565b6103d1610372565b73ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff1614158015610453575060035f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f9054906101000a900460ff16155b1561049557336040517f6cba12b700000000000000000000000000000000000000000000000000000000815260040161048c9190610d57565b60405180910390fd5b5f5f1b82036104d9576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016104d090610fc0565b60405180910390fd5b5f60045f8481526020019081526020015f20541461052c576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161052390611028565b60405180910390fd5b4260045f8481526020019081526020015f2081905550817f569739a8a132d28586d65d35b6a86902bf0adbce83a4651378ff888d03b702558242604051610574929190611046565b60405180910390a25050565b6105886109c5565b5f73ffffffffffffffffffffffffffffffffffffffff168273ffffffffffffffffffffffffffffffffffffffff16036105f6576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016105ed906110b7565b60405180910390fd5b8060035f8473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f6101000a81548160ff0219169083151502179055508173ffffffffffffffffffffffffffffffffffffffff167faa29e9c7319872059973646686bcb05675a1351529119ca980b4ad90e73d7264826040516106909190610ca8565b60405180910390a25050565b6106a4610372565b73ffffffffffffffffffffffffffffffffffffffff163373ffffffffffffffffffffffffffffffffffffffff1614158015610726575060035f3373ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1681526020019081526020015f205f9054906101000a900460ff16155b1561076857336040517f6cba12b700000000000000000000000000000000000000000000000000000000815260040161075f9190610d57565b60405180910390fd5b5f73ffffffffffffffffffffffffffffffffffffffff1660015f8481526020019081526020015f205f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff1673ffffffffffffffffffffffffffffffffffffffff1614610806576040517f08c379a00000000000000000000000000000000000000000000000000000000081526004016107fd9061111f565b60405180910390fd5b5f73ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff1603610874576040517f08c379a000000000000000000000000000000000000000000000000000000000815260040161086b90611187565b60405180910390fd5b8060015f8481526020019081526020015f205f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055504260025f8481526020019081526020015f20819055508073ffffffffffffffffffffffffffffffffffffffff16827f9203c64ea296ec5884d25bb426d6d8bfa5a22b30c86d789cf95f241f4403263a426040516109209190610d04565b60405180910390a35050565b6004602052805f5260405f205f915090505481565b6109496109c5565b5f73ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff16036109b9575f6040517f1e4fbdf70000000000000000000000000000000000000000000000000000000081526004016109b09190610d57565b60405180910390fd5b6109c281610a4c565b50565b6109cd610b0d565b73ffffffffffffffffffffffffffffffffffffffff166109eb610372565b73ffffffffffffffffffffffffffffffffffffffff1614610a4a57610a0e610b0d565b6040517f118cdaa7000000000000000000000000000000000000000000000000000000008152600401610a419190610d57565b60405180910390fd5b565b5f5f5f9054906101000a900473ffffffffffffffffffffffffffffffffffffffff169050815f5f6101000a81548173ffffffffffffffffffffffffffffffffffffffff021916908373ffffffffffffffffffffffffffffffffffffffff1602179055508173ffffffffffffffffffffffffffffffffffffffff168173ffffffffffffffffffffffffffffffffffffffff167f8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e060405160405180910390a35050565b5f33905090565b5f5ffd5b5f5ffd5b5f819050919050565b610b2e81610b1c565b8114610b38575f5ffd5b50565b5f81359050610b4981610b25565b92915050565b5f73ffffffffffffffffffffffffffffffffffffffff82169050919050565b5f610b7882610b4f565b9050919050565b610b8881610b6e565b8114610b92575f5ffd5b50565b5f81359050610ba381610b7f565b92915050565b5f5ffd5b5f5ffd5b5f5ffd5b5f5f83601f840112610bca57610bc9610ba9565b5b8235905067ffffffffffffffff811115610be757610be6610bad565b5b602083019150836020820283011115610c0357610c02610bb1565b5b9250929050565b5f5f5f5f5f60808688031215610c2357610c22610b14565b5b5f610c3088828901610b3b565b9550506020610c4188828901610b3b565b9450506040610c5288828901610b95565b935050606086013567ffffffffffffffff811115610c7357610c72610b18565b5b610c7f88828901610bb5565b92509250509295509295909350565b5f8115159050919050565b610ca281610c8e565b82525050565b5f602082019050610cbb5f830184610c99565b92915050565b5f60208284031215610cd657610cd5610b14565b5b5f610ce384828501610b3b565b91505092915050565b5f819050919050565b610cfe81610cec565b82525050565b5f602082019050610d175f830184610cf5565b92915050565b5f60208284031215610d3257610d31610b14565b5b5f610d3f84828501610b95565b91505092915050565b610d5181610b6e565b82525050565b5f602082019050610d6a5f830184610d48565b92915050565b610d7981610cec565b8114610d83575f5ffd5b50565b5f81359050610d9481610d70565b92915050565b5f5f60408385031215610db057610daf610b14565b5b5f610dbd85828601610b3b565b9250506020610dce85828601610d86565b9150509250929050565b610de181610c8e565b8114610deb575f5ffd5b50565b5f81359050610dfc81610dd8565b92915050565b5f5f60408385031215610e1857610e17610b14565b5b5f610e2585828601610b95565b9250506020610e3685828601610dee565b9150509250929050565b5f5f60408385031215610e5657610e55610b14565b5b5f610e6385828601610b3b565b9250506020610e7485828601610b95565b9150509250929050565b5f819050919050565b610e98610e9382610b1c565b610e7e565b82525050565b5f8160601b9050919050565b5f610eb482610e9e565b9050919050565b5f610ec582610eaa565b9050919050565b610edd610ed882610b6e565b610ebb565b82525050565b5f610eee8285610e87565b602082019150610efe8284610ecc565b6014820191508190509392505050565b7f4e487b71000000000000000000000000000000000000000000000000000000005f52603260045260245ffd5b5f610f468285610e87565b602082019150610f568284610e87565b6020820191508190509392505050565b5f82825260208201905092915050565b7f426174636820726f6f742063616e6e6f74206265207a65726f000000000000005f82015250565b5f610faa601983610f66565b9150610fb582610f76565b602082019050919050565b5f6020820190508181035f830152610fd781610f9e565b9050919050565b7f426174636820726f6f7420616c726561647920616e63686f72656400000000005f82015250565b5f611012601b83610f66565b915061101d82610fde565b602082019050919050565b5f6020820190508181035f83015261103f81611006565b9050919050565b5f6040820190506110595f830185610cf5565b6110666020830184610cf5565b9392505050565b7f52656769737472617220616464726573732063616e6e6f74206265207a65726f5f82015250565b5f6110a1602083610f66565b91506110ac8261106d565b602082019050919050565b5f6020820190508181035f8301526110ce81611095565b9050919050565b7f526563697065206861736820616c7265616479206578697374730000000000005f82015250565b5f611109601a83610f66565b9150611114826110d5565b602082019050919050565b5f6020820190508181035f830152611136816110fd565b9050919050565b7f43726561746f7220616464726573732063616e6e6f74206265207a65726f00005f82015250565b5f611171601e83610f66565b915061117c8261113d565b602082019050919050565b5f6020820190508181035f83015261119e81611165565b905091905056fea26469706673582212200688cee10ccab8488265c216e0e4efe6e7c2f7dfaeb60a1cba06e37d275f7cf864736f6c634300081e0033",
+}
+
+// RecipeRegistryABI is the input ABI used to generate the binding from.
+// Deprecated: Use RecipeRegistryMetaData.ABI instead.
+var RecipeRegistryABI = RecipeRegistryMetaData.ABI
+
+// RecipeRegistryBin is the compiled bytecode used for deploying new contracts.
+// Deprecated: Use RecipeRegistryMetaData.Bin instead.
+var RecipeRegistryBin = RecipeRegistryMetaData.Bin
+
+// DeployRecipeRegistry deploys a new Ethereum contract, binding an instance of RecipeRegistry to it.
+func DeployRecipeRegistry(auth *bind.TransactOpts, backend bind.ContractBackend, initialOwner common.Address) (common.Address, *types.Transaction, *RecipeRegistry, error) {
+	parsed, err := RecipeRegistryMetaData.GetAbi()
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	if parsed == nil {
+		return common.Address{}, nil, nil, errors.New("GetABI returned nil")
+	}
+
+	address, tx, contract, err := bind.DeployContract(auth, *parsed, common.FromHex(RecipeRegistryBin), backend, initialOwner)
+	if err != nil {
+		return common.Address{}, nil, nil, err
+	}
+	return address, tx, &RecipeRegistry{RecipeRegistryCaller: RecipeRegistryCaller{contract: contract}, RecipeRegistryTransactor: RecipeRegistryTransactor{contract: contract}, RecipeRegistryFilterer: RecipeRegistryFilterer{contract: contract}}, nil
+}
+
+// RecipeRegistry is an auto generated Go binding around an Ethereum contract.
+type RecipeRegistry struct {
+	RecipeRegistryCaller     // Read-only binding to the contract
+	RecipeRegistryTransactor // Write-only binding to the contract
+	RecipeRegistryFilterer   // Log filterer for contract events
+}
+
+// RecipeRegistryCaller is an auto generated read-only Go binding around an Ethereum contract.
+type RecipeRegistryCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// RecipeRegistryTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type RecipeRegistryTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// RecipeRegistryFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type RecipeRegistryFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// RecipeRegistrySession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type RecipeRegistrySession struct {
+	Contract     *RecipeRegistry   // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// RecipeRegistryCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type RecipeRegistryCallerSession struct {
+	Contract *RecipeRegistryCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts         // Call options to use throughout this session
+}
+
+// RecipeRegistryTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type RecipeRegistryTransactorSession struct {
+	Contract     *RecipeRegistryTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts         // Transaction auth options to use throughout this session
+}
+
+// RecipeRegistryRaw is an auto generated low-level Go binding around an Ethereum contract.
+type RecipeRegistryRaw struct {
+	Contract *RecipeRegistry // Generic contract binding to access the raw methods on
+}
+
+// RecipeRegistryCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type RecipeRegistryCallerRaw struct {
+	Contract *RecipeRegistryCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// RecipeRegistryTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type RecipeRegistryTransactorRaw struct {
+	Contract *RecipeRegistryTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewRecipeRegistry creates a new instance of RecipeRegistry, bound to a specific deployed contract.
+func NewRecipeRegistry(address common.Address, backend bind.ContractBackend) (*RecipeRegistry, error) {
+	contract, err := bindRecipeRegistry(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &RecipeRegistry{RecipeRegistryCaller: RecipeRegistryCaller{contract: contract}, RecipeRegistryTransactor: RecipeRegistryTransactor{contract: contract}, RecipeRegistryFilterer: RecipeRegistryFilterer{contract: contract}}, nil
+}
+
+// NewRecipeRegistryCaller creates a new read-only instance of RecipeRegistry, bound to a specific deployed contract.
+func NewRecipeRegistryCaller(address common.Address, caller bind.ContractCaller) (*RecipeRegistryCaller, error) {
+	contract, err := bindRecipeRegistry(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &RecipeRegistryCaller{contract: contract}, nil
+}
+
+// NewRecipeRegistryTransactor creates a new write-only instance of RecipeRegistry, bound to a specific deployed contract.
+func NewRecipeRegistryTransactor(address common.Address, transactor bind.ContractTransactor) (*RecipeRegistryTransactor, error) {
+	contract, err := bindRecipeRegistry(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &RecipeRegistryTransactor{contract: contract}, nil
+}
+
+// NewRecipeRegistryFilterer creates a new log filterer instance of RecipeRegistry, bound to a specific deployed contract.
+func NewRecipeRegistryFilterer(address common.Address, filterer bind.ContractFilterer) (*RecipeRegistryFilterer, error) {
+	contract, err := bindRecipeRegistry(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &RecipeRegistryFilterer{contract: contract}, nil
+}
+
+// bindRecipeRegistry binds a generic wrapper to an already deployed contract.
+func bindRecipeRegistry(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := RecipeRegistryMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_RecipeRegistry *RecipeRegistryRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _RecipeRegistry.Contract.RecipeRegistryCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_RecipeRegistry *RecipeRegistryRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _RecipeRegistry.Contract.RecipeRegistryTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_RecipeRegistry *RecipeRegistryRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _RecipeRegistry.Contract.RecipeRegistryTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_RecipeRegistry *RecipeRegistryCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _RecipeRegistry.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_RecipeRegistry *RecipeRegistryTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _RecipeRegistry.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_RecipeRegistry *RecipeRegistryTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _RecipeRegistry.Contract.contract.Transact(opts, method, params...)
+}
+
+// BatchRoots is a free data retrieval call binding the contract method 0xe7a315a4.
+//
+// Solidity: function batchRoots(bytes32 ) view returns(uint256)
+func (_RecipeRegistry *RecipeRegistryCaller) BatchRoots(opts *bind.CallOpts, arg0 [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _RecipeRegistry.contract.Call(opts, &out, "batchRoots", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// BatchRoots is a free data retrieval call binding the contract method 0xe7a315a4.
+//
+// Solidity: function batchRoots(bytes32 ) view returns(uint256)
+func (_RecipeRegistry *RecipeRegistrySession) BatchRoots(arg0 [32]byte) (*big.Int, error) {
+	return _RecipeRegistry.Contract.BatchRoots(&_RecipeRegistry.CallOpts, arg0)
+}
+
+// BatchRoots is a free data retrieval call binding the contract method 0xe7a315a4.
+//
+// Solidity: function batchRoots(bytes32 ) view returns(uint256)
+func (_RecipeRegistry *RecipeRegistryCallerSession) BatchRoots(arg0 [32]byte) (*big.Int, error) {
+	return _RecipeRegistry.Contract.BatchRoots(&_RecipeRegistry.CallOpts, arg0)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_RecipeRegistry *RecipeRegistryCaller) Owner(opts *bind.CallOpts) (common.Address, error) {
+	var out []interface{}
+	err := _RecipeRegistry.contract.Call(opts, &out, "owner")
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_RecipeRegistry *RecipeRegistrySession) Owner() (common.Address, error) {
+	return _RecipeRegistry.Contract.Owner(&_RecipeRegistry.CallOpts)
+}
+
+// Owner is a free data retrieval call binding the contract method 0x8da5cb5b.
+//
+// Solidity: function owner() view returns(address)
+func (_RecipeRegistry *RecipeRegistryCallerSession) Owner() (common.Address, error) {
+	return _RecipeRegistry.Contract.Owner(&_RecipeRegistry.CallOpts)
+}
+
+// RecipeOwners is a free data retrieval call binding the contract method 0x96ac1543.
+//
+// Solidity: function recipeOwners(bytes32 ) view returns(address)
+func (_RecipeRegistry *RecipeRegistryCaller) RecipeOwners(opts *bind.CallOpts, arg0 [32]byte) (common.Address, error) {
+	var out []interface{}
+	err := _RecipeRegistry.contract.Call(opts, &out, "recipeOwners", arg0)
+
+	if err != nil {
+		return *new(common.Address), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(common.Address)).(*common.Address)
+
+	return out0, err
+
+}
+
+// RecipeOwners is a free data retrieval call binding the contract method 0x96ac1543.
+//
+// Solidity: function recipeOwners(bytes32 ) view returns(address)
+func (_RecipeRegistry *RecipeRegistrySession) RecipeOwners(arg0 [32]byte) (common.Address, error) {
+	return _RecipeRegistry.Contract.RecipeOwners(&_RecipeRegistry.CallOpts, arg0)
+}
+
+// RecipeOwners is a free data retrieval call binding the contract method 0x96ac1543.
+//
+// Solidity: function recipeOwners(bytes32 ) view returns(address)
+func (_RecipeRegistry *RecipeRegistryCallerSession) RecipeOwners(arg0 [32]byte) (common.Address, error) {
+	return _RecipeRegistry.Contract.RecipeOwners(&_RecipeRegistry.CallOpts, arg0)
+}
+
+// RecipeTimestamps is a free data retrieval call binding the contract method 0x67832467.
+//
+// Solidity: function recipeTimestamps(bytes32 ) view returns(uint256)
+func (_RecipeRegistry *RecipeRegistryCaller) RecipeTimestamps(opts *bind.CallOpts, arg0 [32]byte) (*big.Int, error) {
+	var out []interface{}
+	err := _RecipeRegistry.contract.Call(opts, &out, "recipeTimestamps", arg0)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// RecipeTimestamps is a free data retrieval call binding the contract method 0x67832467.
+//
+// Solidity: function recipeTimestamps(bytes32 ) view returns(uint256)
+func (_RecipeRegistry *RecipeRegistrySession) RecipeTimestamps(arg0 [32]byte) (*big.Int, error) {
+	return _RecipeRegistry.Contract.RecipeTimestamps(&_RecipeRegistry.CallOpts, arg0)
+}
+
+// RecipeTimestamps is a free data retrieval call binding the contract method 0x67832467.
+//
+// Solidity: function recipeTimestamps(bytes32 ) view returns(uint256)
+func (_RecipeRegistry *RecipeRegistryCallerSession) RecipeTimestamps(arg0 [32]byte) (*big.Int, error) {
+	return _RecipeRegistry.Contract.RecipeTimestamps(&_RecipeRegistry.CallOpts, arg0)
+}
+
+// Registrars is a free data retrieval call binding the contract method 0x89aeca76.
+//
+// Solidity: function registrars(address ) view returns(bool)
+func (_RecipeRegistry *RecipeRegistryCaller) Registrars(opts *bind.CallOpts, arg0 common.Address) (bool, error) {
+	var out []interface{}
+	err := _RecipeRegistry.contract.Call(opts, &out, "registrars", arg0)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// Registrars is a free data retrieval call binding the contract method 0x89aeca76.
+//
+// Solidity: function registrars(address ) view returns(bool)
+func (_RecipeRegistry *RecipeRegistrySession) Registrars(arg0 common.Address) (bool, error) {
+	return _RecipeRegistry.Contract.Registrars(&_RecipeRegistry.CallOpts, arg0)
+}
+
+// Registrars is a free data retrieval call binding the contract method 0x89aeca76.
+//
+// Solidity: function registrars(address ) view returns(bool)
+func (_RecipeRegistry *RecipeRegistryCallerSession) Registrars(arg0 common.Address) (bool, error) {
+	return _RecipeRegistry.Contract.Registrars(&_RecipeRegistry.CallOpts, arg0)
+}
+
+// VerifyBatchInclusion is a free data retrieval call binding the contract method 0x48b5b1e9.
+//
+// Solidity: function verifyBatchInclusion(bytes32 _root, bytes32 _recipeHash, address _creator, bytes32[] _proof) view returns(bool)
+func (_RecipeRegistry *RecipeRegistryCaller) VerifyBatchInclusion(opts *bind.CallOpts, _root [32]byte, _recipeHash [32]byte, _creator common.Address, _proof [][32]byte) (bool, error) {
+	var out []interface{}
+	err := _RecipeRegistry.contract.Call(opts, &out, "verifyBatchInclusion", _root, _recipeHash, _creator, _proof)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// VerifyBatchInclusion is a free data retrieval call binding the contract method 0x48b5b1e9.
+//
+// Solidity: function verifyBatchInclusion(bytes32 _root, bytes32 _recipeHash, address _creator, bytes32[] _proof) view returns(bool)
+func (_RecipeRegistry *RecipeRegistrySession) VerifyBatchInclusion(_root [32]byte, _recipeHash [32]byte, _creator common.Address, _proof [][32]byte) (bool, error) {
+	return _RecipeRegistry.Contract.VerifyBatchInclusion(&_RecipeRegistry.CallOpts, _root, _recipeHash, _creator, _proof)
+}
+
+// VerifyBatchInclusion is a free data retrieval call binding the contract method 0x48b5b1e9.
+//
+// Solidity: function verifyBatchInclusion(bytes32 _root, bytes32 _recipeHash, address _creator, bytes32[] _proof) view returns(bool)
+func (_RecipeRegistry *RecipeRegistryCallerSession) VerifyBatchInclusion(_root [32]byte, _recipeHash [32]byte, _creator common.Address, _proof [][32]byte) (bool, error) {
+	return _RecipeRegistry.Contract.VerifyBatchInclusion(&_RecipeRegistry.CallOpts, _root, _recipeHash, _creator, _proof)
+}
+
+// AddRecipe is a paid mutator transaction binding the contract method 0xd5cc4ae9.
+//
+// Solidity: function addRecipe(bytes32 _recipeHash, address _creator) returns()
+func (_RecipeRegistry *RecipeRegistryTransactor) AddRecipe(opts *bind.TransactOpts, _recipeHash [32]byte, _creator common.Address) (*types.Transaction, error) {
+	return _RecipeRegistry.contract.Transact(opts, "addRecipe", _recipeHash, _creator)
+}
+
+// AddRecipe is a paid mutator transaction binding the contract method 0xd5cc4ae9.
+//
+// Solidity: function addRecipe(bytes32 _recipeHash, address _creator) returns()
+func (_RecipeRegistry *RecipeRegistrySession) AddRecipe(_recipeHash [32]byte, _creator common.Address) (*types.Transaction, error) {
+	return _RecipeRegistry.Contract.AddRecipe(&_RecipeRegistry.TransactOpts, _recipeHash, _creator)
+}
+
+// AddRecipe is a paid mutator transaction binding the contract method 0xd5cc4ae9.
+//
+// Solidity: function addRecipe(bytes32 _recipeHash, address _creator) returns()
+func (_RecipeRegistry *RecipeRegistryTransactorSession) AddRecipe(_recipeHash [32]byte, _creator common.Address) (*types.Transaction, error) {
+	return _RecipeRegistry.Contract.AddRecipe(&_RecipeRegistry.TransactOpts, _recipeHash, _creator)
+}
+
+// AnchorBatch is a paid mutator transaction binding the contract method 0x9a1d0d41.
+//
+// Solidity: function anchorBatch(bytes32 _root, uint256 _leafCount) returns()
+func (_RecipeRegistry *RecipeRegistryTransactor) AnchorBatch(opts *bind.TransactOpts, _root [32]byte, _leafCount *big.Int) (*types.Transaction, error) {
+	return _RecipeRegistry.contract.Transact(opts, "anchorBatch", _root, _leafCount)
+}
+
+// AnchorBatch is a paid mutator transaction binding the contract method 0x9a1d0d41.
+//
+// Solidity: function anchorBatch(bytes32 _root, uint256 _leafCount) returns()
+func (_RecipeRegistry *RecipeRegistrySession) AnchorBatch(_root [32]byte, _leafCount *big.Int) (*types.Transaction, error) {
+	return _RecipeRegistry.Contract.AnchorBatch(&_RecipeRegistry.TransactOpts, _root, _leafCount)
+}
+
+// AnchorBatch is a paid mutator transaction binding the contract method 0x9a1d0d41.
+//
+// Solidity: function anchorBatch(bytes32 _root, uint256 _leafCount) returns()
+func (_RecipeRegistry *RecipeRegistryTransactorSession) AnchorBatch(_root [32]byte, _leafCount *big.Int) (*types.Transaction, error) {
+	return _RecipeRegistry.Contract.AnchorBatch(&_RecipeRegistry.TransactOpts, _root, _leafCount)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_RecipeRegistry *RecipeRegistryTransactor) RenounceOwnership(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _RecipeRegistry.contract.Transact(opts, "renounceOwnership")
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_RecipeRegistry *RecipeRegistrySession) RenounceOwnership() (*types.Transaction, error) {
+	return _RecipeRegistry.Contract.RenounceOwnership(&_RecipeRegistry.TransactOpts)
+}
+
+// RenounceOwnership is a paid mutator transaction binding the contract method 0x715018a6.
+//
+// Solidity: function renounceOwnership() returns()
+func (_RecipeRegistry *RecipeRegistryTransactorSession) RenounceOwnership() (*types.Transaction, error) {
+	return _RecipeRegistry.Contract.RenounceOwnership(&_RecipeRegistry.TransactOpts)
+}
+
+// SetRegistrar is a paid mutator transaction binding the contract method 0xc1bf1dcf.
+//
+// Solidity: function setRegistrar(address _account, bool _authorized) returns()
+func (_RecipeRegistry *RecipeRegistryTransactor) SetRegistrar(opts *bind.TransactOpts, _account common.Address, _authorized bool) (*types.Transaction, error) {
+	return _RecipeRegistry.contract.Transact(opts, "setRegistrar", _account, _authorized)
+}
+
+// SetRegistrar is a paid mutator transaction binding the contract method 0xc1bf1dcf.
+//
+// Solidity: function setRegistrar(address _account, bool _authorized) returns()
+func (_RecipeRegistry *RecipeRegistrySession) SetRegistrar(_account common.Address, _authorized bool) (*types.Transaction, error) {
+	return _RecipeRegistry.Contract.SetRegistrar(&_RecipeRegistry.TransactOpts, _account, _authorized)
+}
+
+// SetRegistrar is a paid mutator transaction binding the contract method 0xc1bf1dcf.
+//
+// Solidity: function setRegistrar(address _account, bool _authorized) returns()
+func (_RecipeRegistry *RecipeRegistryTransactorSession) SetRegistrar(_account common.Address, _authorized bool) (*types.Transaction, error) {
+	return _RecipeRegistry.Contract.SetRegistrar(&_RecipeRegistry.TransactOpts, _account, _authorized)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_RecipeRegistry *RecipeRegistryTransactor) TransferOwnership(opts *bind.TransactOpts, newOwner common.Address) (*types.Transaction, error) {
+	return _RecipeRegistry.contract.Transact(opts, "transferOwnership", newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_RecipeRegistry *RecipeRegistrySession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _RecipeRegistry.Contract.TransferOwnership(&_RecipeRegistry.TransactOpts, newOwner)
+}
+
+// TransferOwnership is a paid mutator transaction binding the contract method 0xf2fde38b.
+//
+// Solidity: function transferOwnership(address newOwner) returns()
+func (_RecipeRegistry *RecipeRegistryTransactorSession) TransferOwnership(newOwner common.Address) (*types.Transaction, error) {
+	return _RecipeRegistry.Contract.TransferOwnership(&_RecipeRegistry.TransactOpts, newOwner)
+}
+
+// RecipeRegistryBatchAnchoredIterator is returned from FilterBatchAnchored and is used to iterate over the raw logs and unpacked data for BatchAnchored events raised by the RecipeRegistry contract.
+type RecipeRegistryBatchAnchoredIterator struct {
+	Event *RecipeRegistryBatchAnchored // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *RecipeRegistryBatchAnchoredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(RecipeRegistryBatchAnchored)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(RecipeRegistryBatchAnchored)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *RecipeRegistryBatchAnchoredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *RecipeRegistryBatchAnchoredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// RecipeRegistryBatchAnchored represents a BatchAnchored event raised by the RecipeRegistry contract.
+type RecipeRegistryBatchAnchored struct {
+	Root      [32]byte
+	LeafCount *big.Int
+	Timestamp *big.Int
+	Raw       types.Log // Blockchain specific contextual infos
+}
+
+// FilterBatchAnchored is a free log retrieval operation binding the contract event 0x569739a8a132d28586d65d35b6a86902bf0adbce83a4651378ff888d03b70255.
+//
+// Solidity: event BatchAnchored(bytes32 indexed root, uint256 leafCount, uint256 timestamp)
+func (_RecipeRegistry *RecipeRegistryFilterer) FilterBatchAnchored(opts *bind.FilterOpts, root [][32]byte) (*RecipeRegistryBatchAnchoredIterator, error) {
+
+	var rootRule []interface{}
+	for _, rootItem := range root {
+		rootRule = append(rootRule, rootItem)
+	}
+
+	logs, sub, err := _RecipeRegistry.contract.FilterLogs(opts, "BatchAnchored", rootRule)
+	if err != nil {
+		return nil, err
+	}
+	return &RecipeRegistryBatchAnchoredIterator{contract: _RecipeRegistry.contract, event: "BatchAnchored", logs: logs, sub: sub}, nil
+}
+
+// WatchBatchAnchored is a free log subscription operation binding the contract event 0x569739a8a132d28586d65d35b6a86902bf0adbce83a4651378ff888d03b70255.
+//
+// Solidity: event BatchAnchored(bytes32 indexed root, uint256 leafCount, uint256 timestamp)
+func (_RecipeRegistry *RecipeRegistryFilterer) WatchBatchAnchored(opts *bind.WatchOpts, sink chan<- *RecipeRegistryBatchAnchored, root [][32]byte) (event.Subscription, error) {
+
+	var rootRule []interface{}
+	for _, rootItem := range root {
+		rootRule = append(rootRule, rootItem)
+	}
+
+	logs, sub, err := _RecipeRegistry.contract.WatchLogs(opts, "BatchAnchored", rootRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(RecipeRegistryBatchAnchored)
+				if err := _RecipeRegistry.contract.UnpackLog(event, "BatchAnchored", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseBatchAnchored is a log parse operation binding the contract event 0x569739a8a132d28586d65d35b6a86902bf0adbce83a4651378ff888d03b70255.
+//
+// Solidity: event BatchAnchored(bytes32 indexed root, uint256 leafCount, uint256 timestamp)
+func (_RecipeRegistry *RecipeRegistryFilterer) ParseBatchAnchored(log types.Log) (*RecipeRegistryBatchAnchored, error) {
+	event := new(RecipeRegistryBatchAnchored)
+	if err := _RecipeRegistry.contract.UnpackLog(event, "BatchAnchored", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// RecipeRegistryOwnershipTransferredIterator is returned from FilterOwnershipTransferred and is used to iterate over the raw logs and unpacked data for OwnershipTransferred events raised by the RecipeRegistry contract.
+type RecipeRegistryOwnershipTransferredIterator struct {
+	Event *RecipeRegistryOwnershipTransferred // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *RecipeRegistryOwnershipTransferredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(RecipeRegistryOwnershipTransferred)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(RecipeRegistryOwnershipTransferred)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *RecipeRegistryOwnershipTransferredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *RecipeRegistryOwnershipTransferredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// RecipeRegistryOwnershipTransferred represents a OwnershipTransferred event raised by the RecipeRegistry contract.
+type RecipeRegistryOwnershipTransferred struct {
+	PreviousOwner common.Address
+	NewOwner      common.Address
+	Raw           types.Log // Blockchain specific contextual infos
+}
+
+// FilterOwnershipTransferred is a free log retrieval operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_RecipeRegistry *RecipeRegistryFilterer) FilterOwnershipTransferred(opts *bind.FilterOpts, previousOwner []common.Address, newOwner []common.Address) (*RecipeRegistryOwnershipTransferredIterator, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _RecipeRegistry.contract.FilterLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &RecipeRegistryOwnershipTransferredIterator{contract: _RecipeRegistry.contract, event: "OwnershipTransferred", logs: logs, sub: sub}, nil
+}
+
+// WatchOwnershipTransferred is a free log subscription operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_RecipeRegistry *RecipeRegistryFilterer) WatchOwnershipTransferred(opts *bind.WatchOpts, sink chan<- *RecipeRegistryOwnershipTransferred, previousOwner []common.Address, newOwner []common.Address) (event.Subscription, error) {
+
+	var previousOwnerRule []interface{}
+	for _, previousOwnerItem := range previousOwner {
+		previousOwnerRule = append(previousOwnerRule, previousOwnerItem)
+	}
+	var newOwnerRule []interface{}
+	for _, newOwnerItem := range newOwner {
+		newOwnerRule = append(newOwnerRule, newOwnerItem)
+	}
+
+	logs, sub, err := _RecipeRegistry.contract.WatchLogs(opts, "OwnershipTransferred", previousOwnerRule, newOwnerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(RecipeRegistryOwnershipTransferred)
+				if err := _RecipeRegistry.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseOwnershipTransferred is a log parse operation binding the contract event 0x8be0079c531659141344cd1fd0a4f28419497f9722a3daafe3b4186f6b6457e0.
+//
+// Solidity: event OwnershipTransferred(address indexed previousOwner, address indexed newOwner)
+func (_RecipeRegistry *RecipeRegistryFilterer) ParseOwnershipTransferred(log types.Log) (*RecipeRegistryOwnershipTransferred, error) {
+	event := new(RecipeRegistryOwnershipTransferred)
+	if err := _RecipeRegistry.contract.UnpackLog(event, "OwnershipTransferred", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// RecipeRegistryRecipeAddedIterator is returned from FilterRecipeAdded and is used to iterate over the raw logs and unpacked data for RecipeAdded events raised by the RecipeRegistry contract.
+type RecipeRegistryRecipeAddedIterator struct {
+	Event *RecipeRegistryRecipeAdded // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *RecipeRegistryRecipeAddedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(RecipeRegistryRecipeAdded)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(RecipeRegistryRecipeAdded)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *RecipeRegistryRecipeAddedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *RecipeRegistryRecipeAddedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// RecipeRegistryRecipeAdded represents a RecipeAdded event raised by the RecipeRegistry contract.
+type RecipeRegistryRecipeAdded struct {
+	RecipeHash [32]byte
+	Creator    common.Address
+	Timestamp  *big.Int
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterRecipeAdded is a free log retrieval operation binding the contract event 0x9203c64ea296ec5884d25bb426d6d8bfa5a22b30c86d789cf95f241f4403263a.
+//
+// Solidity: event RecipeAdded(bytes32 indexed recipeHash, address indexed creator, uint256 timestamp)
+func (_RecipeRegistry *RecipeRegistryFilterer) FilterRecipeAdded(opts *bind.FilterOpts, recipeHash [][32]byte, creator []common.Address) (*RecipeRegistryRecipeAddedIterator, error) {
+
+	var recipeHashRule []interface{}
+	for _, recipeHashItem := range recipeHash {
+		recipeHashRule = append(recipeHashRule, recipeHashItem)
+	}
+	var creatorRule []interface{}
+	for _, creatorItem := range creator {
+		creatorRule = append(creatorRule, creatorItem)
+	}
+
+	logs, sub, err := _RecipeRegistry.contract.FilterLogs(opts, "RecipeAdded", recipeHashRule, creatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return &RecipeRegistryRecipeAddedIterator{contract: _RecipeRegistry.contract, event: "RecipeAdded", logs: logs, sub: sub}, nil
+}
+
+// WatchRecipeAdded is a free log subscription operation binding the contract event 0x9203c64ea296ec5884d25bb426d6d8bfa5a22b30c86d789cf95f241f4403263a.
+//
+// Solidity: event RecipeAdded(bytes32 indexed recipeHash, address indexed creator, uint256 timestamp)
+func (_RecipeRegistry *RecipeRegistryFilterer) WatchRecipeAdded(opts *bind.WatchOpts, sink chan<- *RecipeRegistryRecipeAdded, recipeHash [][32]byte, creator []common.Address) (event.Subscription, error) {
+
+	var recipeHashRule []interface{}
+	for _, recipeHashItem := range recipeHash {
+		recipeHashRule = append(recipeHashRule, recipeHashItem)
+	}
+	var creatorRule []interface{}
+	for _, creatorItem := range creator {
+		creatorRule = append(creatorRule, creatorItem)
+	}
+
+	logs, sub, err := _RecipeRegistry.contract.WatchLogs(opts, "RecipeAdded", recipeHashRule, creatorRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(RecipeRegistryRecipeAdded)
+				if err := _RecipeRegistry.contract.UnpackLog(event, "RecipeAdded", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseRecipeAdded is a log parse operation binding the contract event 0x9203c64ea296ec5884d25bb426d6d8bfa5a22b30c86d789cf95f241f4403263a.
+//
+// Solidity: event RecipeAdded(bytes32 indexed recipeHash, address indexed creator, uint256 timestamp)
+func (_RecipeRegistry *RecipeRegistryFilterer) ParseRecipeAdded(log types.Log) (*RecipeRegistryRecipeAdded, error) {
+	event := new(RecipeRegistryRecipeAdded)
+	if err := _RecipeRegistry.contract.UnpackLog(event, "RecipeAdded", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// RecipeRegistryRegistrarUpdatedIterator is returned from FilterRegistrarUpdated and is used to iterate over the raw logs and unpacked data for RegistrarUpdated events raised by the RecipeRegistry contract.
+type RecipeRegistryRegistrarUpdatedIterator struct {
+	Event *RecipeRegistryRegistrarUpdated // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *RecipeRegistryRegistrarUpdatedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(RecipeRegistryRegistrarUpdated)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(RecipeRegistryRegistrarUpdated)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *RecipeRegistryRegistrarUpdatedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *RecipeRegistryRegistrarUpdatedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// RecipeRegistryRegistrarUpdated represents a RegistrarUpdated event raised by the RecipeRegistry contract.
+type RecipeRegistryRegistrarUpdated struct {
+	Account    common.Address
+	Authorized bool
+	Raw        types.Log // Blockchain specific contextual infos
+}
+
+// FilterRegistrarUpdated is a free log retrieval operation binding the contract event 0xaa29e9c7319872059973646686bcb05675a1351529119ca980b4ad90e73d7264.
+//
+// Solidity: event RegistrarUpdated(address indexed account, bool authorized)
+func (_RecipeRegistry *RecipeRegistryFilterer) FilterRegistrarUpdated(opts *bind.FilterOpts, account []common.Address) (*RecipeRegistryRegistrarUpdatedIterator, error) {
+
+	var accountRule []interface{}
+	for _, accountItem := range account {
+		accountRule = append(accountRule, accountItem)
+	}
+
+	logs, sub, err := _RecipeRegistry.contract.FilterLogs(opts, "RegistrarUpdated", accountRule)
+	if err != nil {
+		return nil, err
+	}
+	return &RecipeRegistryRegistrarUpdatedIterator{contract: _RecipeRegistry.contract, event: "RegistrarUpdated", logs: logs, sub: sub}, nil
+}
+
+// WatchRegistrarUpdated is a free log subscription operation binding the contract event 0xaa29e9c7319872059973646686bcb05675a1351529119ca980b4ad90e73d7264.
+//
+// Solidity: event RegistrarUpdated(address indexed account, bool authorized)
+func (_RecipeRegistry *RecipeRegistryFilterer) WatchRegistrarUpdated(opts *bind.WatchOpts, sink chan<- *RecipeRegistryRegistrarUpdated, account []common.Address) (event.Subscription, error) {
+
+	var accountRule []interface{}
+	for _, accountItem := range account {
+		accountRule = append(accountRule, accountItem)
+	}
+
+	logs, sub, err := _RecipeRegistry.contract.WatchLogs(opts, "RegistrarUpdated", accountRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(RecipeRegistryRegistrarUpdated)
+				if err := _RecipeRegistry.contract.UnpackLog(event, "RegistrarUpdated", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseRegistrarUpdated is a log parse operation binding the contract event 0xaa29e9c7319872059973646686bcb05675a1351529119ca980b4ad90e73d7264.
+//
+// Solidity: event RegistrarUpdated(address indexed account, bool authorized)
+func (_RecipeRegistry *RecipeRegistryFilterer) ParseRegistrarUpdated(log types.Log) (*RecipeRegistryRegistrarUpdated, error) {
+	event := new(RecipeRegistryRegistrarUpdated)
+	if err := _RecipeRegistry.contract.UnpackLog(event, "RegistrarUpdated", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -0,0 +1,177 @@
+package blockchain
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Sentinel errors for the ways RecipeRegistry rejects a call. Use errors.Is on
+// the error returned by RegisterRecipeOnChain.
+var (
+	ErrHashAlreadyRegistered = errors.New("recipe hash already registered")
+	ErrZeroCreator           = errors.New("creator address cannot be zero")
+	ErrNotOwner              = errors.New("backend account is not the registry owner")
+	ErrNotRegistrar          = errors.New("backend account is not an authorized registrar")
+	ErrInvalidOwner          = errors.New("invalid registry owner")
+	ErrInvalidRegistration   = errors.New("invalid registration request")
+	ErrBatchAlreadyAnchored  = errors.New("batch root already anchored")
+	ErrZeroBatchRoot         = errors.New("batch root cannot be zero")
+)
+
+// requireReasons maps the contract's require() messages to sentinel errors.
+var requireReasons = map[string]error{
+	"Recipe hash already exists":     ErrHashAlreadyRegistered,
+	"Creator address cannot be zero": ErrZeroCreator,
+	"Batch root already anchored":    ErrBatchAlreadyAnchored,
+	"Batch root cannot be zero":      ErrZeroBatchRoot,
+}
+
+// customErrors maps the contract's custom errors (see contractABI.Errors) to sentinel errors.
+var customErrors = map[string]error{
+	"OwnableUnauthorizedAccount": ErrNotOwner,
+	"OwnableInvalidOwner":        ErrInvalidOwner,
+	"UnauthorizedRegistrar":      ErrNotRegistrar,
+}
+
+// RevertError is returned when the contract rejects a call, either during gas
+// estimation or when a mined transaction reverted. It unwraps to one of the
+// sentinel errors above when the reason is known.
+type RevertError struct {
+	Reason      string        // Decoded Error(string) reason, empty if none
+	CustomError string        // Name of the decoded custom error, empty if none
+	Args        []interface{} // Arguments of the custom error
+	Data        []byte        // Raw revert data as returned by the node
+	Err         error         // Matching sentinel error, nil if unknown
+}
+
+func (e *RevertError) Error() string {
+	switch {
+	case e.CustomError != "":
+		return fmt.Sprintf("execution reverted: %s%v", e.CustomError, e.Args)
+	case e.Reason != "":
+		return "execution reverted: " + e.Reason
+	default:
+		return "execution reverted"
+	}
+}
+
+func (e *RevertError) Unwrap() error {
+	return e.Err
+}
+
+// IsRetryable reports whether a registration that failed with err may succeed if
+// attempted again. Contract-level rejections and invalid input never will.
+func IsRetryable(err error) bool {
+	switch {
+	// Wraps the rejection of the last signer, but signers come back once they
+	// are authorized again
+	case errors.Is(err, ErrNoSignerAvailable):
+		return true
+	case errors.Is(err, ErrHashAlreadyRegistered),
+		errors.Is(err, ErrZeroCreator),
+		errors.Is(err, ErrNotOwner),
+		errors.Is(err, ErrNotRegistrar),
+		errors.Is(err, ErrInvalidOwner),
+		errors.Is(err, ErrInvalidRegistration),
+		errors.Is(err, ErrBatchAlreadyAnchored),
+		errors.Is(err, ErrZeroBatchRoot):
+		return false
+	default:
+		return true
+	}
+}
+
+// decodeRevert decodes revert data into a *RevertError, handling Error(string)
+// and the custom errors declared in the contract ABI.
+func decodeRevert(data []byte) *RevertError {
+	revertErr := &RevertError{Data: data}
+	if len(data) < 4 {
+		return revertErr
+	}
+
+	if reason, err := abi.UnpackRevert(data); err == nil {
+		revertErr.Reason = reason
+		revertErr.Err = requireReasons[reason]
+		return revertErr
+	}
+
+	for name, abiErr := range contractABI.Errors {
+		if !bytes.Equal(abiErr.ID[:4], data[:4]) {
+			continue
+		}
+		revertErr.CustomError = name
+		if args, err := abiErr.Inputs.Unpack(data[4:]); err == nil {
+			revertErr.Args = args
+		}
+		revertErr.Err = customErrors[name]
+		break
+	}
+	return revertErr
+}
+
+// asRevertError converts a node error caused by a contract revert into a *RevertError.
+// It returns nil for any other error (network failures, etc.).
+func asRevertError(err error) *RevertError {
+	// Most nodes return the revert data alongside the error
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		if data := revertData(dataErr.ErrorData()); len(data) > 0 {
+			return decodeRevert(data)
+		}
+	}
+
+	// Some only include the reason in the message
+	msg := err.Error()
+	if idx := strings.Index(msg, "execution reverted"); idx >= 0 {
+		reason := strings.TrimSpace(strings.TrimPrefix(msg[idx+len("execution reverted"):], ":"))
+		return &RevertError{Reason: reason, Err: requireReasons[reason]}
+	}
+	return nil
+}
+
+// revertData extracts raw bytes from the data field of a JSON-RPC error.
+func revertData(data interface{}) []byte {
+	switch v := data.(type) {
+	case string:
+		b, err := hexutil.Decode(v)
+		if err != nil {
+			return nil
+		}
+		return b
+	case []byte:
+		return v
+	default:
+		return nil
+	}
+}
+
+// replayRevert re-executes a reverted transaction with CallContract at the block it
+// was mined in to recover the revert reason, which receipts do not contain.
+func replayRevert(ctx context.Context, tx *types.Transaction, from common.Address, blockNumber *big.Int) error {
+	_, err := ethClient.CallContract(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    tx.To(),
+		Gas:   tx.Gas(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}, blockNumber)
+	if err == nil {
+		// Not a contract rejection: out of gas, or state changed within the block
+		return errors.New("replay of reverted transaction succeeded (out of gas or state changed within the block)")
+	}
+	if revertErr := asRevertError(err); revertErr != nil {
+		return revertErr
+	}
+	return fmt.Errorf("failed to replay reverted transaction: %w", err)
+}
@@ -0,0 +1,93 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ErrFeeCapExceeded is returned when the network base fee is above the configured
+// MAX_FEE_PER_GAS_GWEI. The registration should be retried once fees come down.
+var ErrFeeCapExceeded = errors.New("network base fee exceeds configured max fee per gas")
+
+// FeeCaps bounds what the backend wallet is willing to pay per unit of gas.
+// A nil field means "no cap".
+type FeeCaps struct {
+	MaxFeePerGas         *big.Int // MAX_FEE_PER_GAS_GWEI
+	MaxPriorityFeePerGas *big.Int // MAX_PRIORITY_FEE_GWEI
+}
+
+// feeCaps is loaded by InitBlockchain.
+var feeCaps FeeCaps
+
+// loadFeeCaps reads the fee caps from the environment.
+func loadFeeCaps() (FeeCaps, error) {
+	var caps FeeCaps
+	var err error
+	if caps.MaxFeePerGas, err = parseGweiEnv("MAX_FEE_PER_GAS_GWEI"); err != nil {
+		return caps, err
+	}
+	if caps.MaxPriorityFeePerGas, err = parseGweiEnv("MAX_PRIORITY_FEE_GWEI"); err != nil {
+		return caps, err
+	}
+	return caps, nil
+}
+
+// parseGweiEnv parses a (possibly fractional) gwei amount into wei; empty means unset.
+func parseGweiEnv(key string) (*big.Int, error) {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return nil, nil
+	}
+	gwei, ok := new(big.Float).SetString(raw)
+	if !ok || gwei.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid %s=%q, expected a positive gwei amount", key, raw)
+	}
+	wei, _ := new(big.Float).Mul(gwei, big.NewFloat(params.GWei)).Int(nil)
+	return wei, nil
+}
+
+// DynamicFees are the EIP-1559 fee fields of a transaction.
+type DynamicFees struct {
+	GasTipCap *big.Int // maxPriorityFeePerGas
+	GasFeeCap *big.Int // maxFeePerGas
+}
+
+// suggestDynamicFees derives EIP-1559 fees from the node's tip suggestion and the
+// latest base fee: feeCap = 2*baseFee + tip, which survives several full blocks of
+// base fee growth. Both values are clamped to the configured caps.
+func suggestDynamicFees(ctx context.Context) (*DynamicFees, error) {
+	header, err := ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest header: %w", err)
+	}
+	if header.BaseFee == nil {
+		return nil, fmt.Errorf("connected chain does not support EIP-1559 dynamic fee transactions")
+	}
+
+	tip, err := ethClient.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to suggest gas tip cap: %w", err)
+	}
+	if feeCaps.MaxPriorityFeePerGas != nil && tip.Cmp(feeCaps.MaxPriorityFeePerGas) > 0 {
+		tip = new(big.Int).Set(feeCaps.MaxPriorityFeePerGas)
+	}
+
+	feeCap := new(big.Int).Add(new(big.Int).Mul(header.BaseFee, big.NewInt(2)), tip)
+	if feeCaps.MaxFeePerGas != nil && feeCap.Cmp(feeCaps.MaxFeePerGas) > 0 {
+		if header.BaseFee.Cmp(feeCaps.MaxFeePerGas) >= 0 {
+			return nil, fmt.Errorf("%w (base fee %s wei, cap %s wei)", ErrFeeCapExceeded, header.BaseFee, feeCaps.MaxFeePerGas)
+		}
+		feeCap = new(big.Int).Set(feeCaps.MaxFeePerGas)
+	}
+	// The tip can never exceed the fee cap
+	if tip.Cmp(feeCap) > 0 {
+		tip = new(big.Int).Set(feeCap)
+	}
+
+	return &DynamicFees{GasTipCap: tip, GasFeeCap: feeCap}, nil
+}
@@ -0,0 +1,56 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const defaultGasLimitMultiplier = 1.2
+
+// gasLimitMultiplier is the safety margin applied on top of EstimateGas, loaded by InitBlockchain.
+var gasLimitMultiplier = defaultGasLimitMultiplier
+
+// loadGasLimitMultiplier reads GAS_LIMIT_MULTIPLIER from the environment.
+func loadGasLimitMultiplier() (float64, error) {
+	raw := os.Getenv("GAS_LIMIT_MULTIPLIER")
+	if raw == "" {
+		return defaultGasLimitMultiplier, nil
+	}
+	m, err := strconv.ParseFloat(raw, 64)
+	if err != nil || m < 1 {
+		return 0, fmt.Errorf("invalid GAS_LIMIT_MULTIPLIER=%q, expected a number >= 1", raw)
+	}
+	return m, nil
+}
+
+// estimateGas estimates the gas for calling the registry with callData from the
+// backend account and applies the safety multiplier. Reverts are returned as
+// *RevertError before anything is signed.
+func estimateGas(ctx context.Context, from common.Address, callData []byte) (uint64, error) {
+	estimate, err := ethClient.EstimateGas(ctx, ethereum.CallMsg{
+		From: from,
+		To:   &contractAddress,
+		Data: callData,
+	})
+	if err != nil {
+		if revertErr := asRevertError(err); revertErr != nil {
+			return 0, revertErr
+		}
+		return 0, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+
+	limit := float64(estimate) * gasLimitMultiplier
+	if limit > math.MaxUint64 {
+		return 0, fmt.Errorf("gas estimate %d overflows with multiplier %.2f", estimate, gasLimitMultiplier)
+	}
+	gas := uint64(math.Ceil(limit))
+	log.Printf("Estimated gas %d, using limit %d (x%.2f)", estimate, gas, gasLimitMultiplier)
+	return gas, nil
+}
@@ -0,0 +1,155 @@
+package blockchain
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// pendingNonceSource is the part of the Ethereum client the nonce manager needs.
+type pendingNonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager serializes nonce allocation for a single signer so concurrent
+// registrations never reuse or skip a nonce.
+//
+// A nonce is "in flight" from Next until the transaction is handed to the node
+// (MarkSent) or abandoned (Release). Released nonces are reused before new ones
+// are allocated, which closes the gap a failed send would otherwise leave behind.
+type NonceManager struct {
+	mu       sync.Mutex
+	client   pendingNonceSource
+	account  common.Address
+	synced   bool
+	next     uint64              // Next never-used nonce
+	inFlight map[uint64]struct{} // Allocated but not yet accepted by the node
+	released []uint64            // Allocated, then abandoned; reused first (kept sorted)
+}
+
+// NewNonceManager creates a nonce manager for account. The first call to Next
+// syncs with the node's pending nonce.
+func NewNonceManager(client pendingNonceSource, account common.Address) *NonceManager {
+	return &NonceManager{
+		client:   client,
+		account:  account,
+		inFlight: make(map[uint64]struct{}),
+	}
+}
+
+// Next allocates the nonce for the next transaction.
+func (m *NonceManager) Next(ctx context.Context) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if !m.synced {
+		if err := m.resyncLocked(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	var nonce uint64
+	if len(m.released) > 0 {
+		nonce, m.released = m.released[0], m.released[1:]
+	} else {
+		nonce = m.next
+		m.next++
+	}
+	m.inFlight[nonce] = struct{}{}
+	return nonce, nil
+}
+
+// MarkSent records that the node accepted the transaction using nonce.
+func (m *NonceManager) MarkSent(nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.inFlight, nonce)
+}
+
+// Release returns a nonce whose transaction never reached the node, so the next
+// transaction fills the gap instead of getting stuck behind it.
+func (m *NonceManager) Release(nonce uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.inFlight, nonce)
+	if nonce+1 == m.next {
+		m.next--
+		return
+	}
+	if nonce < m.next && !slices.Contains(m.released, nonce) {
+		m.released = append(m.released, nonce)
+		slices.Sort(m.released)
+	}
+}
+
+// Reuse marks nonce as in flight again, to re-send a transaction the node dropped
+// before it was mined. It reports false if another transaction holds the nonce
+// or the nonce lies past the next one, in which case the caller allocates a new
+// nonce with Next instead.
+func (m *NonceManager) Reuse(nonce uint64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, busy := m.inFlight[nonce]; busy || !m.synced || nonce > m.next {
+		return false
+	}
+	if nonce == m.next {
+		m.next++
+	} else {
+		m.released = slices.DeleteFunc(m.released, func(n uint64) bool { return n == nonce })
+	}
+	m.inFlight[nonce] = struct{}{}
+	return true
+}
+
+// Resync reloads the pending nonce from the node, e.g. after a "nonce too low"
+// error caused by another process using the same key.
+func (m *NonceManager) Resync(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.resyncLocked(ctx)
+}
+
+func (m *NonceManager) resyncLocked(ctx context.Context) error {
+	pending, err := m.client.PendingNonceAt(ctx, m.account)
+	if err != nil {
+		m.synced = false
+		return fmt.Errorf("failed to get pending nonce: %w", err)
+	}
+
+	// Released nonces below the node's pending nonce have been used elsewhere
+	m.released = slices.DeleteFunc(m.released, func(n uint64) bool { return n < pending })
+
+	if len(m.inFlight) == 0 {
+		// Nothing is between allocation and broadcast, so the node is authoritative
+		// (this also rewinds past transactions that were dropped from the mempool).
+		if m.synced && pending != m.next {
+			log.Printf("Nonce manager: resynced %s from %d to %d", m.account.Hex(), m.next, pending)
+		}
+		m.next = pending
+	} else if pending > m.next {
+		m.next = pending
+	}
+	m.synced = true
+	return nil
+}
+
+// isNonceError reports whether a send error means our local nonce view is stale.
+func isNonceError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "nonce too high") ||
+		strings.Contains(msg, "replacement transaction underpriced")
+}
+
+// isAlreadyKnown reports whether the node already has this exact transaction,
+// which means an earlier send of it succeeded.
+func isAlreadyKnown(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "already known")
+}
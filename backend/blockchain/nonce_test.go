@@ -0,0 +1,228 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// fakeNonceSource stands in for the node's pending nonce.
+type fakeNonceSource struct {
+	mu      sync.Mutex
+	pending uint64
+	err     error
+	calls   int
+}
+
+func (f *fakeNonceSource) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	return f.pending, f.err
+}
+
+func (f *fakeNonceSource) set(pending uint64, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pending, f.err = pending, err
+}
+
+var testNonceAccount = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+
+func mustNext(t *testing.T, m *NonceManager) uint64 {
+	t.Helper()
+	nonce, err := m.Next(context.Background())
+	if err != nil {
+		t.Fatalf("Next: %v", err)
+	}
+	return nonce
+}
+
+func TestNonceManagerConcurrentNextIsUnique(t *testing.T) {
+	source := &fakeNonceSource{pending: 5}
+	m := NewNonceManager(source, testNonceAccount)
+
+	const n = 50
+	nonces := make(chan uint64, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nonce, err := m.Next(context.Background())
+			if err != nil {
+				t.Errorf("Next: %v", err)
+				return
+			}
+			m.MarkSent(nonce)
+			nonces <- nonce
+		}()
+	}
+	wg.Wait()
+	close(nonces)
+
+	var got []uint64
+	for nonce := range nonces {
+		got = append(got, nonce)
+	}
+	slices.Sort(got)
+	for i, nonce := range got {
+		if nonce != uint64(5+i) {
+			t.Fatalf("nonces = %v, want 5..%d without duplicates or gaps", got, 5+n-1)
+		}
+	}
+	if len(got) != n {
+		t.Fatalf("got %d nonces, want %d", len(got), n)
+	}
+	if source.calls != 1 {
+		t.Errorf("PendingNonceAt called %d times, want once", source.calls)
+	}
+}
+
+func TestNonceManagerReleaseFillsGap(t *testing.T) {
+	m := NewNonceManager(&fakeNonceSource{}, testNonceAccount)
+	for want := uint64(0); want < 4; want++ {
+		if got := mustNext(t, m); got != want {
+			t.Fatalf("Next = %d, want %d", got, want)
+		}
+	}
+	m.MarkSent(0)
+	m.MarkSent(3)
+
+	// The sends with nonces 2 and 1 failed; both are reused, lowest first
+	m.Release(2)
+	m.Release(1)
+	for _, want := range []uint64{1, 2, 4} {
+		if got := mustNext(t, m); got != want {
+			t.Fatalf("Next = %d, want %d", got, want)
+		}
+	}
+
+	// Releasing the newest nonce just steps back
+	m.Release(4)
+	if got := mustNext(t, m); got != 4 {
+		t.Fatalf("Next after releasing the newest nonce = %d, want 4", got)
+	}
+
+	// A nonce released twice is handed out once
+	m.Release(1)
+	m.Release(1)
+	if a, b := mustNext(t, m), mustNext(t, m); a != 1 || b != 5 {
+		t.Fatalf("Next = %d, %d, want 1, 5", a, b)
+	}
+}
+
+func TestNonceManagerResyncAfterNonceTooLow(t *testing.T) {
+	source := &fakeNonceSource{}
+	m := NewNonceManager(source, testNonceAccount)
+	a, b := mustNext(t, m), mustNext(t, m)
+	m.MarkSent(b)
+	m.Release(a) // Nonce 0 waits in the released list
+
+	// Another process sent nonces 0 to 6 with the same key
+	source.set(7, nil)
+	sendErr := errors.New("nonce too low: next nonce 7, tx nonce 2")
+	if !isNonceError(sendErr) {
+		t.Fatalf("isNonceError(%q) = false", sendErr)
+	}
+	if err := m.Resync(context.Background()); err != nil {
+		t.Fatalf("Resync: %v", err)
+	}
+	if got := mustNext(t, m); got != 7 {
+		t.Fatalf("Next after resync = %d, want 7 (released 0 was used elsewhere)", got)
+	}
+
+	// An in-flight nonce keeps the node from rewinding past it...
+	source.set(3, nil)
+	if err := m.Resync(context.Background()); err != nil {
+		t.Fatalf("Resync: %v", err)
+	}
+	if got := mustNext(t, m); got != 8 {
+		t.Fatalf("Next with nonce 7 in flight = %d, want 8", got)
+	}
+
+	// ...but once nothing is in flight the node is authoritative, so transactions
+	// dropped from its mempool are sent again
+	m.MarkSent(7)
+	m.MarkSent(8)
+	if err := m.Resync(context.Background()); err != nil {
+		t.Fatalf("Resync: %v", err)
+	}
+	if got := mustNext(t, m); got != 3 {
+		t.Fatalf("Next after the node dropped transactions = %d, want 3", got)
+	}
+	m.MarkSent(3)
+
+	// A failed resync makes the next allocation ask the node again
+	source.set(0, errors.New("connection refused"))
+	if err := m.Resync(context.Background()); err == nil {
+		t.Fatal("Resync succeeded with the node down")
+	}
+	if _, err := m.Next(context.Background()); err == nil {
+		t.Fatal("Next succeeded with the node down")
+	}
+	source.set(10, nil)
+	if got := mustNext(t, m); got != 10 {
+		t.Fatalf("Next after the node came back = %d, want 10", got)
+	}
+}
+
+func TestIsNonceError(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want bool
+	}{
+		{"nonce too low: next nonce 7, tx nonce 2", true},
+		{"Nonce too high", true},
+		{"replacement transaction underpriced", true},
+		{"already known", false},
+		{"insufficient funds for gas * price + value", false},
+	}
+	for _, tt := range tests {
+		if got := isNonceError(errors.New(tt.msg)); got != tt.want {
+			t.Errorf("isNonceError(%q) = %t, want %t", tt.msg, got, tt.want)
+		}
+	}
+}
+
+func TestNonceManagerReuse(t *testing.T) {
+	m := NewNonceManager(&fakeNonceSource{pending: 3}, testNonceAccount)
+	if m.Reuse(3) {
+		t.Fatal("Reuse before the first sync succeeded")
+	}
+	a, b := mustNext(t, m), mustNext(t, m)
+	m.MarkSent(a)
+
+	if m.Reuse(b) {
+		t.Error("Reuse of a nonce in flight succeeded")
+	}
+	if m.Reuse(6) {
+		t.Error("Reuse past the next nonce succeeded")
+	}
+
+	// Re-sending a dropped transaction with its old nonce
+	if !m.Reuse(a) {
+		t.Fatalf("Reuse(%d) of a sent nonce failed", a)
+	}
+	m.MarkSent(a)
+	m.MarkSent(b)
+	if !m.Reuse(5) {
+		t.Fatal("Reuse of the next nonce failed")
+	}
+	if got := mustNext(t, m); got != 6 {
+		t.Fatalf("Next after reusing the next nonce = %d, want 6", got)
+	}
+
+	// A released nonce that is reused is not handed out again
+	m.Release(5)
+	if !m.Reuse(5) {
+		t.Fatal("Reuse of a released nonce failed")
+	}
+	if got := mustNext(t, m); got != 7 {
+		t.Fatalf("Next = %d, want 7", got)
+	}
+}
@@ -2,190 +2,518 @@ package blockchain
 
 import (
 	"context"
-	"crypto/ecdsa"
-	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
 	"math/big"
 	"os"
-	"strings"
+	"proofpot-backend/blockchain/contracts"
+	"proofpot-backend/contenthash"
+	"time"
 
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 // Global variables for blockchain connection (consider dependency injection for more complex apps)
 var (
-	ethClient       *ethclient.Client
+	ethClient       Client
 	contractAddress common.Address
 	contractABI     abi.ABI
-	auth            *bind.TransactOpts
-	backendKey      *ecdsa.PrivateKey
+	signers         *signerPool
+	chainIDValue    *big.Int
+	registry        RecipeRegistry
 )
 
-// Updated ABI for RecipeRegistry with Ownable and modified addRecipe
-const recipeRegistryABI = `[{"inputs":[{"internalType":"address","name":"initialOwner","type":"address"}],"stateMutability":"nonpayable","type":"constructor"},{"inputs":[{"internalType":"address","name":"owner","type":"address"}],"name":"OwnableInvalidOwner","type":"error"},{"inputs":[{"internalType":"address","name":"account","type":"address"}],"name":"OwnableUnauthorizedAccount","type":"error"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"address","name":"previousOwner","type":"address"},{"indexed":true,"internalType":"address","name":"newOwner","type":"address"}],"name":"OwnershipTransferred","type":"event"},{"anonymous":false,"inputs":[{"indexed":true,"internalType":"bytes32","name":"recipeHash","type":"bytes32"},{"indexed":true,"internalType":"address","name":"creator","type":"address"},{"indexed":false,"internalType":"uint256","name":"timestamp","type":"uint256"}],"name":"RecipeAdded","type":"event"},{"inputs":[{"internalType":"bytes32","name":"_recipeHash","type":"bytes32"},{"internalType":"address","name":"_creator","type":"address"}],"name":"addRecipe","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[],"name":"owner","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"name":"recipeOwners","outputs":[{"internalType":"address","name":"","type":"address"}],"stateMutability":"view","type":"function"},{"inputs":[{"internalType":"bytes32","name":"","type":"bytes32"}],"name":"recipeTimestamps","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},{"inputs":[],"name":"renounceOwnership","outputs":[],"stateMutability":"nonpayable","type":"function"},{"inputs":[{"internalType":"address","name":"newOwner","type":"address"}],"name":"transferOwnership","outputs":[],"stateMutability":"nonpayable","type":"function"}]`
+// Client is the part of the Ethereum RPC client the blockchain package uses.
+// It is satisfied by *ethclient.Client and by the simulated backend's client.
+type Client interface {
+	bind.ContractBackend
+	ethereum.BlockNumberReader
+	ethereum.ChainIDReader
+	ethereum.ChainStateReader
+	ethereum.TransactionReader
+}
 
 // InitBlockchain initializes the Ethereum client, contract instance, and transaction signer.
+// CHAIN_BACKEND selects the chain: "rpc" (default) dials SEPOLIA_RPC_URL, "simulated"
+// starts an in-process chain with a freshly deployed RecipeRegistry (see simulated.go).
 func InitBlockchain() error {
+	switch backend := os.Getenv("CHAIN_BACKEND"); backend {
+	case "", "rpc":
+		return initRPC()
+	case "simulated":
+		return initSimulated()
+	default:
+		return fmt.Errorf("%w: unknown CHAIN_BACKEND %q (expected \"rpc\" or \"simulated\")", ErrChainConfig, backend)
+	}
+}
+
+// initRPC connects to the chain configured in the environment.
+func initRPC() error {
 	// Load config from environment variables
-	rpcURL := os.Getenv("SEPOLIA_RPC_URL")   // Use the same var name as Hardhat config
-	pKey := os.Getenv("BACKEND_PRIVATE_KEY") // Use a dedicated backend key
+	rpcURL := os.Getenv("SEPOLIA_RPC_URL") // Use the same var name as Hardhat config
 	contractAddrStr := os.Getenv("RECIPE_REGISTRY_CONTRACT_ADDRESS")
 
-	if rpcURL == "" || pKey == "" || contractAddrStr == "" {
-		return fmt.Errorf("required blockchain environment variables not set (SEPOLIA_RPC_URL, BACKEND_PRIVATE_KEY, RECIPE_REGISTRY_CONTRACT_ADDRESS)")
+	if rpcURL == "" || contractAddrStr == "" {
+		return fmt.Errorf("%w: required blockchain environment variables not set (SEPOLIA_RPC_URL, RECIPE_REGISTRY_CONTRACT_ADDRESS)", ErrChainConfig)
+	}
+
+	// The signing keys come from keystore files, an external signer or, in
+	// development only, BACKEND_PRIVATE_KEY (see signer.go)
+	signerList, err := loadSigners()
+	if err != nil {
+		return err
 	}
 
 	// --- Connect to Ethereum client ---
-	var err error
-	ethClient, err = ethclient.Dial(rpcURL)
+	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
 		return fmt.Errorf("failed to connect to Ethereum client: %w", err)
 	}
 	log.Println("Connected to Ethereum client")
 
+	return configure(client, signerList, common.HexToAddress(contractAddrStr))
+}
+
+// configure points the package at a chain client, signer accounts and RecipeRegistry deployment.
+func configure(client Client, signerList []Signer, address common.Address) error {
+	ethClient = client
+
 	// --- Load Contract ABI and Address ---
-	contractAddress = common.HexToAddress(contractAddrStr)
-	parsedABI, err := abi.JSON(strings.NewReader(recipeRegistryABI))
+	// Both come from the generated bindings in the contracts package
+	contractAddress = address
+	parsedABI, err := contracts.RecipeRegistryMetaData.GetAbi()
 	if err != nil {
 		return fmt.Errorf("failed to parse contract ABI: %w", err)
 	}
-	contractABI = parsedABI
-	log.Println("Contract ABI loaded")
-
-	// --- Setup Transaction Signer (Auth) ---
-	privateKey, err := crypto.HexToECDSA(pKey)
+	contractABI = *parsedABI
+	registry, err = NewRecipeRegistry(contractAddress, ethClient)
 	if err != nil {
-		return fmt.Errorf("failed to load private key: %w", err)
-	}
-	backendKey = privateKey
-
-	publicKey := privateKey.Public()
-	publicKeyECDSA, ok := publicKey.(*ecdsa.PublicKey)
-	if !ok {
-		return fmt.Errorf("error casting public key to ECDSA")
+		return err
 	}
+	log.Println("Contract ABI loaded")
 
-	fromAddress := crypto.PubkeyToAddress(*publicKeyECDSA)
-	nonce, err := ethClient.PendingNonceAt(context.Background(), fromAddress)
+	chainID, err := ethClient.ChainID(context.Background())
 	if err != nil {
-		return fmt.Errorf("failed to get nonce: %w", err)
+		return fmt.Errorf("failed to get chain ID: %w", err)
 	}
+	chainIDValue = chainID
 
-	chainID, err := ethClient.ChainID(context.Background())
+	// --- Setup Transaction Signers ---
+	// Every key gets its own nonce sequence; registrations go to the least busy one
+	minBalance, err := loadMinSignerBalance()
 	if err != nil {
-		return fmt.Errorf("failed to get chain ID: %w", err)
+		return fmt.Errorf("%w: %v", ErrChainConfig, err)
 	}
+	signers = newSignerPool(ethClient, signerList, chainID, minBalance)
+	for _, acct := range signers.accounts {
+		if err := acct.nonces.Resync(context.Background()); err != nil {
+			return err
+		}
+	}
+	signers.refresh(context.Background())
 
-	auth, err = bind.NewKeyedTransactorWithChainID(privateKey, chainID)
+	// Gas limits are estimated per call (see estimateGas), with a safety multiplier
+	gasLimitMultiplier, err = loadGasLimitMultiplier()
 	if err != nil {
-		return fmt.Errorf("failed to create keyed transactor: %w", err)
+		return fmt.Errorf("%w: %v", ErrChainConfig, err)
 	}
-	auth.Nonce = big.NewInt(int64(nonce))
-	auth.Value = big.NewInt(0)     // Amount of ETH to send with tx (0 for this call)
-	auth.GasLimit = uint64(300000) // Set a suitable gas limit
-	// Fetch suggested gas price (can be dynamic)
-	gasPrice, err := ethClient.SuggestGasPrice(context.Background())
+
+	// Fees are computed per transaction (EIP-1559), bounded by the configured caps
+	feeCaps, err = loadFeeCaps()
 	if err != nil {
-		log.Printf("Warning: failed to suggest gas price: %v. Using default.", err)
-		// Set a default or handle error differently if needed
-		// auth.GasPrice = big.NewInt(20000000000) // Example default: 20 Gwei
-	} else {
-		auth.GasPrice = gasPrice
+		return fmt.Errorf("%w: %v", ErrChainConfig, err)
 	}
+	replacementCfg = loadReplacementConfig()
 
-	log.Printf("Blockchain setup complete. Using address: %s", fromAddress.Hex())
+	markInitialized()
+	log.Printf("Blockchain setup complete. Using %d signer account(s): %s", len(signers.accounts), signers.addresses())
 	return nil
 }
 
+// Registration describes a successfully mined addRecipe transaction.
+type Registration struct {
+	TxHash      string
+	BlockNumber uint64
+	BlockTime   time.Time
+}
+
 // RegisterRecipeOnChain interacts with the deployed RecipeRegistry contract to add a recipe hash.
-func RegisterRecipeOnChain(contentHashHex string, creatorAddressStr string) error {
-	if ethClient == nil || auth == nil || backendKey == nil {
-		return fmt.Errorf("blockchain service not initialized correctly")
+// onSubmitted (optional) is called for the transaction as soon as it is broadcast, and again
+// for every fee-bumped replacement. It blocks until one of them is mined or ctx is done.
+func RegisterRecipeOnChain(ctx context.Context, contentHashHex string, creatorAddressStr string, onSubmitted func(SubmittedTx)) (*Registration, error) {
+	if err := ready(); err != nil {
+		return nil, err
 	}
 
 	log.Printf("Attempting to register hash %s for creator %s on chain", contentHashHex, creatorAddressStr)
 
+	callData, build, err := addRecipeCall(contentHashHex, creatorAddressStr)
+	if err != nil {
+		return nil, err
+	}
+	return submitTransaction(ctx, callData, build, onSubmitted)
+}
+
+// ResumeRecipeRegistration is RegisterRecipeOnChain for a recipe whose earlier attempt
+// already broadcast the transactions in previous, e.g. because it timed out or was
+// interrupted by a shutdown. Rather than sending a second addRecipe, it picks up
+// where that attempt stopped: it returns the registration if one of them was mined,
+// waits for (and speeds up) the ones still pending, and re-sends with the same nonce
+// if the node dropped them. Only if that nonce went to another transaction does it
+// register the recipe from scratch.
+func ResumeRecipeRegistration(ctx context.Context, contentHashHex string, creatorAddressStr string, previous []SubmittedTx, onSubmitted func(SubmittedTx)) (*Registration, error) {
+	if len(previous) == 0 {
+		return RegisterRecipeOnChain(ctx, contentHashHex, creatorAddressStr, onSubmitted)
+	}
+	if err := ready(); err != nil {
+		return nil, err
+	}
+
+	callData, build, err := addRecipeCall(contentHashHex, creatorAddressStr)
+	if err != nil {
+		return nil, err
+	}
+	reg, resumed, err := resumeTransaction(ctx, callData, build, previous, onSubmitted)
+	if resumed || err != nil {
+		return reg, err
+	}
+	log.Printf("Previous transactions for hash %s can no longer be mined, registering it again", contentHashHex)
+	return submitTransaction(ctx, callData, build, onSubmitted)
+}
+
+// addRecipeCall validates the arguments of addRecipe and returns its call data and
+// a builder for the transaction, as submitTransaction takes them.
+func addRecipeCall(contentHashHex string, creatorAddressStr string) ([]byte, func(opts *bind.TransactOpts) (*types.Transaction, error), error) {
 	// Convert the hex hash string (e.g., "0x...") to [32]byte
-	hashBytes, err := hex.DecodeString(strings.TrimPrefix(contentHashHex, "0x"))
-	if err != nil || len(hashBytes) != 32 {
-		return fmt.Errorf("invalid content hash format: %w", err)
+	contentHash, err := contenthash.Decode(contentHashHex)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid content hash format: %v", ErrInvalidRegistration, err)
 	}
-	var contentHash [32]byte
-	copy(contentHash[:], hashBytes)
 
 	// Convert creator address string to common.Address
 	if !common.IsHexAddress(creatorAddressStr) {
-		return fmt.Errorf("invalid creator address format: %s", creatorAddressStr)
+		return nil, nil, fmt.Errorf("%w: invalid creator address format: %s", ErrInvalidRegistration, creatorAddressStr)
 	}
 	creatorAddress := common.HexToAddress(creatorAddressStr)
 
-	// Create a contract instance bound to the specific address
-	// Note: We use the low-level `abi.Pack` and `ethClient.SendTransaction` here
-	// because we don't have the generated Go bindings for the contract.
-	// Alternatively, use `abigen` to generate Go bindings for a typed interface.
-
-	// Pack the data for the addRecipe function call, now including creatorAddress
+	// Pack the call data for gas estimation; the transaction itself is built by the bindings
 	callData, err := contractABI.Pack("addRecipe", contentHash, creatorAddress)
 	if err != nil {
-		return fmt.Errorf("failed to pack data for addRecipe: %w", err)
+		return nil, nil, fmt.Errorf("failed to pack data for addRecipe: %w", err)
 	}
+	return callData, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return registry.AddRecipe(opts, contentHash, creatorAddress)
+	}, nil
+}
 
-	// Create the transaction
-	// Ensure nonce management is robust, fetch latest pending nonce before sending
-	pendingNonce, err := ethClient.PendingNonceAt(context.Background(), auth.From)
+// submitTransaction sends a RecipeRegistry call from one of the signer accounts and
+// waits for it to be mined. callData is only used for gas estimation; build must
+// create the same call with the given options. onSubmitted is called as described
+// for RegisterRecipeOnChain.
+func submitTransaction(ctx context.Context, callData []byte, build func(opts *bind.TransactOpts) (*types.Transaction, error), onSubmitted func(SubmittedTx)) (*Registration, error) {
+	// Pick a signer account. Each has its own nonces, so registrations sent from
+	// different accounts never wait on each other.
+	var acct *signerAccount
+	var gasLimit uint64
+	var err error
+	for {
+		acct, err = signers.acquire()
+		if err != nil {
+			return nil, err
+		}
+
+		// Estimate gas before signing anything; a revert (e.g. "Recipe hash already exists")
+		// comes back as a *RevertError without spending gas or a nonce
+		gasLimit, err = estimateGas(ctx, acct.address(), callData)
+		if err == nil {
+			break
+		}
+		signers.release(acct)
+		// An account whose registrar role was revoked hands the job to the others.
+		// Without any left the job waits until the role is granted again.
+		if errors.Is(err, ErrNotRegistrar) || errors.Is(err, ErrNotOwner) {
+			if signers.revoke(acct) {
+				continue
+			}
+			return nil, fmt.Errorf("%w: %s was the last authorized signer: %w", ErrNoSignerAvailable, acct.address().Hex(), err)
+		}
+		return nil, err
+	}
+	defer signers.release(acct)
+
+	// Fetch current EIP-1559 fees, bounded by MAX_FEE_PER_GAS_GWEI / MAX_PRIORITY_FEE_GWEI
+	fees, err := suggestDynamicFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Allocate the nonce last so it is held for as short as possible. broadcast
+	// marks it sent or releases it.
+	nonce, err := acct.nonces.Next(ctx)
+	if err != nil {
+		return nil, err
+	}
+	signedTx, err := broadcast(ctx, acct, nonce, gasLimit, fees, build)
 	if err != nil {
-		return fmt.Errorf("failed to get pending nonce before sending tx: %w", err)
+		return nil, err
 	}
-	auth.Nonce = big.NewInt(int64(pendingNonce))
-	// Re-fetch gas price for potentially better estimate
-	gasPrice, err := ethClient.SuggestGasPrice(context.Background())
-	if err == nil {
-		auth.GasPrice = gasPrice
+	if onSubmitted != nil {
+		onSubmitted(newSubmittedTx(acct.address(), signedTx, nil))
 	}
+	return confirmMined(ctx, acct, []*types.Transaction{signedTx}, onSubmitted)
+}
 
-	tx := types.NewTransaction(auth.Nonce.Uint64(), contractAddress, auth.Value, auth.GasLimit, auth.GasPrice, callData)
+// resumeTransaction continues the registration that broadcast previous, as described
+// for ResumeRecipeRegistration. It reports false, without an error, if the caller
+// has to start over with submitTransaction.
+func resumeTransaction(ctx context.Context, callData []byte, build func(opts *bind.TransactOpts) (*types.Transaction, error), previous []SubmittedTx, onSubmitted func(SubmittedTx)) (*Registration, bool, error) {
+	// One of them may have been mined while nobody was waiting for it
+	for _, sub := range previous {
+		receipt, err := ethClient.TransactionReceipt(ctx, common.HexToHash(sub.Hash))
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to get receipt of previous tx %s: %w", sub.Hash, err)
+		}
+		if receipt.Status == types.ReceiptStatusSuccessful {
+			log.Printf("Previous tx %s was mined in block %d", sub.Hash, receipt.BlockNumber)
+			reg, err := registrationFromReceipt(ctx, receipt)
+			return reg, true, err
+		}
+		// A reverted transaction used up its nonce without registering anything
+	}
 
-	// Sign the transaction
-	chainID, err := ethClient.ChainID(context.Background())
+	// Replacements share the nonce of the latest transaction; earlier nonces were
+	// given up by the attempts that moved on from them
+	latest := previous[len(previous)-1]
+	from := common.HexToAddress(latest.From)
+	acct := signers.acquireAddress(from)
+	if acct == nil {
+		log.Printf("Signer %s of previous tx %s is no longer configured", from.Hex(), latest.Hash)
+		return nil, false, nil
+	}
+	defer signers.release(acct)
+
+	var pending []*types.Transaction
+	for _, sub := range previous {
+		if common.HexToAddress(sub.From) != from || sub.Nonce != latest.Nonce {
+			continue
+		}
+		tx, isPending, err := ethClient.TransactionByHash(ctx, common.HexToHash(sub.Hash))
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to look up previous tx %s: %w", sub.Hash, err)
+		}
+		if isPending {
+			pending = append(pending, tx)
+		}
+	}
+	if len(pending) > 0 {
+		log.Printf("Resuming wait for previous tx %s (from %s, nonce %d)", pending[len(pending)-1].Hash().Hex(), from.Hex(), latest.Nonce)
+		reg, err := confirmMined(ctx, acct, pending, onSubmitted)
+		return reg, true, err
+	}
+
+	// The node dropped them. Send the call again with their nonce, unless it was
+	// used by another transaction in the meantime.
+	next, err := ethClient.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to get pending nonce: %w", err)
+	}
+	if next > latest.Nonce {
+		return nil, false, nil
+	}
+	gasLimit, err := estimateGas(ctx, from, callData)
+	if err != nil {
+		if errors.Is(err, ErrNotRegistrar) || errors.Is(err, ErrNotOwner) {
+			return nil, false, nil
+		}
+		return nil, true, err
+	}
+	fees, err := suggestDynamicFees(ctx)
+	if err != nil {
+		return nil, true, err
+	}
+	if !acct.nonces.Reuse(latest.Nonce) {
+		return nil, false, nil
+	}
+	log.Printf("Previous tx %s (from %s, nonce %d) was dropped, sending it again", latest.Hash, from.Hex(), latest.Nonce)
+	signedTx, err := broadcast(ctx, acct, latest.Nonce, gasLimit, fees, build)
 	if err != nil {
-		return fmt.Errorf("failed to get chain ID for signing: %w", err)
+		return nil, true, err
 	}
-	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(chainID), backendKey)
+	if onSubmitted != nil {
+		onSubmitted(newSubmittedTx(acct.address(), signedTx, nil))
+	}
+	reg, err := confirmMined(ctx, acct, []*types.Transaction{signedTx}, onSubmitted)
+	return reg, true, err
+}
+
+// sendTimeout bounds a broadcast, which does not stop when the caller's context
+// is cancelled: a send interrupted halfway may still have reached the node, and
+// its nonce must then not be handed to another transaction.
+const sendTimeout = 30 * time.Second
+
+// broadcast signs the call built by build with the given nonce, which must be in
+// flight for acct, and sends it. The nonce is marked sent, or released if the
+// transaction never reached the node.
+func broadcast(ctx context.Context, acct *signerAccount, nonce uint64, gasLimit uint64, fees *DynamicFees, build func(opts *bind.TransactOpts) (*types.Transaction, error)) (*types.Transaction, error) {
+	// Build and sign the transaction without sending it, so a failed send can
+	// release the nonce
+	signedTx, err := build(&bind.TransactOpts{
+		From:      acct.opts.From,
+		Signer:    acct.opts.Signer,
+		Nonce:     new(big.Int).SetUint64(nonce),
+		Value:     acct.opts.Value,
+		GasTipCap: fees.GasTipCap,
+		GasFeeCap: fees.GasFeeCap,
+		GasLimit:  gasLimit,
+		Context:   ctx,
+		NoSend:    true,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to sign transaction: %w", err)
+		acct.nonces.Release(nonce)
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
 	}
 
 	// Send the transaction
-	err = ethClient.SendTransaction(context.Background(), signedTx)
-	if err != nil {
-		// Potentially update nonce if error is nonce-related for retries
-		// auth.Nonce.Add(auth.Nonce, big.NewInt(1)) // Example: Increment nonce for next attempt
-		return fmt.Errorf("failed to send transaction: %w", err)
+	err = sendTransaction(ctx, signedTx)
+	if err != nil && !isAlreadyKnown(err) {
+		acct.nonces.Release(nonce)
+		if isNonceError(err) {
+			// Our view of the account is stale (e.g. another process used the key)
+			if resyncErr := acct.nonces.Resync(ctx); resyncErr != nil {
+				log.Printf("Warning: nonce resync failed for %s: %v", acct.address().Hex(), resyncErr)
+			}
+		}
+		return nil, fmt.Errorf("failed to send transaction (from %s, nonce %d): %w", acct.address().Hex(), nonce, err)
 	}
+	acct.nonces.MarkSent(nonce)
 
-	log.Printf("Transaction sent successfully: %s", signedTx.Hash().Hex())
+	log.Printf("Transaction sent successfully: %s (from %s, nonce %d, max fee %s wei, tip %s wei)", signedTx.Hash().Hex(), acct.address().Hex(), nonce, fees.GasFeeCap, fees.GasTipCap)
+	return signedTx, nil
+}
 
-	// --- Optional: Wait for Transaction Receipt ---
-	// Generally good practice to confirm the transaction was mined.
-	// This blocks until the transaction is included in a block.
-	receipt, err := bind.WaitMined(context.Background(), ethClient, signedTx)
+// sendTransaction broadcasts tx within sendTimeout, ignoring ctx's cancellation.
+func sendTransaction(ctx context.Context, tx *types.Transaction) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sendTimeout)
+	defer cancel()
+	return ethClient.SendTransaction(ctx, tx)
+}
+
+// confirmMined waits until one of the transactions sharing a nonce in sent (or a
+// fee-bumped replacement) is mined and checks that it succeeded.
+func confirmMined(ctx context.Context, acct *signerAccount, sent []*types.Transaction, onSubmitted func(SubmittedTx)) (*Registration, error) {
+	// --- Wait for Transaction Receipt ---
+	// Blocks until the transaction (or a fee-bumped replacement, if it gets stuck)
+	// is included in a block.
+	receipt, minedTx, err := waitMined(ctx, acct, sent, onSubmitted)
 	if err != nil {
-		return fmt.Errorf("failed to get transaction receipt: %w", err)
+		return nil, fmt.Errorf("failed to get transaction receipt: %w", err)
 	}
 	if receipt.Status == 0 {
-		// Transaction reverted
+		// Transaction reverted; replay it to find out why (receipts carry no reason)
 		log.Printf("Transaction reverted! Receipt: %+v", receipt)
-		return fmt.Errorf("transaction reverted on chain (Tx: %s)", signedTx.Hash().Hex())
+		reason := replayRevert(ctx, minedTx, acct.address(), receipt.BlockNumber)
+		return nil, fmt.Errorf("transaction reverted on chain (Tx: %s): %w", minedTx.Hash().Hex(), reason)
 	}
 
-	log.Printf("Transaction confirmed successfully! Block: %d, Tx Hash: %s", receipt.BlockNumber, signedTx.Hash().Hex())
-	// --- End Optional Wait ---
+	log.Printf("Transaction confirmed successfully! Block: %d, Tx Hash: %s", receipt.BlockNumber, minedTx.Hash().Hex())
+	// --- End Wait ---
+	return registrationFromReceipt(ctx, receipt)
+}
 
-	return nil
+// registrationFromReceipt describes the successful transaction of receipt.
+func registrationFromReceipt(ctx context.Context, receipt *types.Receipt) (*Registration, error) {
+	// Use the block timestamp as the confirmation time, matching recipeTimestamps on chain
+	header, err := ethClient.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block header for confirmed tx: %w", err)
+	}
+
+	return &Registration{
+		TxHash:      receipt.TxHash.Hex(),
+		BlockNumber: receipt.BlockNumber.Uint64(),
+		BlockTime:   time.Unix(int64(header.Time), 0).UTC(),
+	}, nil
+}
+
+// RecipeRecord is what the RecipeRegistry contract stores for a recipe hash.
+type RecipeRecord struct {
+	Creator   common.Address // Zero address if the hash was never registered
+	Timestamp time.Time      // Block timestamp of the registration
+}
+
+// Registered reports whether the contract knows about the hash at all.
+func (r *RecipeRecord) Registered() bool {
+	return r.Creator != (common.Address{})
+}
+
+// GetRecipeRecord reads recipeOwners and recipeTimestamps for a content hash from the contract.
+func GetRecipeRecord(ctx context.Context, contentHash [32]byte) (*RecipeRecord, error) {
+	if err := ready(); err != nil {
+		return nil, err
+	}
+	return registry.GetRecipeRecord(ctx, contentHash)
+}
+
+// ContractAddress returns the configured RecipeRegistry address.
+func ContractAddress() common.Address {
+	return contractAddress
+}
+
+// ChainID returns the chain ID of the connected chain.
+func ChainID() (*big.Int, error) {
+	if err := ready(); err != nil {
+		return nil, err
+	}
+	return new(big.Int).Set(chainIDValue), nil
+}
+
+// RecipeAddedEvent is a decoded RecipeAdded log emitted by the RecipeRegistry contract.
+type RecipeAddedEvent struct {
+	RecipeHash  [32]byte
+	Creator     common.Address
+	Timestamp   time.Time
+	BlockNumber uint64
+	BlockHash   common.Hash
+	TxHash      common.Hash
+	LogIndex    uint
+}
+
+// FilterRecipeAdded returns the RecipeAdded events emitted in the inclusive block range.
+func FilterRecipeAdded(ctx context.Context, fromBlock, toBlock uint64) ([]RecipeAddedEvent, error) {
+	if err := ready(); err != nil {
+		return nil, err
+	}
+	return registry.FilterRecipeAdded(ctx, fromBlock, toBlock)
+}
+
+// LatestBlockNumber returns the current head block number of the connected chain.
+func LatestBlockNumber(ctx context.Context) (uint64, error) {
+	if err := ready(); err != nil {
+		return 0, err
+	}
+	return ethClient.BlockNumber(ctx)
+}
+
+// BlockHashAt returns the canonical block hash at the given height.
+func BlockHashAt(ctx context.Context, number uint64) (common.Hash, error) {
+	if err := ready(); err != nil {
+		return common.Hash{}, err
+	}
+	header, err := ethClient.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get header %d: %w", number, err)
+	}
+	return header.Hash(), nil
 }
@@ -0,0 +1,161 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// resumeResult is what ResumeRecipeRegistration returned, plus the transactions it sent.
+type resumeResult struct {
+	reg  *Registration
+	err  error
+	sent []SubmittedTx
+}
+
+func resume(ctx context.Context, hashHex string, creator common.Address, previous ...SubmittedTx) resumeResult {
+	var res resumeResult
+	res.reg, res.err = ResumeRecipeRegistration(ctx, hashHex, creator.Hex(), previous, func(sub SubmittedTx) {
+		res.sent = append(res.sent, sub)
+	})
+	return res
+}
+
+func TestResumeWaitsForInterruptedTx(t *testing.T) {
+	chain := newTestChain(t, nil)
+	// Without auto-mining, the transaction stays pending until the test commits a block
+	if err := configure(chain.Backend.Client(), []Signer{NewKeySigner(chain.Owner)}, chain.Contract); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	replacementCfg.PollInterval = 10 * time.Millisecond
+	ctx := testContext(t)
+	hashHex, hash := testHash("interrupted")
+	creator := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+
+	// The attempt is cancelled right after the broadcast, like on shutdown
+	attemptCtx, cancel := context.WithCancel(ctx)
+	var first SubmittedTx
+	_, err := RegisterRecipeOnChain(attemptCtx, hashHex, creator.Hex(), func(sub SubmittedTx) {
+		first = sub
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("RegisterRecipeOnChain = %v, want context.Canceled", err)
+	}
+
+	done := make(chan resumeResult)
+	go func() { done <- resume(ctx, hashHex, creator, first) }()
+	var res resumeResult
+	for mined := false; !mined; {
+		select {
+		case res = <-done:
+			mined = true
+		case <-time.After(20 * time.Millisecond):
+			chain.Backend.Commit()
+		}
+	}
+
+	if res.err != nil {
+		t.Fatalf("ResumeRecipeRegistration: %v", res.err)
+	}
+	if res.reg.TxHash != first.Hash || len(res.sent) != 0 {
+		t.Errorf("resumed registration mined %s and sent %d transactions, want %s and none", res.reg.TxHash, len(res.sent), first.Hash)
+	}
+	owner := crypto.PubkeyToAddress(chain.Owner.PublicKey)
+	if nonce, err := ethClient.NonceAt(ctx, owner, nil); err != nil || nonce != first.Nonce+1 {
+		t.Errorf("owner nonce = %d, %v, want %d (one registration)", nonce, err, first.Nonce+1)
+	}
+	if record, err := GetRecipeRecord(ctx, hash); err != nil || record.Creator != creator {
+		t.Errorf("GetRecipeRecord = %+v, %v", record, err)
+	}
+}
+
+func TestResumeMinedTx(t *testing.T) {
+	newTestChain(t, nil)
+	ctx := testContext(t)
+	hashHex, _ := testHash("mined while nobody waited")
+	creator := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+
+	var first SubmittedTx
+	reg, err := RegisterRecipeOnChain(ctx, hashHex, creator.Hex(), func(sub SubmittedTx) { first = sub })
+	if err != nil {
+		t.Fatalf("RegisterRecipeOnChain: %v", err)
+	}
+
+	res := resume(ctx, hashHex, creator, first)
+	if res.err != nil {
+		t.Fatalf("ResumeRecipeRegistration: %v", res.err)
+	}
+	if *res.reg != *reg || len(res.sent) != 0 {
+		t.Errorf("resumed = %+v with %d new transactions, want %+v and none", res.reg, len(res.sent), reg)
+	}
+}
+
+func TestResumeResendsDroppedTx(t *testing.T) {
+	chain := newTestChain(t, nil)
+	ctx := testContext(t)
+	hashHex, hash := testHash("dropped")
+	creator := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	owner := crypto.PubkeyToAddress(chain.Owner.PublicKey)
+
+	// A transaction the node no longer knows, with the account's next nonce
+	nonce, err := ethClient.PendingNonceAt(ctx, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	droppedHash, _ := testHash("dropped tx")
+	dropped := SubmittedTx{Hash: droppedHash, From: owner.Hex(), Nonce: nonce}
+
+	res := resume(ctx, hashHex, creator, dropped)
+	if res.err != nil {
+		t.Fatalf("ResumeRecipeRegistration: %v", res.err)
+	}
+	if len(res.sent) != 1 || res.sent[0].Nonce != nonce || res.reg.TxHash != res.sent[0].Hash {
+		t.Fatalf("sent %+v, mined %s, want one transaction with nonce %d", res.sent, res.reg.TxHash, nonce)
+	}
+	if record, err := GetRecipeRecord(ctx, hash); err != nil || record.Creator != creator {
+		t.Errorf("GetRecipeRecord = %+v, %v", record, err)
+	}
+
+	// The nonce manager moved past the re-sent nonce
+	otherHex, _ := testHash("after the resend")
+	var next SubmittedTx
+	if _, err := RegisterRecipeOnChain(ctx, otherHex, creator.Hex(), func(sub SubmittedTx) { next = sub }); err != nil {
+		t.Fatalf("RegisterRecipeOnChain: %v", err)
+	}
+	if next.Nonce != nonce+1 {
+		t.Errorf("next registration used nonce %d, want %d", next.Nonce, nonce+1)
+	}
+}
+
+func TestResumeRegistersAgainWhenNonceWasTaken(t *testing.T) {
+	chain := newTestChain(t, nil)
+	ctx := testContext(t)
+	creator := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	owner := crypto.PubkeyToAddress(chain.Owner.PublicKey)
+
+	// The dropped transaction's nonce went to another registration
+	otherHex, _ := testHash("other recipe")
+	var other SubmittedTx
+	if _, err := RegisterRecipeOnChain(ctx, otherHex, creator.Hex(), func(sub SubmittedTx) { other = sub }); err != nil {
+		t.Fatalf("RegisterRecipeOnChain: %v", err)
+	}
+	droppedHash, _ := testHash("dropped tx")
+	dropped := SubmittedTx{Hash: droppedHash, From: owner.Hex(), Nonce: other.Nonce}
+
+	hashHex, hash := testHash("registered again")
+	res := resume(ctx, hashHex, creator, dropped)
+	if res.err != nil {
+		t.Fatalf("ResumeRecipeRegistration: %v", res.err)
+	}
+	if len(res.sent) != 1 || res.sent[0].Nonce != other.Nonce+1 {
+		t.Fatalf("sent %+v, want one transaction with nonce %d", res.sent, other.Nonce+1)
+	}
+	if record, err := GetRecipeRecord(ctx, hash); err != nil || record.Creator != creator {
+		t.Errorf("GetRecipeRecord = %+v, %v", record, err)
+	}
+}
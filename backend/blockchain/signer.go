@@ -0,0 +1,290 @@
+package blockchain
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/external"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Signer signs transactions for the backend's account. Implementations must return
+// a transaction signed by Address(), ready to broadcast.
+type Signer interface {
+	Address() common.Address
+	SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+	// SignText signs text as an EIP-191 personal message (as personal_sign does) and
+	// returns the 65 byte [R || S || V] signature with V = 0 or 1.
+	SignText(text []byte) ([]byte, error)
+}
+
+// Signer kinds selectable with the SIGNER environment variable.
+const (
+	SignerKeystore = "keystore" // go-ethereum encrypted keystore JSON (KEYSTORE_PATH)
+	SignerClef     = "clef"     // Clef-compatible external signer over JSON-RPC (CLEF_URL)
+	SignerDevKey   = "dev-key"  // Plaintext hex key from BACKEND_PRIVATE_KEY, development only
+)
+
+// loadSigners builds the signer accounts selected by SIGNER (default "keystore").
+// KEYSTORE_PATH, CLEF_ACCOUNT and BACKEND_PRIVATE_KEY accept comma-separated lists
+// to register from several keys at once (see accounts.go). Configuration problems
+// are wrapped in ErrChainConfig.
+func loadSigners() ([]Signer, error) {
+	kind := os.Getenv("SIGNER")
+	if kind == "" {
+		kind = SignerKeystore
+	}
+
+	var list []Signer
+	switch kind {
+	case SignerKeystore:
+		paths := splitList(os.Getenv("KEYSTORE_PATH"))
+		if len(paths) == 0 {
+			return nil, fmt.Errorf("%w: KEYSTORE_PATH not set (set SIGNER to choose another signer)", ErrChainConfig)
+		}
+		// All keystore files share one passphrase
+		passphrase, err := keystorePassphrase()
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range paths {
+			signer, err := NewKeystoreSigner(path, passphrase)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, signer)
+		}
+
+	case SignerClef:
+		endpoint := os.Getenv("CLEF_URL")
+		if endpoint == "" {
+			return nil, fmt.Errorf("%w: CLEF_URL not set", ErrChainConfig)
+		}
+		var addresses []common.Address
+		for _, raw := range splitList(os.Getenv("CLEF_ACCOUNT")) {
+			if !common.IsHexAddress(raw) {
+				return nil, fmt.Errorf("%w: invalid CLEF_ACCOUNT %q", ErrChainConfig, raw)
+			}
+			addresses = append(addresses, common.HexToAddress(raw))
+		}
+		// Dial failures are connectivity problems, not configuration errors
+		clefSigners, err := NewClefSigners(endpoint, addresses)
+		if err != nil {
+			return nil, err
+		}
+		list = clefSigners
+
+	case SignerDevKey:
+		keys := splitList(os.Getenv("BACKEND_PRIVATE_KEY"))
+		if len(keys) == 0 {
+			return nil, fmt.Errorf("%w: SIGNER=dev-key requires BACKEND_PRIVATE_KEY", ErrChainConfig)
+		}
+		for _, pKey := range keys {
+			privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(pKey, "0x"))
+			if err != nil {
+				return nil, fmt.Errorf("%w: failed to load private key: %v", ErrChainConfig, err)
+			}
+			list = append(list, NewKeySigner(privateKey))
+		}
+		log.Println("WARNING: Signing with plaintext private keys from BACKEND_PRIVATE_KEY. Use SIGNER=keystore or SIGNER=clef outside development.")
+
+	default:
+		return nil, fmt.Errorf("%w: unknown SIGNER %q (expected %q, %q or %q)", ErrChainConfig, kind, SignerKeystore, SignerClef, SignerDevKey)
+	}
+
+	// The same key twice would share an address but not a nonce sequence
+	seen := make(map[common.Address]bool)
+	for _, signer := range list {
+		if seen[signer.Address()] {
+			return nil, fmt.Errorf("%w: signer account %s is configured more than once", ErrChainConfig, signer.Address().Hex())
+		}
+		seen[signer.Address()] = true
+	}
+	return list, nil
+}
+
+// splitList splits a comma-separated environment value, dropping empty entries.
+func splitList(raw string) []string {
+	var out []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// keystorePassphrase reads the keystore passphrase from KEYSTORE_PASSPHRASE_FILE
+// (preferred, e.g. a mounted secret) or KEYSTORE_PASSPHRASE.
+func keystorePassphrase() (string, error) {
+	if path := os.Getenv("KEYSTORE_PASSPHRASE_FILE"); path != "" {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("%w: failed to read KEYSTORE_PASSPHRASE_FILE: %v", ErrChainConfig, err)
+		}
+		// Editors and `echo` add a trailing newline that is not part of the passphrase
+		return strings.TrimRight(string(raw), "\r\n"), nil
+	}
+	if passphrase, ok := os.LookupEnv("KEYSTORE_PASSPHRASE"); ok {
+		return passphrase, nil
+	}
+	return "", fmt.Errorf("%w: set KEYSTORE_PASSPHRASE_FILE or KEYSTORE_PASSPHRASE for the keystore", ErrChainConfig)
+}
+
+// keySigner signs with an in-memory private key.
+type keySigner struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewKeySigner returns a signer for an in-memory private key.
+func NewKeySigner(key *ecdsa.PrivateKey) Signer {
+	return &keySigner{key: key, address: crypto.PubkeyToAddress(key.PublicKey)}
+}
+
+func (s *keySigner) Address() common.Address {
+	return s.address
+}
+
+func (s *keySigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+func (s *keySigner) SignText(text []byte) ([]byte, error) {
+	return crypto.Sign(accounts.TextHash(text), s.key)
+}
+
+// NewKeystoreSigner decrypts a go-ethereum keystore JSON file (as written by
+// `geth account new` or `clef newaccount`) and signs with the key it contains.
+func NewKeystoreSigner(path, passphrase string) (Signer, error) {
+	keyJSON, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to read keystore file: %v", ErrChainConfig, err)
+	}
+	key, err := keystore.DecryptKey(keyJSON, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to decrypt keystore file %s: %v", ErrChainConfig, path, err)
+	}
+	log.Printf("Loaded signer key %s from keystore %s", key.Address.Hex(), path)
+	return NewKeySigner(key.PrivateKey), nil
+}
+
+// clefSigner delegates signing to a Clef-compatible external signer, so the key
+// never enters this process. Clef's rules or operator decide whether to sign.
+type clefSigner struct {
+	signer  *external.ExternalSigner
+	account accounts.Account
+}
+
+// NewClefSigner connects to the external signer at endpoint (http(s)://, ws(s)://
+// or an IPC path). account selects the signing account; nil uses the first account
+// the signer exposes.
+func NewClefSigner(endpoint string, account *common.Address) (Signer, error) {
+	var addresses []common.Address
+	if account != nil {
+		addresses = append(addresses, *account)
+	}
+	list, err := NewClefSigners(endpoint, addresses)
+	if err != nil {
+		return nil, err
+	}
+	return list[0], nil
+}
+
+// NewClefSigners is NewClefSigner for several accounts behind one external signer
+// connection. An empty list uses the first account the signer exposes.
+func NewClefSigners(endpoint string, addresses []common.Address) ([]Signer, error) {
+	ext, err := external.NewExternalSigner(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to external signer: %w", err)
+	}
+
+	if len(addresses) == 0 {
+		available := ext.Accounts()
+		if len(available) == 0 {
+			return nil, fmt.Errorf("external signer exposes no accounts")
+		}
+		addresses = []common.Address{available[0].Address}
+	}
+
+	list := make([]Signer, 0, len(addresses))
+	for _, address := range addresses {
+		selected := accounts.Account{Address: address}
+		if !ext.Contains(selected) {
+			return nil, fmt.Errorf("%w: external signer does not manage account %s", ErrChainConfig, address.Hex())
+		}
+		log.Printf("Using external signer at %s for account %s", endpoint, address.Hex())
+		list = append(list, &clefSigner{signer: ext, account: selected})
+	}
+	return list, nil
+}
+
+func (s *clefSigner) Address() common.Address {
+	return s.account.Address
+}
+
+func (s *clefSigner) SignTx(tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	signed, err := s.signer.SignTx(s.account, tx, chainID)
+	if err != nil {
+		return nil, fmt.Errorf("external signer refused transaction: %w", err)
+	}
+	// Don't trust the signer to have signed what we asked for, with the right key
+	if !sameUnsignedFields(tx, signed) {
+		return nil, fmt.Errorf("external signer returned a different transaction")
+	}
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	if err != nil || sender != s.account.Address {
+		return nil, fmt.Errorf("external signer returned a transaction not signed by %s", s.account.Address.Hex())
+	}
+	return signed, nil
+}
+
+func (s *clefSigner) SignText(text []byte) ([]byte, error) {
+	signature, err := s.signer.SignText(s.account, text)
+	if err != nil {
+		return nil, fmt.Errorf("external signer refused to sign text: %w", err)
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash(text), signature)
+	if err != nil || crypto.PubkeyToAddress(*pub) != s.account.Address {
+		return nil, fmt.Errorf("external signer returned a text signature not made by %s", s.account.Address.Hex())
+	}
+	return signature, nil
+}
+
+// sameUnsignedFields reports whether a and b are the same transaction apart from the signature.
+func sameUnsignedFields(a, b *types.Transaction) bool {
+	return a.Type() == b.Type() &&
+		a.Nonce() == b.Nonce() &&
+		a.Gas() == b.Gas() &&
+		a.GasFeeCap().Cmp(b.GasFeeCap()) == 0 &&
+		a.GasTipCap().Cmp(b.GasTipCap()) == 0 &&
+		a.Value().Cmp(b.Value()) == 0 &&
+		bytes.Equal(a.Data(), b.Data()) &&
+		(a.To() == nil) == (b.To() == nil) &&
+		(a.To() == nil || *a.To() == *b.To())
+}
+
+// transactOpts returns bind options that sign with signer. Callers fill in
+// nonce, fees and gas.
+func transactOpts(signer Signer, chainID *big.Int) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: signer.Address(),
+		Signer: func(from common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if from != signer.Address() {
+				return nil, bind.ErrNotAuthorized
+			}
+			return signer.SignTx(tx, chainID)
+		},
+		Value: big.NewInt(0), // Amount of ETH to send with tx (0 for this call)
+	}
+}
@@ -0,0 +1,251 @@
+package blockchain
+
+import (
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+var testChainID = big.NewInt(1337)
+
+func testTx() *types.Transaction {
+	to := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   testChainID,
+		Nonce:     7,
+		GasTipCap: big.NewInt(1e9),
+		GasFeeCap: big.NewInt(3e9),
+		Gas:       50000,
+		To:        &to,
+		Value:     big.NewInt(0),
+		Data:      []byte{0x01, 0x02},
+	})
+}
+
+func assertSignedBy(t *testing.T, tx *types.Transaction, want common.Address) {
+	t.Helper()
+	sender, err := types.Sender(types.LatestSignerForChainID(testChainID), tx)
+	if err != nil {
+		t.Fatalf("recover sender: %v", err)
+	}
+	if sender != want {
+		t.Fatalf("signed by %s, want %s", sender.Hex(), want.Hex())
+	}
+}
+
+// writeKeystore creates a keystore file with light scrypt parameters in a temp dir.
+func writeKeystore(t *testing.T, passphrase string) (string, common.Address) {
+	t.Helper()
+	account, err := keystore.StoreKey(t.TempDir(), passphrase, keystore.LightScryptN, keystore.LightScryptP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return account.URL.Path, account.Address
+}
+
+func TestKeystoreSigner(t *testing.T) {
+	path, address := writeKeystore(t, "correct horse")
+
+	signer, err := NewKeystoreSigner(path, "correct horse")
+	if err != nil {
+		t.Fatalf("NewKeystoreSigner: %v", err)
+	}
+	if signer.Address() != address {
+		t.Fatalf("address = %s, want %s", signer.Address().Hex(), address.Hex())
+	}
+	signed, err := signer.SignTx(testTx(), testChainID)
+	if err != nil {
+		t.Fatalf("SignTx: %v", err)
+	}
+	assertSignedBy(t, signed, address)
+
+	if _, err := NewKeystoreSigner(path, "wrong"); !errors.Is(err, ErrChainConfig) {
+		t.Errorf("wrong passphrase error = %v, want ErrChainConfig", err)
+	}
+}
+
+func TestLoadSignerKeystorePassphraseFile(t *testing.T) {
+	path, address := writeKeystore(t, "from a file")
+	passphraseFile := filepath.Join(t.TempDir(), "passphrase")
+	if err := os.WriteFile(passphraseFile, []byte("from a file\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SIGNER", "")
+	t.Setenv("KEYSTORE_PATH", path)
+	t.Setenv("KEYSTORE_PASSPHRASE_FILE", passphraseFile)
+
+	list, err := loadSigners()
+	if err != nil {
+		t.Fatalf("loadSigners: %v", err)
+	}
+	if len(list) != 1 || list[0].Address() != address {
+		t.Fatalf("signers = %v, want only %s", list, address.Hex())
+	}
+}
+
+func TestLoadSignersKeystoreList(t *testing.T) {
+	first, firstAddress := writeKeystore(t, "shared")
+	second, secondAddress := writeKeystore(t, "shared")
+
+	t.Setenv("SIGNER", SignerKeystore)
+	t.Setenv("KEYSTORE_PATH", first+", "+second)
+	t.Setenv("KEYSTORE_PASSPHRASE_FILE", "")
+	t.Setenv("KEYSTORE_PASSPHRASE", "shared")
+
+	list, err := loadSigners()
+	if err != nil {
+		t.Fatalf("loadSigners: %v", err)
+	}
+	if len(list) != 2 || list[0].Address() != firstAddress || list[1].Address() != secondAddress {
+		t.Fatalf("signers = %v, want %s and %s", list, firstAddress.Hex(), secondAddress.Hex())
+	}
+
+	// The same key twice would break nonce tracking
+	t.Setenv("KEYSTORE_PATH", first+","+first)
+	if _, err := loadSigners(); !errors.Is(err, ErrChainConfig) {
+		t.Errorf("duplicate keystore error = %v, want ErrChainConfig", err)
+	}
+}
+
+func TestLoadSignerRequiresDevKeyFlag(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BACKEND_PRIVATE_KEY", hexutil.Encode(crypto.FromECDSA(privateKey))[2:])
+	t.Setenv("KEYSTORE_PATH", "")
+
+	// A plaintext key alone is not enough; the keystore signer is the default
+	t.Setenv("SIGNER", "")
+	if _, err := loadSigners(); !errors.Is(err, ErrChainConfig) {
+		t.Fatalf("loadSigners without SIGNER=dev-key error = %v, want ErrChainConfig", err)
+	}
+
+	t.Setenv("SIGNER", SignerDevKey)
+	list, err := loadSigners()
+	if err != nil {
+		t.Fatalf("loadSigners: %v", err)
+	}
+	if len(list) != 1 || list[0].Address() != crypto.PubkeyToAddress(privateKey.PublicKey) {
+		t.Errorf("dev-key signers = %v, want only %s", list, crypto.PubkeyToAddress(privateKey.PublicKey).Hex())
+	}
+
+	t.Setenv("SIGNER", "hsm")
+	if _, err := loadSigners(); !errors.Is(err, ErrChainConfig) {
+		t.Errorf("unknown SIGNER error = %v, want ErrChainConfig", err)
+	}
+}
+
+// fakeClef implements the subset of Clef's account_ API used by the external signer.
+type fakeClef struct {
+	key *keySigner
+	// tamper, if set, modifies the transaction before it is signed
+	tamper func(args *apitypes.SendTxArgs)
+}
+
+func (c *fakeClef) Version() string { return "6.0.0" }
+
+func (c *fakeClef) List() []common.Address { return []common.Address{c.key.Address()} }
+
+func (c *fakeClef) SignTransaction(args apitypes.SendTxArgs) (map[string]interface{}, error) {
+	if c.tamper != nil {
+		c.tamper(&args)
+	}
+	tx, err := args.ToTransaction()
+	if err != nil {
+		return nil, err
+	}
+	signed, err := c.key.SignTx(tx, (*big.Int)(args.ChainID))
+	if err != nil {
+		return nil, err
+	}
+	raw, err := signed.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"raw": hexutil.Bytes(raw), "tx": signed}, nil
+}
+
+func (c *fakeClef) SignData(contentType string, addr common.MixedcaseAddress, data hexutil.Bytes) (hexutil.Bytes, error) {
+	signature, err := c.key.SignText(data)
+	if err != nil {
+		return nil, err
+	}
+	signature[64] += 27 // Clef returns legacy V values
+	return signature, nil
+}
+
+func startFakeClef(t *testing.T, clef *fakeClef) string {
+	t.Helper()
+	server := rpc.NewServer()
+	if err := server.RegisterName("account", clef); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(func() {
+		httpServer.Close()
+		server.Stop()
+	})
+	return httpServer.URL
+}
+
+func TestClefSigner(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clef := &fakeClef{key: NewKeySigner(privateKey).(*keySigner)}
+	url := startFakeClef(t, clef)
+
+	signer, err := NewClefSigner(url, nil)
+	if err != nil {
+		t.Fatalf("NewClefSigner: %v", err)
+	}
+	if signer.Address() != clef.key.Address() {
+		t.Fatalf("address = %s, want %s", signer.Address().Hex(), clef.key.Address().Hex())
+	}
+
+	tx := testTx()
+	signed, err := signer.SignTx(tx, testChainID)
+	if err != nil {
+		t.Fatalf("SignTx: %v", err)
+	}
+	assertSignedBy(t, signed, clef.key.Address())
+	if !sameUnsignedFields(tx, signed) {
+		t.Errorf("signed transaction differs from the request")
+	}
+
+	text := []byte("ProofPot certificate")
+	signature, err := signer.SignText(text)
+	if err != nil {
+		t.Fatalf("SignText: %v", err)
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash(text), signature)
+	if err != nil || crypto.PubkeyToAddress(*pub) != clef.key.Address() {
+		t.Errorf("text signature does not recover to %s (err %v)", clef.key.Address().Hex(), err)
+	}
+
+	// A signer that changes the transaction is rejected
+	clef.tamper = func(args *apitypes.SendTxArgs) { args.Gas = hexutil.Uint64(uint64(args.Gas) * 2) }
+	if _, err := signer.SignTx(tx, testChainID); err == nil {
+		t.Errorf("tampered transaction was accepted")
+	}
+
+	other := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	if _, err := NewClefSigner(url, &other); !errors.Is(err, ErrChainConfig) {
+		t.Errorf("unknown account error = %v, want ErrChainConfig", err)
+	}
+}
@@ -0,0 +1,164 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+
+	"proofpot-backend/blockchain/contracts"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+)
+
+// simulatedFunding is the genesis balance of every funded simulated account (1000 ETH).
+var simulatedFunding = new(big.Int).Mul(big.NewInt(1000), big.NewInt(1e18))
+
+// SimulatedChain is an in-process chain with a freshly deployed RecipeRegistry.
+// It needs no network access, which makes it suitable for tests and local
+// development (CHAIN_BACKEND=simulated). State is lost when it is closed.
+type SimulatedChain struct {
+	Backend  *simulated.Backend
+	Owner    *ecdsa.PrivateKey // Deployer and owner of the RecipeRegistry
+	Contract common.Address
+}
+
+// NewSimulatedChain starts a simulated chain, funds owner and the extra accounts,
+// and deploys RecipeRegistry owned by owner.
+func NewSimulatedChain(owner *ecdsa.PrivateKey, funded ...common.Address) (*SimulatedChain, error) {
+	ownerAddress := crypto.PubkeyToAddress(owner.PublicKey)
+	alloc := types.GenesisAlloc{ownerAddress: {Balance: simulatedFunding}}
+	for _, addr := range funded {
+		alloc[addr] = types.Account{Balance: simulatedFunding}
+	}
+	backend := simulated.NewBackend(alloc)
+
+	chainID, err := backend.Client().ChainID(context.Background())
+	if err != nil {
+		backend.Close()
+		return nil, fmt.Errorf("failed to get simulated chain ID: %w", err)
+	}
+	deployer, err := bind.NewKeyedTransactorWithChainID(owner, chainID)
+	if err != nil {
+		backend.Close()
+		return nil, fmt.Errorf("failed to create deployer transactor: %w", err)
+	}
+	address, _, _, err := contracts.DeployRecipeRegistry(deployer, backend.Client(), ownerAddress)
+	if err != nil {
+		backend.Close()
+		return nil, fmt.Errorf("failed to deploy RecipeRegistry: %w", err)
+	}
+	backend.Commit()
+
+	return &SimulatedChain{Backend: backend, Owner: owner, Contract: address}, nil
+}
+
+// GrantRegistrars lets accounts register recipes, as the owner would with setRegistrar.
+func (c *SimulatedChain) GrantRegistrars(accounts ...common.Address) error {
+	chainID, err := c.Backend.Client().ChainID(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to get simulated chain ID: %w", err)
+	}
+	owner, err := bind.NewKeyedTransactorWithChainID(c.Owner, chainID)
+	if err != nil {
+		return fmt.Errorf("failed to create owner transactor: %w", err)
+	}
+	contract, err := contracts.NewRecipeRegistry(c.Contract, c.Backend.Client())
+	if err != nil {
+		return err
+	}
+	for _, account := range accounts {
+		if _, err := contract.SetRegistrar(owner, account, true); err != nil {
+			return fmt.Errorf("failed to grant registrar role to %s: %w", account.Hex(), err)
+		}
+		c.Backend.Commit()
+	}
+	return nil
+}
+
+// Client returns a client for the chain that mines a block after every accepted
+// transaction, so code waiting for receipts does not need to call Commit.
+func (c *SimulatedChain) Client() Client {
+	return &autoMiningClient{Client: c.Backend.Client(), backend: c.Backend}
+}
+
+// Use points the package at this chain, signing with signerList (the owner if
+// empty), as CHAIN_BACKEND=simulated does. Tests of the packages built on this
+// one use it to run against an in-process chain.
+func (c *SimulatedChain) Use(signerList ...Signer) error {
+	if len(signerList) == 0 {
+		signerList = []Signer{NewKeySigner(c.Owner)}
+	}
+	return configure(c.Client(), signerList, c.Contract)
+}
+
+// Close shuts the chain down.
+func (c *SimulatedChain) Close() error {
+	return c.Backend.Close()
+}
+
+// autoMiningClient commits a block after each transaction it sends.
+type autoMiningClient struct {
+	simulated.Client
+	backend *simulated.Backend
+	mu      sync.Mutex // The simulated beacon does not support concurrent commits
+}
+
+func (c *autoMiningClient) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.Client.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	c.backend.Commit()
+	return nil
+}
+
+// initSimulated starts an in-process chain for CHAIN_BACKEND=simulated. The
+// backend keys come from BACKEND_PRIVATE_KEY (comma-separated) if set, otherwise
+// a throwaway key is generated. The first key deploys and owns the RecipeRegistry;
+// any further keys are funded and granted the registrar role.
+func initSimulated() error {
+	var keys []*ecdsa.PrivateKey
+	for _, pKey := range splitList(os.Getenv("BACKEND_PRIVATE_KEY")) {
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(pKey, "0x"))
+		if err != nil {
+			return fmt.Errorf("%w: failed to load private key: %v", ErrChainConfig, err)
+		}
+		keys = append(keys, privateKey)
+	}
+	if len(keys) == 0 {
+		privateKey, err := crypto.GenerateKey()
+		if err != nil {
+			return fmt.Errorf("%w: failed to generate private key: %v", ErrChainConfig, err)
+		}
+		keys = append(keys, privateKey)
+	}
+
+	var registrars []common.Address
+	signerList := []Signer{NewKeySigner(keys[0])}
+	for _, key := range keys[1:] {
+		registrars = append(registrars, crypto.PubkeyToAddress(key.PublicKey))
+		signerList = append(signerList, NewKeySigner(key))
+	}
+
+	chain, err := NewSimulatedChain(keys[0], registrars...)
+	if err != nil {
+		return err
+	}
+	if err := chain.GrantRegistrars(registrars...); err != nil {
+		chain.Close()
+		return err
+	}
+	log.Printf("WARNING: Using an in-memory simulated chain; registrations are lost on restart. RecipeRegistry deployed at %s", chain.Contract.Hex())
+
+	return chain.Use(signerList...)
+}
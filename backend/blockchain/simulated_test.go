@@ -0,0 +1,267 @@
+package blockchain
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// newTestChain starts a simulated chain owned by a fresh key and configures the
+// package globals to sign with signer (the owner if nil). Extra keys are funded.
+func newTestChain(t *testing.T, signer *ecdsa.PrivateKey, funded ...*ecdsa.PrivateKey) *SimulatedChain {
+	t.Helper()
+
+	owner, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var addresses []common.Address
+	for _, key := range funded {
+		addresses = append(addresses, crypto.PubkeyToAddress(key.PublicKey))
+	}
+	chain, err := NewSimulatedChain(owner, addresses...)
+	if err != nil {
+		t.Fatalf("NewSimulatedChain: %v", err)
+	}
+	t.Cleanup(func() { chain.Close() })
+
+	if signer == nil {
+		signer = owner
+	}
+	if err := configure(chain.Client(), []Signer{NewKeySigner(signer)}, chain.Contract); err != nil {
+		t.Fatalf("configure: %v", err)
+	}
+	return chain
+}
+
+func testHash(label string) (string, [32]byte) {
+	h := crypto.Keccak256Hash([]byte(label))
+	return h.Hex(), h
+}
+
+func testContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+func TestRegisterRecipeOnChain(t *testing.T) {
+	newTestChain(t, nil)
+	ctx := testContext(t)
+	hashHex, hash := testHash("pancakes")
+	creator := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+
+	var submitted []SubmittedTx
+	reg, err := RegisterRecipeOnChain(ctx, hashHex, creator.Hex(), func(sub SubmittedTx) {
+		submitted = append(submitted, sub)
+	})
+	if err != nil {
+		t.Fatalf("RegisterRecipeOnChain: %v", err)
+	}
+	if len(submitted) != 1 || submitted[0].Hash != reg.TxHash {
+		t.Fatalf("onSubmitted calls = %+v, want one call for %s", submitted, reg.TxHash)
+	}
+	if reg.BlockNumber == 0 {
+		t.Errorf("BlockNumber = 0, want the block the tx was mined in")
+	}
+
+	record, err := GetRecipeRecord(ctx, hash)
+	if err != nil {
+		t.Fatalf("GetRecipeRecord: %v", err)
+	}
+	if !record.Registered() || record.Creator != creator {
+		t.Errorf("on-chain creator = %s, want %s", record.Creator.Hex(), creator.Hex())
+	}
+	if !record.Timestamp.Equal(reg.BlockTime) {
+		t.Errorf("on-chain timestamp = %s, want block time %s", record.Timestamp, reg.BlockTime)
+	}
+}
+
+func TestGetRecipeRecordUnregistered(t *testing.T) {
+	newTestChain(t, nil)
+	_, hash := testHash("never registered")
+
+	record, err := GetRecipeRecord(testContext(t), hash)
+	if err != nil {
+		t.Fatalf("GetRecipeRecord: %v", err)
+	}
+	if record.Registered() {
+		t.Errorf("unregistered hash reported as registered by %s", record.Creator.Hex())
+	}
+}
+
+func TestRegisterDuplicateReverts(t *testing.T) {
+	newTestChain(t, nil)
+	ctx := testContext(t)
+	hashHex, _ := testHash("soup")
+	creator := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+
+	if _, err := RegisterRecipeOnChain(ctx, hashHex, creator.Hex(), nil); err != nil {
+		t.Fatalf("first registration: %v", err)
+	}
+	_, err := RegisterRecipeOnChain(ctx, hashHex, creator.Hex(), nil)
+	if !errors.Is(err, ErrHashAlreadyRegistered) {
+		t.Fatalf("duplicate registration error = %v, want ErrHashAlreadyRegistered", err)
+	}
+	var revert *RevertError
+	if !errors.As(err, &revert) || revert.Reason != "Recipe hash already exists" {
+		t.Errorf("duplicate registration error = %#v, want revert reason", err)
+	}
+	if IsRetryable(err) {
+		t.Errorf("duplicate registration reported as retryable")
+	}
+}
+
+func TestRegisterZeroCreatorReverts(t *testing.T) {
+	newTestChain(t, nil)
+	hashHex, _ := testHash("zero creator")
+
+	_, err := RegisterRecipeOnChain(testContext(t), hashHex, common.Address{}.Hex(), nil)
+	if !errors.Is(err, ErrZeroCreator) {
+		t.Fatalf("error = %v, want ErrZeroCreator", err)
+	}
+}
+
+func TestRegisterUnauthorizedSigner(t *testing.T) {
+	outsider, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTestChain(t, outsider, outsider)
+	hashHex, _ := testHash("stolen recipe")
+
+	// The startup refresh already knows the key is not a registrar
+	_, err = RegisterRecipeOnChain(testContext(t), hashHex, crypto.PubkeyToAddress(outsider.PublicKey).Hex(), nil)
+	if !errors.Is(err, ErrNoSignerAvailable) {
+		t.Fatalf("error = %v, want ErrNoSignerAvailable", err)
+	}
+	if !IsRetryable(err) {
+		t.Errorf("registration without a usable signer reported as permanent")
+	}
+	status := CurrentStatus()
+	if len(status.Signers) != 1 || status.Signers[0].Authorized || status.SignersAvailable() {
+		t.Errorf("signer status = %+v, want one unauthorized signer", status.Signers)
+	}
+}
+
+func TestRegisterInvalidInput(t *testing.T) {
+	newTestChain(t, nil)
+	ctx := testContext(t)
+	hashHex, _ := testHash("invalid input")
+
+	if _, err := RegisterRecipeOnChain(ctx, "0x1234", common.Address{1}.Hex(), nil); !errors.Is(err, ErrInvalidRegistration) {
+		t.Errorf("short hash error = %v, want ErrInvalidRegistration", err)
+	}
+	if _, err := RegisterRecipeOnChain(ctx, hashHex, "not-an-address", nil); !errors.Is(err, ErrInvalidRegistration) {
+		t.Errorf("bad creator error = %v, want ErrInvalidRegistration", err)
+	}
+}
+
+func TestFilterRecipeAdded(t *testing.T) {
+	chain := newTestChain(t, nil)
+	ctx := testContext(t)
+
+	creators := []common.Address{
+		common.HexToAddress("0x00000000000000000000000000000000000000c2"),
+		common.HexToAddress("0x00000000000000000000000000000000000000c3"),
+	}
+	var regs []*Registration
+	var hashes [][32]byte
+	for i, creator := range creators {
+		hashHex, hash := testHash("event " + creator.Hex())
+		reg, err := RegisterRecipeOnChain(ctx, hashHex, creator.Hex(), nil)
+		if err != nil {
+			t.Fatalf("registration %d: %v", i, err)
+		}
+		regs = append(regs, reg)
+		hashes = append(hashes, hash)
+	}
+
+	head, err := LatestBlockNumber(ctx)
+	if err != nil {
+		t.Fatalf("LatestBlockNumber: %v", err)
+	}
+	events, err := FilterRecipeAdded(ctx, 0, head)
+	if err != nil {
+		t.Fatalf("FilterRecipeAdded: %v", err)
+	}
+	if len(events) != len(creators) {
+		t.Fatalf("got %d events, want %d", len(events), len(creators))
+	}
+
+	for i, ev := range events {
+		if ev.RecipeHash != hashes[i] {
+			t.Errorf("event %d hash = %x, want %x", i, ev.RecipeHash, hashes[i])
+		}
+		if ev.Creator != creators[i] {
+			t.Errorf("event %d creator = %s, want %s", i, ev.Creator.Hex(), creators[i].Hex())
+		}
+		if !strings.EqualFold(ev.TxHash.Hex(), regs[i].TxHash) || ev.BlockNumber != regs[i].BlockNumber {
+			t.Errorf("event %d at tx %s block %d, want tx %s block %d", i, ev.TxHash.Hex(), ev.BlockNumber, regs[i].TxHash, regs[i].BlockNumber)
+		}
+		if !ev.Timestamp.Equal(regs[i].BlockTime) {
+			t.Errorf("event %d timestamp = %s, want %s", i, ev.Timestamp, regs[i].BlockTime)
+		}
+		blockHash, err := BlockHashAt(ctx, ev.BlockNumber)
+		if err != nil || blockHash != ev.BlockHash {
+			t.Errorf("event %d block hash = %s, want %s (%v)", i, ev.BlockHash.Hex(), blockHash.Hex(), err)
+		}
+	}
+
+	// Events outside the range are not returned
+	events, err = FilterRecipeAdded(ctx, regs[1].BlockNumber, head)
+	if err != nil {
+		t.Fatalf("FilterRecipeAdded: %v", err)
+	}
+	if len(events) != 1 || events[0].Creator != creators[1] {
+		t.Errorf("range filter returned %+v, want only the second event", events)
+	}
+
+	if Registry().Address() != chain.Contract {
+		t.Errorf("registry address = %s, want %s", Registry().Address().Hex(), chain.Contract.Hex())
+	}
+}
+
+func TestTransferOwnership(t *testing.T) {
+	newOwner, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain := newTestChain(t, nil, newOwner)
+	ctx := testContext(t)
+	newOwnerAddress := crypto.PubkeyToAddress(newOwner.PublicKey)
+
+	if _, err := Registry().TransferOwnership(transactOpts(NewKeySigner(chain.Owner), chainIDValue), newOwnerAddress); err != nil {
+		t.Fatalf("TransferOwnership: %v", err)
+	}
+	chain.Backend.Commit()
+
+	owner, err := Registry().Owner(ctx)
+	if err != nil {
+		t.Fatalf("Owner: %v", err)
+	}
+	if owner != newOwnerAddress {
+		t.Fatalf("owner = %s, want %s", owner.Hex(), newOwnerAddress.Hex())
+	}
+
+	// The previous owner can no longer register
+	hashHex, _ := testHash("after transfer")
+	_, err = RegisterRecipeOnChain(ctx, hashHex, newOwnerAddress.Hex(), nil)
+	if !errors.Is(err, ErrNotRegistrar) {
+		t.Fatalf("registration by previous owner error = %v, want ErrNotRegistrar", err)
+	}
+	var revert *RevertError
+	if !errors.As(err, &revert) || revert.CustomError != "UnauthorizedRegistrar" {
+		t.Errorf("error = %#v, want UnauthorizedRegistrar custom error", err)
+	}
+	// It was the only signer, so the job waits for the role to be granted
+	if !errors.Is(err, ErrNoSignerAvailable) || !IsRetryable(err) {
+		t.Errorf("registration without any registrar left error = %v, want retryable ErrNoSignerAvailable", err)
+	}
+}
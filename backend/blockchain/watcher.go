@@ -0,0 +1,202 @@
+package blockchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ReplacementConfig controls how pending transactions are sped up.
+type ReplacementConfig struct {
+	StuckAfter      time.Duration // Rebroadcast with higher fees after this long unmined (TX_STUCK_AFTER_SECONDS)
+	MaxReplacements int           // Give up bumping after this many replacements (TX_MAX_REPLACEMENTS)
+	PollInterval    time.Duration // How often receipts are polled
+}
+
+const (
+	defaultStuckAfter      = 3 * time.Minute
+	defaultMaxReplacements = 5
+	defaultReceiptPoll     = 4 * time.Second
+
+	// Nodes only accept a replacement that raises both fee fields by at least 10%;
+	// bump by 12.5% to stay clear of rounding.
+	feeBumpNumerator   = 1125
+	feeBumpDenominator = 1000
+)
+
+// replacementCfg is loaded by InitBlockchain.
+var replacementCfg = ReplacementConfig{
+	StuckAfter:      defaultStuckAfter,
+	MaxReplacements: defaultMaxReplacements,
+	PollInterval:    defaultReceiptPoll,
+}
+
+// loadReplacementConfig reads the stuck-transaction settings from the environment.
+func loadReplacementConfig() ReplacementConfig {
+	cfg := ReplacementConfig{
+		StuckAfter:      defaultStuckAfter,
+		MaxReplacements: defaultMaxReplacements,
+		PollInterval:    defaultReceiptPoll,
+	}
+	if raw := os.Getenv("TX_STUCK_AFTER_SECONDS"); raw != "" {
+		if secs, err := strconv.Atoi(raw); err == nil && secs > 0 {
+			cfg.StuckAfter = time.Duration(secs) * time.Second
+		} else {
+			log.Printf("Warning: invalid TX_STUCK_AFTER_SECONDS=%q, using default %s", raw, cfg.StuckAfter)
+		}
+	}
+	if raw := os.Getenv("TX_MAX_REPLACEMENTS"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil && n >= 0 {
+			cfg.MaxReplacements = n
+		} else {
+			log.Printf("Warning: invalid TX_MAX_REPLACEMENTS=%q, using default %d", raw, cfg.MaxReplacements)
+		}
+	}
+	return cfg
+}
+
+// SubmittedTx describes a broadcast registration transaction. Replacements share
+// the nonce of the transaction they replace.
+type SubmittedTx struct {
+	Hash      string
+	From      string // Signer account that sent it
+	Nonce     uint64
+	GasFeeCap *big.Int
+	GasTipCap *big.Int
+	Replaces  string // Hash of the transaction this one replaces, empty for the original
+}
+
+func newSubmittedTx(from common.Address, tx *types.Transaction, replaces *types.Transaction) SubmittedTx {
+	sub := SubmittedTx{
+		Hash:      tx.Hash().Hex(),
+		From:      from.Hex(),
+		Nonce:     tx.Nonce(),
+		GasFeeCap: tx.GasFeeCap(),
+		GasTipCap: tx.GasTipCap(),
+	}
+	if replaces != nil {
+		sub.Replaces = replaces.Hash().Hex()
+	}
+	return sub
+}
+
+// waitMined waits for one of the transactions in sent, which share a nonce, (or one
+// of their replacements) to be mined. If nothing is mined within StuckAfter of the
+// last broadcast, the latest transaction is re-signed with the same nonce and bumped
+// fees. onSubmitted is called for every replacement. It returns the receipt together
+// with the transaction that was actually mined.
+func waitMined(ctx context.Context, acct *signerAccount, sent []*types.Transaction, onSubmitted func(SubmittedTx)) (*types.Receipt, *types.Transaction, error) {
+	lastBroadcast := time.Now()
+
+	ticker := time.NewTicker(replacementCfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Any of the transactions sharing the nonce may be the one that gets mined
+		for _, candidate := range sent {
+			receipt, err := ethClient.TransactionReceipt(ctx, candidate.Hash())
+			if err == nil {
+				return receipt, candidate, nil
+			}
+			if !errors.Is(err, ethereum.NotFound) {
+				log.Printf("Warning: receipt lookup for %s failed: %v", candidate.Hash().Hex(), err)
+			}
+		}
+
+		latest := sent[len(sent)-1]
+		if time.Since(lastBroadcast) >= replacementCfg.StuckAfter && len(sent) <= replacementCfg.MaxReplacements {
+			replacement, err := replaceTransaction(ctx, acct, latest)
+			if err != nil {
+				log.Printf("Warning: could not speed up stuck tx %s (nonce %d): %v", latest.Hash().Hex(), latest.Nonce(), err)
+			} else {
+				log.Printf("Replaced stuck tx %s with %s (nonce %d, max fee %s wei, tip %s wei)",
+					latest.Hash().Hex(), replacement.Hash().Hex(), replacement.Nonce(), replacement.GasFeeCap(), replacement.GasTipCap())
+				sent = append(sent, replacement)
+				if onSubmitted != nil {
+					onSubmitted(newSubmittedTx(acct.address(), replacement, latest))
+				}
+			}
+			// Also back off after a failed attempt instead of retrying every poll
+			lastBroadcast = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil, fmt.Errorf("transaction %s not mined: %w", latest.Hash().Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// replaceTransaction re-signs prev with the same nonce and at least 12.5% higher fees
+// (or the current suggestion, if that is higher) and broadcasts it from acct.
+func replaceTransaction(ctx context.Context, acct *signerAccount, prev *types.Transaction) (*types.Transaction, error) {
+	fees, err := suggestDynamicFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	minTip := bumpFee(prev.GasTipCap())
+	minFeeCap := bumpFee(prev.GasFeeCap())
+	tip := bigMax(fees.GasTipCap, minTip)
+	feeCap := bigMax(fees.GasFeeCap, minFeeCap)
+
+	// Respect the configured caps; if that makes the bump too small, the node
+	// would reject the replacement anyway.
+	if feeCaps.MaxPriorityFeePerGas != nil && tip.Cmp(feeCaps.MaxPriorityFeePerGas) > 0 {
+		tip = new(big.Int).Set(feeCaps.MaxPriorityFeePerGas)
+	}
+	if feeCaps.MaxFeePerGas != nil && feeCap.Cmp(feeCaps.MaxFeePerGas) > 0 {
+		feeCap = new(big.Int).Set(feeCaps.MaxFeePerGas)
+	}
+	if tip.Cmp(minTip) < 0 || feeCap.Cmp(minFeeCap) < 0 {
+		return nil, fmt.Errorf("%w: bumped fees would exceed the configured caps", ErrFeeCapExceeded)
+	}
+	if tip.Cmp(feeCap) > 0 {
+		return nil, fmt.Errorf("bumped tip %s exceeds fee cap %s", tip, feeCap)
+	}
+
+	replacement := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   chainIDValue,
+		Nonce:     prev.Nonce(),
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       prev.Gas(),
+		To:        prev.To(),
+		Value:     prev.Value(),
+		Data:      prev.Data(),
+	})
+	signed, err := acct.signer.SignTx(replacement, chainIDValue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign replacement: %w", err)
+	}
+	if err := sendTransaction(ctx, signed); err != nil && !isAlreadyKnown(err) {
+		// "nonce too low" here means one of the earlier transactions was just mined;
+		// the next receipt poll picks it up.
+		return nil, fmt.Errorf("failed to send replacement: %w", err)
+	}
+	return signed, nil
+}
+
+// bumpFee returns fee * 1.125, rounded up.
+func bumpFee(fee *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(fee, big.NewInt(feeBumpNumerator))
+	bumped.Add(bumped, big.NewInt(feeBumpDenominator-1))
+	return bumped.Div(bumped, big.NewInt(feeBumpDenominator))
+}
+
+func bigMax(a, b *big.Int) *big.Int {
+	if a.Cmp(b) >= 0 {
+		return new(big.Int).Set(a)
+	}
+	return new(big.Int).Set(b)
+}
@@ -0,0 +1,249 @@
+// Package certificate issues and verifies recipe proof certificates: portable,
+// signed JSON documents that tie a recipe's content to its creator and to the
+// RecipeRegistry transaction that anchored it.
+//
+// A certificate can be checked without trusting the server that issued it.
+// Verify recomputes the content hash from the recipe text, checks the Merkle
+// proof of batch-anchored recipes and recovers the issuer from the signature;
+// VerifyOnChain additionally asks any node of the chain whether the contract
+// agrees and whether the issuer is one of its registrars.
+package certificate
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"proofpot-backend/blockchain"
+	"proofpot-backend/contenthash"
+	"proofpot-backend/merkle"
+	"proofpot-backend/models"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Version is the certificate format produced by this package.
+const Version = 1
+
+var (
+	ErrUnsupportedVersion  = errors.New("unsupported certificate version")
+	ErrNotAnchored         = errors.New("recipe is not anchored on chain yet")
+	ErrContentHashMismatch = errors.New("recipe content does not match the content hash")
+	ErrInvalidProof        = errors.New("merkle proof does not lead to the batch root")
+	ErrBadSignature        = errors.New("certificate signature is invalid")
+	ErrUntrustedIssuer     = errors.New("certificate issuer is not trusted")
+	ErrNoTrustedIssuers    = errors.New("no trusted issuers given")
+	ErrChainMismatch       = errors.New("certificate does not match the chain")
+)
+
+// Recipe is the recipe content covered by a certificate. Ingredients and Steps are
+// in the canonical form the content hash was computed over.
+type Recipe struct {
+	Title       string `json:"title"`
+	Ingredients string `json:"ingredients"`
+	Steps       string `json:"steps"`
+	HashScheme  int    `json:"hashScheme"`
+}
+
+// Batch locates a recipe anchored as part of a batch (REGISTRATION_MODE=batch).
+type Batch struct {
+	MerkleRoot string   `json:"merkleRoot"`
+	Proof      []string `json:"proof"` // Sibling hashes from the leaf up to the root
+}
+
+// Certificate is returned by GET /api/recipes/:hash/certificate.
+type Certificate struct {
+	Version         int       `json:"version"`
+	Recipe          Recipe    `json:"recipe"`
+	ContentHash     string    `json:"contentHash"`
+	CreatorAddress  string    `json:"creatorAddress"`
+	ChainID         uint64    `json:"chainId"`
+	ContractAddress string    `json:"contractAddress"`
+	TxHash          string    `json:"txHash,omitempty"` // Empty if the backend only found the hash on chain later
+	BlockNumber     int64     `json:"blockNumber,omitempty"`
+	Timestamp       time.Time `json:"timestamp"` // Block timestamp of the anchoring transaction
+	Batch           *Batch    `json:"batch,omitempty"`
+	IssuedAt        time.Time `json:"issuedAt"`
+	Issuer          string    `json:"issuer"`    // Address of the signing key
+	Signature       string    `json:"signature"` // EIP-191 personal_sign signature of Message()
+}
+
+// New builds an unsigned certificate for a confirmed recipe. batch is the recipe's
+// batch proof, or nil if it was registered with its own transaction.
+func New(recipe *models.Recipe, chainID *big.Int, contract common.Address, batch *models.RecipeBatchProof) (*Certificate, error) {
+	reg := recipe.Registration
+	if reg.Status != models.RegistrationConfirmed || reg.ConfirmedAt == nil {
+		return nil, ErrNotAnchored
+	}
+	scheme, err := contenthash.ParseScheme(recipe.HashScheme)
+	if err != nil {
+		return nil, err
+	}
+
+	cert := &Certificate{
+		Version: Version,
+		Recipe: Recipe{
+			Title:       recipe.Title,
+			Ingredients: recipe.Ingredients,
+			Steps:       recipe.Steps,
+			HashScheme:  recipe.HashScheme,
+		},
+		ContentHash:     recipe.ContentHash,
+		CreatorAddress:  common.HexToAddress(recipe.CreatorAddress).Hex(),
+		ChainID:         chainID.Uint64(),
+		ContractAddress: contract.Hex(),
+		Timestamp:       reg.ConfirmedAt.UTC(),
+	}
+	// SchemeV1 hashed the raw text, later schemes the canonical form
+	if scheme != contenthash.SchemeV1 {
+		cert.Recipe.Ingredients = contenthash.Canonicalize(recipe.Ingredients)
+		cert.Recipe.Steps = contenthash.Canonicalize(recipe.Steps)
+	}
+	if reg.TxHash != nil {
+		cert.TxHash = *reg.TxHash
+	}
+	if reg.BlockNumber != nil {
+		cert.BlockNumber = *reg.BlockNumber
+	}
+	if batch != nil {
+		cert.Batch = &Batch{MerkleRoot: batch.MerkleRoot, Proof: batch.Proof}
+	}
+	return cert, nil
+}
+
+// Message returns the text the issuer signs. It is human readable, so wallets and
+// external signers can show it, and names every claim of the certificate.
+// Ingredients and steps are covered through the content hash.
+func (c *Certificate) Message() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "ProofPot recipe certificate v%d\n", c.Version)
+	fmt.Fprintf(&b, "Title: %s\n", strconv.Quote(c.Recipe.Title))
+	fmt.Fprintf(&b, "Content hash: %s\n", strings.ToLower(c.ContentHash))
+	fmt.Fprintf(&b, "Hash scheme: %d\n", c.Recipe.HashScheme)
+	fmt.Fprintf(&b, "Creator: %s\n", checksummed(c.CreatorAddress))
+	fmt.Fprintf(&b, "Chain ID: %d\n", c.ChainID)
+	fmt.Fprintf(&b, "Contract: %s\n", checksummed(c.ContractAddress))
+	if c.TxHash != "" {
+		fmt.Fprintf(&b, "Transaction: %s\n", strings.ToLower(c.TxHash))
+		fmt.Fprintf(&b, "Block: %d\n", c.BlockNumber)
+	}
+	fmt.Fprintf(&b, "Anchored at: %s\n", c.Timestamp.UTC().Format(time.RFC3339))
+	if c.Batch != nil {
+		fmt.Fprintf(&b, "Merkle root: %s\n", strings.ToLower(c.Batch.MerkleRoot))
+	}
+	fmt.Fprintf(&b, "Issued at: %s\n", c.IssuedAt.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Issuer: %s", checksummed(c.Issuer))
+	return b.String()
+}
+
+// checksummed returns the EIP-55 form of an address, or the input if it is not one.
+func checksummed(address string) string {
+	if !common.IsHexAddress(address) {
+		return address
+	}
+	return common.HexToAddress(address).Hex()
+}
+
+// Sign stamps the certificate with the current time and the signer's address, and signs it.
+func (c *Certificate) Sign(signer blockchain.Signer) error {
+	c.IssuedAt = time.Now().UTC().Truncate(time.Second)
+	c.Issuer = signer.Address().Hex()
+	signature, err := signer.SignText([]byte(c.Message()))
+	if err != nil {
+		return fmt.Errorf("failed to sign certificate: %w", err)
+	}
+	signature[64] += 27 // Legacy V, as personal_sign in wallets returns it
+	c.Signature = hexutil.Encode(signature)
+	return nil
+}
+
+// Verify checks a certificate offline: the recipe content hashes to ContentHash,
+// a batch proof leads to its Merkle root, and the signature was made by Issuer,
+// which must be one of trustedIssuers. Anyone can sign a certificate, so without a
+// pinned issuer it fails with ErrNoTrustedIssuers; callers that have none use
+// VerifyOnChain, which trusts the contract's owner and registrars. Verify does not
+// check that the chain agrees.
+func Verify(c *Certificate, trustedIssuers ...common.Address) error {
+	if len(trustedIssuers) == 0 {
+		return ErrNoTrustedIssuers
+	}
+	issuer, err := verifySignature(c)
+	if err != nil {
+		return err
+	}
+	for _, trusted := range trustedIssuers {
+		if issuer == trusted {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUntrustedIssuer, issuer.Hex())
+}
+
+// verifySignature runs the offline checks of Verify and returns the issuer, without
+// deciding whether to trust it.
+func verifySignature(c *Certificate) (common.Address, error) {
+	if c.Version != Version {
+		return common.Address{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, c.Version)
+	}
+
+	// --- Content ---
+	scheme, err := contenthash.ParseScheme(c.Recipe.HashScheme)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrContentHashMismatch, err)
+	}
+	computed, err := contenthash.Compute(scheme, c.Recipe.Title, c.Recipe.Ingredients, c.Recipe.Steps)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrContentHashMismatch, err)
+	}
+	if !strings.EqualFold(computed, c.ContentHash) {
+		return common.Address{}, fmt.Errorf("%w: computed %s, certificate says %s", ErrContentHashMismatch, computed, c.ContentHash)
+	}
+	if !common.IsHexAddress(c.CreatorAddress) || !common.IsHexAddress(c.ContractAddress) {
+		return common.Address{}, fmt.Errorf("%w: invalid creator or contract address", ErrBadSignature)
+	}
+
+	// --- Batch proof ---
+	if c.Batch != nil {
+		hash, err := contenthash.Decode(c.ContentHash)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+		}
+		proof := make([]common.Hash, len(c.Batch.Proof))
+		for i, sibling := range c.Batch.Proof {
+			proof[i] = common.HexToHash(sibling)
+		}
+		leaf := merkle.Leaf(hash, common.HexToAddress(c.CreatorAddress))
+		if !merkle.Verify(common.HexToHash(c.Batch.MerkleRoot), leaf, proof) {
+			return common.Address{}, ErrInvalidProof
+		}
+	}
+
+	// --- Signature ---
+	return recoverIssuer(c)
+}
+
+// recoverIssuer returns the signer of the certificate, which must be its Issuer.
+func recoverIssuer(c *Certificate) (common.Address, error) {
+	signature, err := hexutil.Decode(c.Signature)
+	if err != nil || len(signature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("%w: malformed signature", ErrBadSignature)
+	}
+	if signature[64] >= 27 {
+		signature[64] -= 27
+	}
+	pub, err := crypto.SigToPub(accounts.TextHash([]byte(c.Message())), signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("%w: %v", ErrBadSignature, err)
+	}
+	signer := crypto.PubkeyToAddress(*pub)
+	if !common.IsHexAddress(c.Issuer) || signer != common.HexToAddress(c.Issuer) {
+		return common.Address{}, fmt.Errorf("%w: signed by %s, not by issuer %s", ErrBadSignature, signer.Hex(), c.Issuer)
+	}
+	return signer, nil
+}
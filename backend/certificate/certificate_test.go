@@ -0,0 +1,234 @@
+package certificate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"proofpot-backend/blockchain"
+	"proofpot-backend/contenthash"
+	"proofpot-backend/merkle"
+	"proofpot-backend/models"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var testCreator = common.HexToAddress("0x00000000000000000000000000000000000000c1")
+
+// confirmedRecipe returns a recipe as stored after its registration was mined.
+func confirmedRecipe(t *testing.T, txHash string, blockNumber int64, at time.Time) *models.Recipe {
+	t.Helper()
+	ingredients, steps := "2 eggs \r\n\r\n1 cup flour", "Whisk.\n  Bake. "
+	hash, err := contenthash.Compute(contenthash.SchemeV2, "Pancakes", ingredients, steps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &models.Recipe{
+		Title:          "Pancakes",
+		Ingredients:    ingredients,
+		Steps:          steps,
+		CreatorAddress: testCreator.Hex(),
+		ContentHash:    hash,
+		HashScheme:     int(contenthash.SchemeV2),
+		Registration: models.Registration{
+			Status:      models.RegistrationConfirmed,
+			TxHash:      &txHash,
+			BlockNumber: &blockNumber,
+			ConfirmedAt: &at,
+		},
+	}
+}
+
+func signedCertificate(t *testing.T, recipe *models.Recipe, batch *models.RecipeBatchProof) (*Certificate, blockchain.Signer) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer := blockchain.NewKeySigner(key)
+	cert, err := New(recipe, big.NewInt(1337), common.HexToAddress("0x00000000000000000000000000000000000000cc"), batch)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	if err := cert.Sign(signer); err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	return cert, signer
+}
+
+// roundTrip decodes the certificate from its JSON form, as a verifier receives it.
+func roundTrip(t *testing.T, cert *Certificate) *Certificate {
+	t.Helper()
+	raw, err := json.Marshal(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out Certificate
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatal(err)
+	}
+	return &out
+}
+
+func TestVerify(t *testing.T) {
+	recipe := confirmedRecipe(t, "0xabc", 42, time.Unix(1700000000, 0))
+	cert, signer := signedCertificate(t, recipe, nil)
+
+	if err := Verify(roundTrip(t, cert), signer.Address()); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if cert.Recipe.Ingredients != "2 eggs\n1 cup flour" {
+		t.Errorf("ingredients = %q, want the canonical form", cert.Recipe.Ingredients)
+	}
+
+	other := common.HexToAddress("0x00000000000000000000000000000000000000dd")
+	if err := Verify(roundTrip(t, cert), other); !errors.Is(err, ErrUntrustedIssuer) {
+		t.Errorf("untrusted issuer error = %v, want ErrUntrustedIssuer", err)
+	}
+
+	// Anyone can sign a valid certificate, so it means nothing without a pinned issuer
+	if err := Verify(roundTrip(t, cert)); !errors.Is(err, ErrNoTrustedIssuers) {
+		t.Errorf("Verify without trusted issuers = %v, want ErrNoTrustedIssuers", err)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	recipe := confirmedRecipe(t, "0xabc", 42, time.Unix(1700000000, 0))
+	cert, signer := signedCertificate(t, recipe, nil)
+
+	tests := []struct {
+		name   string
+		tamper func(c *Certificate)
+		want   error
+	}{
+		{"steps", func(c *Certificate) { c.Recipe.Steps += "\nAdd syrup." }, ErrContentHashMismatch},
+		{"title", func(c *Certificate) { c.Recipe.Title = "Crepes" }, ErrBadSignature},
+		{"creator", func(c *Certificate) { c.CreatorAddress = "0x00000000000000000000000000000000000000c2" }, ErrBadSignature},
+		{"block", func(c *Certificate) { c.BlockNumber++ }, ErrBadSignature},
+		{"issuer", func(c *Certificate) { c.Issuer = "0x00000000000000000000000000000000000000dd" }, ErrBadSignature},
+		{"version", func(c *Certificate) { c.Version = 2 }, ErrUnsupportedVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tampered := roundTrip(t, cert)
+			tt.tamper(tampered)
+			if err := Verify(tampered, signer.Address()); !errors.Is(err, tt.want) {
+				t.Errorf("Verify error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRequiresConfirmation(t *testing.T) {
+	recipe := confirmedRecipe(t, "0xabc", 42, time.Now())
+	recipe.Registration = models.Registration{Status: models.RegistrationSubmitted}
+	if _, err := New(recipe, big.NewInt(1), common.Address{}, nil); !errors.Is(err, ErrNotAnchored) {
+		t.Errorf("New error = %v, want ErrNotAnchored", err)
+	}
+}
+
+func TestVerifyBatchProof(t *testing.T) {
+	recipe := confirmedRecipe(t, "0xabc", 42, time.Unix(1700000000, 0))
+	hash, _ := contenthash.Decode(recipe.ContentHash)
+	leaves := []common.Hash{merkle.Leaf(hash, testCreator), {0x01}, {0x02}}
+	tree, err := merkle.Build(leaves)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, _ := tree.Proof(0)
+	batch := &models.RecipeBatchProof{MerkleRoot: tree.Root().Hex()}
+	for _, sibling := range proof {
+		batch.Proof = append(batch.Proof, sibling.Hex())
+	}
+
+	cert, signer := signedCertificate(t, recipe, batch)
+	if err := Verify(roundTrip(t, cert), signer.Address()); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	tampered := roundTrip(t, cert)
+	tampered.Batch.Proof[0] = common.Hash{0x03}.Hex()
+	if err := Verify(tampered, signer.Address()); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("tampered proof error = %v, want ErrInvalidProof", err)
+	}
+}
+
+func TestVerifyOnChain(t *testing.T) {
+	owner, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := blockchain.NewSimulatedChain(owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer chain.Close()
+	ctx := context.Background()
+	client := chain.Client()
+
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry, err := blockchain.NewRecipeRegistry(chain.Contract, client)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(owner, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recipe := confirmedRecipe(t, "", 0, time.Time{})
+	hash, _ := contenthash.Decode(recipe.ContentHash)
+	tx, err := registry.AddRecipe(opts, hash, testCreator)
+	if err != nil {
+		t.Fatalf("AddRecipe: %v", err)
+	}
+	receipt, err := bind.WaitMined(ctx, client, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header, err := client.HeaderByNumber(ctx, receipt.BlockNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txHash, blockNumber, at := tx.Hash().Hex(), receipt.BlockNumber.Int64(), time.Unix(int64(header.Time), 0)
+	recipe.Registration.TxHash, recipe.Registration.BlockNumber, recipe.Registration.ConfirmedAt = &txHash, &blockNumber, &at
+
+	cert, err := New(recipe, chainID, chain.Contract, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cert.Sign(blockchain.NewKeySigner(owner)); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyOnChain(ctx, roundTrip(t, cert), client); err != nil {
+		t.Fatalf("VerifyOnChain: %v", err)
+	}
+
+	// A validly signed certificate from a key the contract does not know is rejected
+	outsider, _ := signedCertificate(t, recipe, nil)
+	outsider.ChainID, outsider.ContractAddress = chainID.Uint64(), chain.Contract.Hex()
+	key, _ := crypto.GenerateKey()
+	if err := outsider.Sign(blockchain.NewKeySigner(key)); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyOnChain(ctx, outsider, client); !errors.Is(err, ErrUntrustedIssuer) {
+		t.Errorf("outsider certificate error = %v, want ErrUntrustedIssuer", err)
+	}
+
+	// So is one claiming a different registration time
+	backdated := roundTrip(t, cert)
+	backdated.Timestamp = backdated.Timestamp.Add(-time.Hour)
+	if err := backdated.Sign(blockchain.NewKeySigner(owner)); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyOnChain(ctx, backdated, client); !errors.Is(err, ErrChainMismatch) {
+		t.Errorf("backdated certificate error = %v, want ErrChainMismatch", err)
+	}
+}
@@ -0,0 +1,100 @@
+package certificate
+
+import (
+	"context"
+	"fmt"
+
+	"proofpot-backend/blockchain"
+	"proofpot-backend/contenthash"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ChainBackend is a connection to any node of the certificate's chain, such as
+// *ethclient.Client. It does not have to be the issuer's node.
+type ChainBackend interface {
+	bind.ContractBackend
+	ethereum.ChainIDReader
+}
+
+// VerifyOnChain runs the offline checks of Verify and then checks the certificate
+// against the contract: the chain ID matches, the contract stores the hash for
+// the creator (or has anchored the batch root) at the certified time, and the
+// issuer is the contract's owner or one of its registrars.
+func VerifyOnChain(ctx context.Context, c *Certificate, backend ChainBackend) error {
+	issuer, err := verifySignature(c)
+	if err != nil {
+		return err
+	}
+
+	chainID, err := backend.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	if chainID.Uint64() != c.ChainID {
+		return fmt.Errorf("%w: certificate is for chain %d, node is on chain %d", ErrChainMismatch, c.ChainID, chainID.Uint64())
+	}
+	registry, err := blockchain.NewRecipeRegistry(common.HexToAddress(c.ContractAddress), backend)
+	if err != nil {
+		return err
+	}
+
+	hash, err := contenthash.Decode(c.ContentHash)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrContentHashMismatch, err)
+	}
+	creator := common.HexToAddress(c.CreatorAddress)
+
+	// --- Anchoring ---
+	if c.Batch == nil {
+		record, err := registry.GetRecipeRecord(ctx, hash)
+		if err != nil {
+			return err
+		}
+		if record.Creator != creator {
+			return fmt.Errorf("%w: contract records creator %s", ErrChainMismatch, record.Creator.Hex())
+		}
+		if !record.Timestamp.Equal(c.Timestamp) {
+			return fmt.Errorf("%w: contract records registration at %s", ErrChainMismatch, record.Timestamp)
+		}
+	} else {
+		root := common.HexToHash(c.Batch.MerkleRoot)
+		record, err := registry.GetBatchRecord(ctx, root)
+		if err != nil {
+			return err
+		}
+		if !record.Anchored() || !record.Timestamp.Equal(c.Timestamp) {
+			return fmt.Errorf("%w: batch root %s is not anchored at %s", ErrChainMismatch, root.Hex(), c.Timestamp)
+		}
+		proof := make([][32]byte, len(c.Batch.Proof))
+		for i, sibling := range c.Batch.Proof {
+			proof[i] = common.HexToHash(sibling)
+		}
+		included, err := registry.VerifyBatchInclusion(ctx, root, hash, creator, proof)
+		if err != nil {
+			return err
+		}
+		if !included {
+			return ErrInvalidProof
+		}
+	}
+
+	// --- Issuer ---
+	owner, err := registry.Owner(ctx)
+	if err != nil {
+		return err
+	}
+	if issuer == owner {
+		return nil
+	}
+	registrar, err := registry.IsRegistrar(ctx, issuer)
+	if err != nil {
+		return err
+	}
+	if !registrar {
+		return fmt.Errorf("%w: %s is neither owner nor registrar of the contract", ErrUntrustedIssuer, issuer.Hex())
+	}
+	return nil
+}
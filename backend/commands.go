@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"proofpot-backend/database"
+	"proofpot-backend/reconcile"
+)
+
+// runCommand executes a one-shot subcommand (e.g. `server reconcile --repair`)
+// instead of starting the HTTP server, and returns the process exit code.
+func runCommand(name string, args []string) int {
+	switch name {
+	case "reconcile":
+		return runReconcile(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q (available: migrate, reconcile)\n", name)
+		return 2
+	}
+}
+
+// runReconcile compares the database with the RecipeRegistry contract once and
+// prints the report as JSON. Exits with 1 when drift was found.
+func runReconcile(args []string) int {
+	fs := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	repair := fs.Bool("repair", false, "re-enqueue registration jobs for recipes missing on chain")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg := reconcile.LoadConfig()
+	cfg.Repair = *repair
+	report, err := reconcile.New(database.DB, cfg).Once(context.Background())
+	if err != nil {
+		log.Printf("Reconciliation failed: %v", err)
+		return 1
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(report)
+
+	if report.HasDrift() || len(report.Errors) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runMigrate applies, reverts or lists schema migrations:
+//
+//	server migrate up            apply all pending migrations
+//	server migrate down [-steps] revert the latest migration(s)
+//	server migrate status        print every migration and when it was applied
+func runMigrate(args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: migrate up|down|status")
+		return 2
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp(database.DB)
+		if err != nil {
+			log.Printf("Migration failed: %v", err)
+			return 1
+		}
+		if len(applied) == 0 {
+			log.Println("Database schema is up to date")
+		}
+		return 0
+
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := fs.Int("steps", 1, "number of migrations to revert")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		if *steps < 1 {
+			fmt.Fprintln(os.Stderr, "-steps must be at least 1")
+			return 2
+		}
+		reverted, err := database.MigrateDown(database.DB, *steps)
+		if err != nil {
+			log.Printf("Reverting migrations failed: %v", err)
+			return 1
+		}
+		if len(reverted) == 0 {
+			log.Println("No migrations to revert")
+		}
+		return 0
+
+	case "status":
+		statuses, err := database.MigrationStatuses(database.DB)
+		if err != nil {
+			log.Printf("Reading migration status failed: %v", err)
+			return 1
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.Encode(statuses)
+		// Non-zero while migrations are pending, for deploy scripts
+		for _, status := range statuses {
+			if status.AppliedAt == nil {
+				return 1
+			}
+		}
+		return 0
+
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate command %q (expected up, down or status)\n", args[0])
+		return 2
+	}
+}
@@ -0,0 +1,120 @@
+package contenthash
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Scheme identifies how recipe content is turned into bytes before hashing.
+// The scheme is stored next to content_hash so old recipes keep verifying
+// under the rules they were created with.
+type Scheme int
+
+const (
+	// SchemeV1 is the original scheme: sha256 over the raw ingredients, a newline,
+	// and the raw steps. Kept only to verify existing recipes.
+	SchemeV1 Scheme = 1
+	// SchemeV2 hashes canonicalized ingredients and steps (see Canonicalize),
+	// separated by a blank line.
+	SchemeV2 Scheme = 2
+	// SchemeV3 is SchemeV2 with the canonicalized title as the first section.
+	SchemeV3 Scheme = 3
+
+	// CurrentScheme is used for new recipes that do not ask for a specific scheme.
+	CurrentScheme = SchemeV2
+)
+
+// ParseScheme validates a scheme version coming from the API or the database.
+func ParseScheme(version int) (Scheme, error) {
+	switch s := Scheme(version); s {
+	case SchemeV1, SchemeV2, SchemeV3:
+		return s, nil
+	default:
+		return 0, fmt.Errorf("unknown content hash scheme %d", version)
+	}
+}
+
+// Canonicalize normalizes a block of recipe text so that cosmetic differences do not
+// change its hash: Unicode NFC normalization, CRLF/CR converted to LF, surrounding
+// whitespace trimmed from every line and blank lines removed.
+func Canonicalize(text string) string {
+	text = norm.NFC.String(text)
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	lines := strings.Split(text, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		line = strings.TrimFunc(line, isSpace)
+		if line != "" {
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "\n")
+}
+
+// isSpace matches the whitespace set of JavaScript's String.prototype.trim, which the
+// frontend uses, so both sides canonicalize identically.
+func isSpace(r rune) bool {
+	if r == '\u0085' {
+		return false
+	}
+	return r == '\ufeff' || unicode.IsSpace(r)
+}
+
+// Preimage returns the exact bytes that are hashed for the given scheme.
+// Canonical sections never contain blank lines, so joining them with "\n\n"
+// keeps the section boundaries unambiguous.
+func Preimage(scheme Scheme, title, ingredients, steps string) ([]byte, error) {
+	switch scheme {
+	case SchemeV1:
+		return []byte(ingredients + "\n" + steps), nil
+	case SchemeV2:
+		return []byte(Canonicalize(ingredients) + "\n\n" + Canonicalize(steps)), nil
+	case SchemeV3:
+		return []byte(Canonicalize(title) + "\n\n" + Canonicalize(ingredients) + "\n\n" + Canonicalize(steps)), nil
+	default:
+		return nil, fmt.Errorf("unknown content hash scheme %d", scheme)
+	}
+}
+
+// Compute returns the recipe content hash for the given scheme as a 0x-prefixed
+// lowercase hex string, the same format the frontend produces with ethers.sha256.
+func Compute(scheme Scheme, title, ingredients, steps string) (string, error) {
+	preimage, err := Preimage(scheme, title, ingredients, steps)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(preimage)
+	return "0x" + hex.EncodeToString(sum[:]), nil
+}
+
+// Decode parses a hex content hash (with or without 0x prefix) into the bytes32
+// value stored by the RecipeRegistry contract.
+func Decode(hashHex string) ([32]byte, error) {
+	var out [32]byte
+	raw, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(hashHex, "0x"), "0X"))
+	if err != nil {
+		return out, fmt.Errorf("content hash is not valid hex: %w", err)
+	}
+	if len(raw) != len(out) {
+		return out, fmt.Errorf("content hash must be 32 bytes, got %d", len(raw))
+	}
+	copy(out[:], raw)
+	return out, nil
+}
+
+// Normalize returns the canonical 0x-prefixed lowercase form of a content hash,
+// so the same hash cannot be stored twice with different casing.
+func Normalize(hashHex string) (string, error) {
+	b, err := Decode(hashHex)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(b[:]), nil
+}
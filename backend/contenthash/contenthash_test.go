@@ -0,0 +1,84 @@
+package contenthash
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+// vector is a fixed hashing example. The same file is checked by the frontend's
+// src/lib/contentHash.test.ts, so both implementations hash identically.
+type vector struct {
+	Name        string `json:"name"`
+	Scheme      int    `json:"scheme"`
+	Title       string `json:"title"`
+	Ingredients string `json:"ingredients"`
+	Steps       string `json:"steps"`
+	Preimage    string `json:"preimage"`
+	Hash        string `json:"hash"`
+}
+
+func TestVectors(t *testing.T) {
+	raw, err := os.ReadFile("testdata/vectors.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var vectors []vector
+	if err := json.Unmarshal(raw, &vectors); err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[int]bool)
+	for _, v := range vectors {
+		t.Run(v.Name, func(t *testing.T) {
+			scheme, err := ParseScheme(v.Scheme)
+			if err != nil {
+				t.Fatal(err)
+			}
+			seen[v.Scheme] = true
+			preimage, err := Preimage(scheme, v.Title, v.Ingredients, v.Steps)
+			if err != nil || string(preimage) != v.Preimage {
+				t.Errorf("Preimage = %q, %v, want %q", preimage, err, v.Preimage)
+			}
+			if hash, err := Compute(scheme, v.Title, v.Ingredients, v.Steps); err != nil || hash != v.Hash {
+				t.Errorf("Compute = %s, %v, want %s", hash, err, v.Hash)
+			}
+		})
+	}
+	for _, scheme := range []Scheme{SchemeV1, SchemeV2, SchemeV3} {
+		if !seen[int(scheme)] {
+			t.Errorf("no vector for scheme %d", scheme)
+		}
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", ""},
+		{"a\r\nb\rc\nd", "a\nb\nc\nd"},
+		{"\n\n  a  \n\t\n b\t\n", "a\nb"},
+		{"cafe\u0301", "caf\u00e9"},
+		// JavaScript's trim removes these ...
+		{"\u00a0\u2003\u3000\ufeffa\u2028", "a"},
+		// ... but not NEL or zero-width spaces
+		{"\u0085a\u200b", "\u0085a\u200b"},
+	}
+	for _, tt := range tests {
+		if got := Canonicalize(tt.in); got != tt.want {
+			t.Errorf("Canonicalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	const want = "0x0fe49d30b761a68e88ef0f43ea65096817469b94676a5d7c3cd29ba605457636"
+	for _, in := range []string{want, "0X0FE49D30B761A68E88EF0F43EA65096817469B94676A5D7C3CD29BA605457636", want[2:]} {
+		if got, err := Normalize(in); err != nil || got != want {
+			t.Errorf("Normalize(%s) = %s, %v", in, got, err)
+		}
+	}
+	for _, in := range []string{"", "0x1234", "0x" + want[3:] + "zz"} {
+		if _, err := Normalize(in); err == nil {
+			t.Errorf("Normalize(%q) accepted", in)
+		}
+	}
+}
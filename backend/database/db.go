@@ -8,20 +8,29 @@ import (
 
 	// Models import removed as it's no longer used in this file
 
-	_ "github.com/lib/pq" // PostgreSQL driver
+	_ "github.com/lib/pq"  // PostgreSQL driver
+	_ "modernc.org/sqlite" // SQLite driver (pure Go, builds with CGO_ENABLED=0)
 )
 
 var DB *sql.DB
 
-// InitDB initializes the database connection using environment variables.
-func InitDB() {
+// Values of DB_MIGRATIONS, which decides what InitDB does about pending migrations.
+const (
+	MigrationsAuto    = "auto"    // Apply pending migrations on startup (default)
+	MigrationsRequire = "require" // Refuse to start until `migrate up` has been run
+)
+
+// Connect opens the database connection from DATABASE_URL without touching the schema.
+// sqlite:// URLs select the embedded SQLite backend, anything else is passed to
+// the Postgres driver.
+func Connect() {
 	connStr := os.Getenv("DATABASE_URL")
 	if connStr == "" {
 		log.Fatal("DATABASE_URL environment variable is not set")
 	}
 
 	var err error
-	DB, err = sql.Open("postgres", connStr)
+	DB, err = Open(connStr)
 	if err != nil {
 		log.Fatalf("Error opening database connection: %v\n", err)
 	}
@@ -31,7 +40,32 @@ func InitDB() {
 		log.Fatalf("Error connecting to the database: %v\n", err)
 	}
 
-	fmt.Println("Successfully connected to the database!")
+	fmt.Printf("Successfully connected to the %s database!\n", DialectOf(DB))
+}
+
+// InitDB connects to the database and brings its schema up to date. With
+// DB_MIGRATIONS=require it refuses to start while migrations are pending instead,
+// for deployments that run `migrate up` as a separate release step.
+func InitDB() {
+	Connect()
+
+	mode := os.Getenv("DB_MIGRATIONS")
+	switch mode {
+	case "", MigrationsAuto:
+		if _, err := MigrateUp(DB); err != nil {
+			log.Fatalf("Error migrating database schema: %v\n", err)
+		}
+	case MigrationsRequire:
+		pending, err := PendingMigrations(DB)
+		if err != nil {
+			log.Fatalf("Error checking database migrations: %v\n", err)
+		}
+		if len(pending) > 0 {
+			log.Fatalf("Database has %d pending migrations (first: %s), run `migrate up` before starting the server", len(pending), pending[0])
+		}
+	default:
+		log.Fatalf("Invalid DB_MIGRATIONS=%q (expected %q or %q)", mode, MigrationsAuto, MigrationsRequire)
+	}
 }
 
 // CloseDB closes the database connection.
@@ -43,10 +77,10 @@ func CloseDB() {
 }
 
 // CheckHashExists checks if a recipe with the given content hash already exists in the database.
-func CheckHashExists(hash string) (bool, error) {
+func CheckHashExists(db DBTX, hash string) (bool, error) {
 	var exists bool
 	query := `SELECT EXISTS(SELECT 1 FROM recipes WHERE content_hash = $1)`
-	err := DB.QueryRow(query, hash).Scan(&exists)
+	err := db.QueryRow(query, hash).Scan(&exists)
 	if err != nil {
 		// It's important to distinguish between "no rows" (which shouldn't happen with SELECT EXISTS)
 		// and actual database errors.
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"database/sql"
+	"strings"
+
+	"modernc.org/sqlite"
+)
+
+// Dialect is the SQL dialect of a database, picked from the DATABASE_URL scheme.
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres" // postgres:// URLs and key=value connection strings
+	SQLite   Dialect = "sqlite"   // sqlite:// URLs, for single-node deployments
+)
+
+// dialectOfURL returns the dialect of a DATABASE_URL.
+func dialectOfURL(url string) Dialect {
+	if strings.HasPrefix(url, sqliteScheme) {
+		return SQLite
+	}
+	return Postgres
+}
+
+// Open opens (without pinging) the database at url.
+func Open(url string) (*sql.DB, error) {
+	switch dialectOfURL(url) {
+	case SQLite:
+		dsn, err := sqliteDSN(url)
+		if err != nil {
+			return nil, err
+		}
+		return sql.Open("sqlite", dsn)
+	default:
+		return sql.Open("postgres", url)
+	}
+}
+
+// DialectOf returns the dialect of a database opened by Open, as told by its driver.
+func DialectOf(db *sql.DB) Dialect {
+	if _, ok := db.Driver().(*sqlite.Driver); ok {
+		return SQLite
+	}
+	return Postgres
+}
+
+// query returns the variant of a query for the dialect. The repository functions
+// run the same SQL on both dialects except where they pick a variant this way.
+func (d Dialect) query(postgres, sqlite string) string {
+	if d == SQLite {
+		return sqlite
+	}
+	return postgres
+}
@@ -0,0 +1,323 @@
+package database
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"proofpot-backend/models"
+)
+
+// MemoryStore is a RecipeStore that keeps recipes in memory, for handler tests
+// and local demos. It is safe for concurrent use. Nothing registers its recipes
+// on chain, so they stay pending unless a test changes them with SetRegistration.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	recipes map[string]*models.Recipe // By content hash
+	nextID  int
+}
+
+// NewMemoryStore returns an empty store.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{recipes: make(map[string]*models.Recipe), nextID: 1}
+}
+
+func (s *MemoryStore) Create(recipe models.RecipeCreatePayload) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.recipes[recipe.ContentHash]; ok {
+		return 0, ErrDuplicateHash
+	}
+	stored := &models.Recipe{
+		ID:             s.nextID,
+		Title:          recipe.Title,
+		Ingredients:    recipe.Ingredients,
+		Steps:          recipe.Steps,
+		CreatorAddress: recipe.CreatorAddress,
+		ContentHash:    recipe.ContentHash,
+		HashScheme:     recipe.HashScheme,
+		CreatedAt:      time.Now().UTC(),
+		Registration:   models.Registration{Status: models.RegistrationPending},
+	}
+	if recipe.ImageURL != "" {
+		stored.ImageURL = &recipe.ImageURL
+	}
+	if recipe.CreatorSignature != "" {
+		stored.CreatorSignature = &recipe.CreatorSignature
+	}
+	s.recipes[recipe.ContentHash] = stored
+	s.nextID++
+	return stored.ID, nil
+}
+
+func (s *MemoryStore) GetByHash(hash string) (*models.Recipe, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	recipe, ok := s.recipes[hash]
+	if !ok {
+		return nil, nil
+	}
+	return copyRecipe(recipe), nil
+}
+
+func (s *MemoryStore) List(query models.RecipeListQuery) (*models.RecipePage, error) {
+	cursor, err := decodeRecipeCursor(query.Cursor, query.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var matches []models.RecipeListItem
+	for _, r := range s.recipes {
+		if !matchesRecipeQuery(r, query) {
+			continue
+		}
+		r = copyRecipe(r)
+		matches = append(matches, models.RecipeListItem{
+			ID:             r.ID,
+			Title:          r.Title,
+			CreatorAddress: r.CreatorAddress,
+			ContentHash:    r.ContentHash,
+			HashScheme:     r.HashScheme,
+			ImageURL:       r.ImageURL,
+			CreatedAt:      r.CreatedAt,
+			Registration:   r.Registration,
+		})
+	}
+	// Same order as ListRecipes; IDs break ties
+	before := func(a, b models.RecipeListItem) bool {
+		switch query.Sort {
+		case models.RecipeSortOldest:
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+			return a.ID < b.ID
+		case models.RecipeSortTitle:
+			if a.Title != b.Title {
+				return a.Title < b.Title
+			}
+			return a.ID < b.ID
+		default:
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.After(b.CreatedAt)
+			}
+			return a.ID > b.ID
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return before(matches[i], matches[j]) })
+
+	start := 0
+	if cursor != nil {
+		last := models.RecipeListItem{ID: cursor.ID, CreatedAt: cursor.CreatedAt, Title: cursor.Title}
+		start = sort.Search(len(matches), func(i int) bool { return before(last, matches[i]) })
+	}
+	end := min(start+query.Limit+1, len(matches))
+	return newRecipePage(matches[start:end], query), nil
+}
+
+func matchesRecipeQuery(r *models.Recipe, query models.RecipeListQuery) bool {
+	if query.Creator != "" && !strings.EqualFold(r.CreatorAddress, query.Creator) {
+		return false
+	}
+	if query.CreatedFrom != nil && r.CreatedAt.Before(*query.CreatedFrom) {
+		return false
+	}
+	if query.CreatedBefore != nil && !r.CreatedAt.Before(*query.CreatedBefore) {
+		return false
+	}
+	return query.Status == "" || r.Registration.Status == query.Status
+}
+
+func (s *MemoryStore) Exists(hash string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.recipes[hash]
+	return ok, nil
+}
+
+func (s *MemoryStore) Update(hash string, update models.RecipeUpdate) (*models.Recipe, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	recipe, ok := s.recipes[hash]
+	if !ok {
+		return nil, ErrRecipeNotFound
+	}
+	if update.ImageURL != nil {
+		if *update.ImageURL == "" {
+			recipe.ImageURL = nil
+		} else {
+			imageURL := *update.ImageURL
+			recipe.ImageURL = &imageURL
+		}
+	}
+	return copyRecipe(recipe), nil
+}
+
+func (s *MemoryStore) Delete(hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.recipes[hash]; !ok {
+		return ErrRecipeNotFound
+	}
+	delete(s.recipes, hash)
+	return nil
+}
+
+// Search matches recipes whose title, ingredients or steps contain every search
+// word, as plain substrings without stemming. The ranking only mimics the
+// database's weights: each word scores 3 in the title, 2 in the ingredients and
+// 1 in the steps.
+func (s *MemoryStore) Search(query models.RecipeSearchQuery) (*models.RecipeSearchPage, error) {
+	offset, err := decodeSearchCursor(query.Cursor, query.Text)
+	if err != nil {
+		return nil, err
+	}
+	terms := searchTerms(query.Text)
+	if len(terms) == 0 {
+		return newSearchPage(nil, query, offset), nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var results []models.RecipeSearchResult
+	for _, r := range s.recipes {
+		title, ingredients, steps := strings.ToLower(r.Title), strings.ToLower(r.Ingredients), strings.ToLower(r.Steps)
+		rank := 0.0
+		for _, term := range terms {
+			score := 0.0
+			if strings.Contains(title, term) {
+				score += 3
+			}
+			if strings.Contains(ingredients, term) {
+				score += 2
+			}
+			if strings.Contains(steps, term) {
+				score++
+			}
+			if score == 0 {
+				rank = 0
+				break
+			}
+			rank += score
+		}
+		if rank == 0 {
+			continue
+		}
+
+		r = copyRecipe(r)
+		results = append(results, models.RecipeSearchResult{
+			RecipeListItem: models.RecipeListItem{
+				ID:             r.ID,
+				Title:          r.Title,
+				CreatorAddress: r.CreatorAddress,
+				ContentHash:    r.ContentHash,
+				HashScheme:     r.HashScheme,
+				ImageURL:       r.ImageURL,
+				CreatedAt:      r.CreatedAt,
+				Registration:   r.Registration,
+			},
+			Rank:           rank,
+			TitleHighlight: sanitizeHighlight(markTerms(r.Title, terms)),
+			Snippet:        sanitizeHighlight(markTerms(firstMatchingLine(r.Ingredients+"\n"+r.Steps, terms), terms)),
+		})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Rank != results[j].Rank {
+			return results[i].Rank > results[j].Rank
+		}
+		return results[i].ID > results[j].ID
+	})
+
+	start := min(offset, len(results))
+	end := min(start+query.Limit+1, len(results))
+	return newSearchPage(results[start:end], query, offset), nil
+}
+
+// markTerms wraps every case-insensitive occurrence of the terms in <mark> tags.
+func markTerms(text string, terms []string) string {
+	lower := strings.ToLower(text)
+	if len(lower) != len(text) { // Lowercasing changed byte offsets, leave unmarked
+		return text
+	}
+	var b strings.Builder
+	for i := 0; i < len(text); {
+		matched := 0
+		for _, term := range terms {
+			if strings.HasPrefix(lower[i:], term) {
+				matched = max(matched, len(term))
+			}
+		}
+		if matched == 0 {
+			b.WriteByte(text[i])
+			i++
+			continue
+		}
+		b.WriteString(markStart + text[i:i+matched] + markEnd)
+		i += matched
+	}
+	return b.String()
+}
+
+// firstMatchingLine returns the first line of text containing one of the terms.
+func firstMatchingLine(text string, terms []string) string {
+	lines := strings.Split(text, "\n")
+	for _, line := range lines {
+		lower := strings.ToLower(line)
+		for _, term := range terms {
+			if strings.Contains(lower, term) {
+				return line
+			}
+		}
+	}
+	return lines[0]
+}
+
+// GetBatchProof implements BatchProofStore. Recipes in memory are never batched.
+func (s *MemoryStore) GetBatchProof(contentHash string) (*models.RecipeBatchProof, error) {
+	return nil, nil
+}
+
+// SetRegistration replaces the on-chain registration state of a recipe, standing
+// in for the registration workers.
+func (s *MemoryStore) SetRegistration(hash string, registration models.Registration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	recipe, ok := s.recipes[hash]
+	if !ok {
+		return ErrRecipeNotFound
+	}
+	recipe.Registration = copyRecipe(&models.Recipe{Registration: registration}).Registration
+	return nil
+}
+
+// copyRecipe returns a copy that shares no pointers with the stored recipe, so
+// callers cannot modify the store behind its lock.
+func copyRecipe(r *models.Recipe) *models.Recipe {
+	out := *r
+	out.ImageURL = copyPtr(r.ImageURL)
+	out.CreatorSignature = copyPtr(r.CreatorSignature)
+	out.Registration.TxHash = copyPtr(r.Registration.TxHash)
+	out.Registration.BlockNumber = copyPtr(r.Registration.BlockNumber)
+	out.Registration.ConfirmedAt = copyPtr(r.Registration.ConfirmedAt)
+	return &out
+}
+
+func copyPtr[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+	v := *p
+	return &v
+}
+
+// Ping always succeeds; the store lives in memory.
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,73 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"proofpot-backend/models"
+)
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+	payload := models.RecipeCreatePayload{Title: "Pancakes", ContentHash: "0x01", CreatorSignature: "0xsig"}
+	if _, err := s.Create(payload); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := s.Create(payload); !errors.Is(err, ErrDuplicateHash) {
+		t.Errorf("duplicate Create error = %v, want ErrDuplicateHash", err)
+	}
+
+	// Returned recipes are copies
+	got, _ := s.GetByHash("0x01")
+	*got.CreatorSignature = "0xforged"
+	if again, _ := s.GetByHash("0x01"); *again.CreatorSignature != "0xsig" {
+		t.Errorf("modifying a returned recipe changed the store")
+	}
+
+	imageURL := "https://example.com/pancakes.jpg"
+	updated, err := s.Update("0x01", models.RecipeUpdate{ImageURL: &imageURL})
+	if err != nil || updated.ImageURL == nil || *updated.ImageURL != imageURL {
+		t.Errorf("Update = %+v, %v", updated, err)
+	}
+
+	if _, err := s.Update("0x02", models.RecipeUpdate{}); !errors.Is(err, ErrRecipeNotFound) {
+		t.Errorf("Update unknown error = %v, want ErrRecipeNotFound", err)
+	}
+	if err := s.Delete("0x01"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := s.Delete("0x01"); !errors.Is(err, ErrRecipeNotFound) {
+		t.Errorf("second Delete error = %v, want ErrRecipeNotFound", err)
+	}
+	if exists, _ := s.Exists("0x01"); exists {
+		t.Errorf("deleted recipe still exists")
+	}
+}
+
+func TestMemoryStoreConcurrentCreates(t *testing.T) {
+	s := NewMemoryStore()
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.Create(models.RecipeCreatePayload{ContentHash: fmt.Sprintf("0x%02x", i%10)})
+			s.List(models.RecipeListQuery{Sort: models.RecipeSortNewest, Limit: 5})
+		}()
+	}
+	wg.Wait()
+
+	page, _ := s.List(models.RecipeListQuery{Sort: models.RecipeSortNewest, Limit: 100})
+	if len(page.Recipes) != 10 {
+		t.Fatalf("stored %d recipes, want 10", len(page.Recipes))
+	}
+	ids := make(map[int]bool)
+	for _, r := range page.Recipes {
+		if ids[r.ID] {
+			t.Errorf("ID %d assigned twice", r.ID)
+		}
+		ids[r.ID] = true
+	}
+}
@@ -0,0 +1,314 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// migrationFiles holds the schema migrations of each dialect in migrations/<dialect>,
+// named <version>_<name>.up.sql and <version>_<name>.down.sql. Versions are applied
+// in ascending order and never renumbered once released; fix a released migration
+// with a new one. Both dialects have the same versions, so a schema change needs a
+// migration in each directory.
+//
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// migrationLockKey is the Postgres advisory lock held while migrating, so two
+// instances starting at once do not apply the same migration twice.
+const migrationLockKey int64 = 0x70726f6f66706f74 // "proofpot"
+
+var schemaMigrationsTable = map[Dialect]string{
+	Postgres: `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    BIGINT PRIMARY KEY,
+    name       VARCHAR NOT NULL,
+    applied_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+`,
+	SQLite: `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version    BIGINT PRIMARY KEY,
+    name       VARCHAR NOT NULL,
+    applied_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+`,
+}
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+
+// Migration is one numbered schema change.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// String returns the migration's file name prefix, e.g. "0003_recipe_hash_scheme".
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// MigrationStatus describes one migration for `migrate status`.
+type MigrationStatus struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"appliedAt,omitempty"` // Nil while pending
+	Unknown   bool       `json:"unknown,omitempty"`   // Applied, but not part of this build
+}
+
+// Migrations returns the embedded migrations of dialect d in version order.
+func Migrations(d Dialect) ([]Migration, error) {
+	return loadMigrations(migrationFiles, path.Join("migrations", string(d)))
+}
+
+// loadMigrations reads the migrations in dir of fsys. Every version needs both an
+// up and a down file.
+func loadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file name %q", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		raw, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m := byVersion[version]
+		if m == nil {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		} else if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(raw)
+		} else {
+			m.Down = string(raw)
+		}
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %s needs both an up and a down file", m)
+		}
+		out = append(out, *m)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// MigrateUp applies every pending migration and returns the ones it applied.
+func MigrateUp(db *sql.DB) ([]Migration, error) {
+	migrations, err := Migrations(DialectOf(db))
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	err = withMigrationLock(db, func(conn *sql.Conn) error {
+		done, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		for _, m := range migrations {
+			if _, ok := done[m.Version]; ok {
+				continue
+			}
+			if err := runMigration(conn, m, m.Up, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, m.Version, m.Name); err != nil {
+				return err
+			}
+			log.Printf("Applied migration %s", m)
+			applied = append(applied, m)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// MigrateDown reverts the latest steps applied migrations and returns them.
+func MigrateDown(db *sql.DB, steps int) ([]Migration, error) {
+	migrations, err := Migrations(DialectOf(db))
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[int]Migration, len(migrations))
+	for _, m := range migrations {
+		known[m.Version] = m
+	}
+
+	var reverted []Migration
+	err = withMigrationLock(db, func(conn *sql.Conn) error {
+		done, err := appliedMigrations(conn)
+		if err != nil {
+			return err
+		}
+		versions := make([]int, 0, len(done))
+		for version := range done {
+			versions = append(versions, version)
+		}
+		sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+
+		for _, version := range versions[:min(steps, len(versions))] {
+			m, ok := known[version]
+			if !ok {
+				return fmt.Errorf("migration %d is applied but not part of this build, use the build that added it", version)
+			}
+			if err := runMigration(conn, m, m.Down, `DELETE FROM schema_migrations WHERE version = $1`, m.Version); err != nil {
+				return err
+			}
+			log.Printf("Reverted migration %s", m)
+			reverted = append(reverted, m)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// MigrationStatuses lists every migration with the time it was applied.
+func MigrationStatuses(db *sql.DB) ([]MigrationStatus, error) {
+	migrations, done, err := migrationState(db)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if applied, ok := done[m.Version]; ok {
+			status.AppliedAt = &applied.at
+			delete(done, m.Version)
+		}
+		out = append(out, status)
+	}
+	// Whatever is left was applied by a newer build
+	for version, applied := range done {
+		out = append(out, MigrationStatus{Version: version, Name: applied.name, AppliedAt: &applied.at, Unknown: true})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// PendingMigrations returns the migrations that have not been applied yet.
+func PendingMigrations(db *sql.DB) ([]Migration, error) {
+	migrations, done, err := migrationState(db)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, m := range migrations {
+		if _, ok := done[m.Version]; !ok {
+			pending = append(pending, m)
+		}
+	}
+	return pending, nil
+}
+
+// migrationState returns the embedded migrations and the applied ones.
+func migrationState(db *sql.DB) ([]Migration, map[int]appliedMigration, error) {
+	migrations, err := Migrations(DialectOf(db))
+	if err != nil {
+		return nil, nil, err
+	}
+	var done map[int]appliedMigration
+	err = withMigrationLock(db, func(conn *sql.Conn) error {
+		done, err = appliedMigrations(conn)
+		return err
+	})
+	return migrations, done, err
+}
+
+// withMigrationLock runs fn on a dedicated connection holding the migration
+// advisory lock. Session-level advisory locks belong to a connection, so
+// everything that needs the lock must use conn rather than the pool. SQLite has
+// no advisory locks and a single node; there the primary key of schema_migrations
+// makes a concurrent second attempt at a migration fail and roll back.
+func withMigrationLock(db *sql.DB, fn func(conn *sql.Conn) error) error {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get a connection for migrations: %w", err)
+	}
+	defer conn.Close()
+
+	d := DialectOf(db)
+	if d == Postgres {
+		if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockKey); err != nil {
+			return fmt.Errorf("failed to take the migration lock: %w", err)
+		}
+		defer func() {
+			if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+				log.Printf("Error releasing the migration lock: %v", err)
+			}
+		}()
+	}
+
+	if _, err := conn.ExecContext(ctx, schemaMigrationsTable[d]); err != nil {
+		return fmt.Errorf("error creating schema_migrations table: %w", err)
+	}
+	return fn(conn)
+}
+
+type appliedMigration struct {
+	name string
+	at   time.Time
+}
+
+// appliedMigrations reads schema_migrations, keyed by version.
+func appliedMigrations(conn *sql.Conn) (map[int]appliedMigration, error) {
+	rows, err := conn.QueryContext(context.Background(), `SELECT version, name, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+	}
+	defer rows.Close()
+
+	out := make(map[int]appliedMigration)
+	for rows.Next() {
+		var version int
+		var applied appliedMigration
+		if err := rows.Scan(&version, &applied.name, &applied.at); err != nil {
+			return nil, fmt.Errorf("error reading schema_migrations: %w", err)
+		}
+		out[version] = applied
+	}
+	return out, rows.Err()
+}
+
+// runMigration executes one direction of a migration and records it in
+// schema_migrations within a single transaction, so a failed migration leaves
+// neither schema changes nor a record behind.
+func runMigration(conn *sql.Conn, m Migration, script string, record string, args ...any) error {
+	ctx := context.Background()
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return fmt.Errorf("migration %s failed: %w", m, err)
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return fmt.Errorf("failed to record migration %s: %w", m, err)
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"testing"
+	"testing/fstest"
+)
+
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := Migrations(Postgres)
+	if err != nil {
+		t.Fatalf("Migrations: %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	// Versions are consecutive, so a missing or duplicated file shows up here
+	for i, m := range migrations {
+		if m.Version != i+1 {
+			t.Errorf("migration %s has version %d, want %d", m, m.Version, i+1)
+		}
+	}
+	if first := migrations[0]; first.Name != "create_recipes" {
+		t.Errorf("first migration = %s, want the recipes table", first)
+	}
+
+	// Every schema change needs a migration for each dialect
+	sqliteMigrations, err := Migrations(SQLite)
+	if err != nil {
+		t.Fatalf("SQLite migrations: %v", err)
+	}
+	if len(sqliteMigrations) != len(migrations) {
+		t.Fatalf("%d SQLite migrations, want %d like Postgres", len(sqliteMigrations), len(migrations))
+	}
+	for i, m := range sqliteMigrations {
+		if m.String() != migrations[i].String() {
+			t.Errorf("SQLite migration %s, want %s like Postgres", m, migrations[i])
+		}
+	}
+}
+
+func TestLoadMigrationsRejectsBadSets(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+	}{
+		{"missing down", fstest.MapFS{
+			"m/0001_a.up.sql": {Data: []byte("SELECT 1;")},
+		}},
+		{"conflicting names", fstest.MapFS{
+			"m/0001_a.up.sql":   {Data: []byte("SELECT 1;")},
+			"m/0001_b.down.sql": {Data: []byte("SELECT 1;")},
+		}},
+		{"bad file name", fstest.MapFS{
+			"m/first.sql": {Data: []byte("SELECT 1;")},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := loadMigrations(tt.files, "m"); err == nil {
+				t.Errorf("loadMigrations accepted %v", tt.files)
+			}
+		})
+	}
+}
@@ -0,0 +1,13 @@
+-- Recipes were created by hand before migrations existed (see plan.md), so every
+-- statement tolerates an existing table.
+CREATE TABLE IF NOT EXISTS recipes (
+    id              SERIAL PRIMARY KEY,
+    title           VARCHAR NOT NULL,
+    ingredients     TEXT NOT NULL,
+    steps           TEXT NOT NULL,
+    creator_address VARCHAR NOT NULL,
+    content_hash    VARCHAR NOT NULL UNIQUE,
+    created_at      TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS image_url TEXT;
+CREATE INDEX IF NOT EXISTS idx_recipes_content_hash ON recipes(content_hash);
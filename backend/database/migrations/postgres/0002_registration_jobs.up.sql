@@ -0,0 +1,22 @@
+-- On-chain registration state of each recipe, and the outbox of registration jobs.
+-- Jobs are written in the same transaction as the recipe itself so a registration
+-- can never be lost between the database insert and the blockchain call.
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS registration_status VARCHAR NOT NULL DEFAULT 'pending';
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS registration_tx_hash VARCHAR;
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS registration_block_number BIGINT;
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS registration_confirmed_at TIMESTAMP WITH TIME ZONE;
+
+CREATE TABLE IF NOT EXISTS registration_jobs (
+    id              SERIAL PRIMARY KEY,
+    recipe_id       INTEGER NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    content_hash    VARCHAR NOT NULL,
+    creator_address VARCHAR NOT NULL,
+    status          VARCHAR NOT NULL DEFAULT 'pending',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until    TIMESTAMP WITH TIME ZONE,
+    last_error      TEXT,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_registration_jobs_claim ON registration_jobs(status, next_attempt_at);
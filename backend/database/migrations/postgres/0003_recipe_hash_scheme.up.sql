@@ -0,0 +1,3 @@
+-- Which contenthash scheme produced content_hash. Existing rows predate canonical
+-- hashing and therefore default to scheme 1.
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS hash_scheme SMALLINT NOT NULL DEFAULT 1;
@@ -0,0 +1,15 @@
+-- Every transaction broadcast for a recipe, including fee-bumped replacements
+-- that share a nonce, and which key of the signer pool sent it.
+CREATE TABLE IF NOT EXISTS registration_transactions (
+    id           SERIAL PRIMARY KEY,
+    recipe_id    INTEGER NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    tx_hash      VARCHAR NOT NULL UNIQUE,
+    nonce        BIGINT NOT NULL,
+    gas_fee_cap  NUMERIC NOT NULL,
+    gas_tip_cap  NUMERIC NOT NULL,
+    replaces     VARCHAR,
+    status       VARCHAR NOT NULL DEFAULT 'pending',
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_registration_transactions_recipe ON registration_transactions(recipe_id);
+ALTER TABLE registration_transactions ADD COLUMN IF NOT EXISTS from_address VARCHAR;
@@ -0,0 +1,28 @@
+-- Batch anchoring (REGISTRATION_MODE=batch): one row per Merkle root sent with
+-- anchorBatch and every recipe's inclusion proof. Proofs are JSON arrays of hex hashes.
+CREATE TABLE IF NOT EXISTS registration_batches (
+    id              SERIAL PRIMARY KEY,
+    merkle_root     VARCHAR NOT NULL,
+    leaf_count      INTEGER NOT NULL,
+    status          VARCHAR NOT NULL DEFAULT 'pending',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until    TIMESTAMP WITH TIME ZONE,
+    last_error      TEXT,
+    tx_hash         VARCHAR,
+    block_number    BIGINT,
+    anchored_at     TIMESTAMP WITH TIME ZONE,
+    created_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at      TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_registration_batches_claim ON registration_batches(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_registration_batches_root ON registration_batches(merkle_root);
+CREATE TABLE IF NOT EXISTS recipe_batch_proofs (
+    recipe_id  INTEGER PRIMARY KEY REFERENCES recipes(id) ON DELETE CASCADE,
+    batch_id   INTEGER NOT NULL REFERENCES registration_batches(id) ON DELETE CASCADE,
+    leaf_index INTEGER NOT NULL,
+    leaf       VARCHAR NOT NULL,
+    proof      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_recipe_batch_proofs_batch ON recipe_batch_proofs(batch_id);
+ALTER TABLE registration_jobs ADD COLUMN IF NOT EXISTS batch_id INTEGER REFERENCES registration_batches(id);
@@ -0,0 +1,3 @@
+-- The creator's EIP-712 signature of each recipe. Recipes created before
+-- signatures were required have none.
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS creator_signature VARCHAR;
@@ -0,0 +1,5 @@
+-- Keyset pagination and filters of GET /api/recipes.
+CREATE INDEX IF NOT EXISTS idx_recipes_created_at_id ON recipes(created_at, id);
+CREATE INDEX IF NOT EXISTS idx_recipes_title_id ON recipes(title, id);
+CREATE INDEX IF NOT EXISTS idx_recipes_creator ON recipes(LOWER(creator_address));
+CREATE INDEX IF NOT EXISTS idx_recipes_registration_status ON recipes(registration_status);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_recipes_search;
+ALTER TABLE recipes DROP COLUMN IF EXISTS search_vector;
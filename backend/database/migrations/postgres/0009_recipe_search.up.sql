@@ -0,0 +1,8 @@
+-- Full-text search of GET /api/recipes/search. The weights rank title matches
+-- above ingredient matches above step matches.
+ALTER TABLE recipes ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(ingredients, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(steps, '')), 'C')
+) STORED;
+CREATE INDEX IF NOT EXISTS idx_recipes_search ON recipes USING GIN (search_vector);
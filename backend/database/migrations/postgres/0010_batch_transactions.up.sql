@@ -0,0 +1,15 @@
+-- Every anchorBatch transaction broadcast for a batch, so a retry can resume or
+-- fee-bump it instead of sending another one.
+CREATE TABLE IF NOT EXISTS batch_transactions (
+    id           SERIAL PRIMARY KEY,
+    batch_id     INTEGER NOT NULL REFERENCES registration_batches(id) ON DELETE CASCADE,
+    tx_hash      VARCHAR NOT NULL UNIQUE,
+    from_address VARCHAR NOT NULL,
+    nonce        BIGINT NOT NULL,
+    gas_fee_cap  NUMERIC NOT NULL,
+    gas_tip_cap  NUMERIC NOT NULL,
+    replaces     VARCHAR,
+    status       VARCHAR NOT NULL DEFAULT 'pending',
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+CREATE INDEX IF NOT EXISTS idx_batch_transactions_batch ON batch_transactions(batch_id);
@@ -0,0 +1,13 @@
+-- SQLite version of the Postgres schema in ../postgres. Timestamps are stored as
+-- UTC text in the format the driver writes (_time_format=sqlite).
+CREATE TABLE IF NOT EXISTS recipes (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    title           VARCHAR NOT NULL,
+    ingredients     TEXT NOT NULL,
+    steps           TEXT NOT NULL,
+    creator_address VARCHAR NOT NULL,
+    content_hash    VARCHAR NOT NULL UNIQUE,
+    created_at      TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    image_url       TEXT
+);
+CREATE INDEX IF NOT EXISTS idx_recipes_content_hash ON recipes(content_hash);
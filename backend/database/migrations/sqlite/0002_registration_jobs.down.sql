@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS registration_jobs;
+ALTER TABLE recipes DROP COLUMN registration_confirmed_at;
+ALTER TABLE recipes DROP COLUMN registration_block_number;
+ALTER TABLE recipes DROP COLUMN registration_tx_hash;
+ALTER TABLE recipes DROP COLUMN registration_status;
@@ -0,0 +1,20 @@
+-- On-chain registration state of each recipe, and the outbox of registration jobs.
+ALTER TABLE recipes ADD COLUMN registration_status VARCHAR NOT NULL DEFAULT 'pending';
+ALTER TABLE recipes ADD COLUMN registration_tx_hash VARCHAR;
+ALTER TABLE recipes ADD COLUMN registration_block_number BIGINT;
+ALTER TABLE recipes ADD COLUMN registration_confirmed_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS registration_jobs (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    recipe_id       INTEGER NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    content_hash    VARCHAR NOT NULL,
+    creator_address VARCHAR NOT NULL,
+    status          VARCHAR NOT NULL DEFAULT 'pending',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    locked_until    TIMESTAMP,
+    last_error      TEXT,
+    created_at      TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    updated_at      TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+CREATE INDEX IF NOT EXISTS idx_registration_jobs_claim ON registration_jobs(status, next_attempt_at);
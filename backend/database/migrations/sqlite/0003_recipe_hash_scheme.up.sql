@@ -0,0 +1,2 @@
+-- Which contenthash scheme produced content_hash.
+ALTER TABLE recipes ADD COLUMN hash_scheme SMALLINT NOT NULL DEFAULT 1;
@@ -0,0 +1,15 @@
+-- Every transaction broadcast for a recipe. Fees are decimal wei strings, kept as
+-- TEXT because they can exceed SQLite's 64-bit integers.
+CREATE TABLE IF NOT EXISTS registration_transactions (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    recipe_id    INTEGER NOT NULL REFERENCES recipes(id) ON DELETE CASCADE,
+    tx_hash      VARCHAR NOT NULL UNIQUE,
+    nonce        BIGINT NOT NULL,
+    gas_fee_cap  TEXT NOT NULL,
+    gas_tip_cap  TEXT NOT NULL,
+    replaces     VARCHAR,
+    status       VARCHAR NOT NULL DEFAULT 'pending',
+    created_at   TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    from_address VARCHAR
+);
+CREATE INDEX IF NOT EXISTS idx_registration_transactions_recipe ON registration_transactions(recipe_id);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS recipe_events;
+DROP TABLE IF EXISTS indexer_checkpoints;
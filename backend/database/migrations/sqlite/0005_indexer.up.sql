@@ -0,0 +1,22 @@
+-- RecipeAdded events read from the chain and the indexer's progress.
+CREATE TABLE IF NOT EXISTS indexer_checkpoints (
+    name         VARCHAR PRIMARY KEY,
+    block_number BIGINT NOT NULL,
+    block_hash   VARCHAR NOT NULL,
+    updated_at   TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+CREATE TABLE IF NOT EXISTS recipe_events (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    content_hash    VARCHAR NOT NULL,
+    creator_address VARCHAR NOT NULL,
+    chain_timestamp TIMESTAMP NOT NULL,
+    block_number    BIGINT NOT NULL,
+    block_hash      VARCHAR NOT NULL,
+    tx_hash         VARCHAR NOT NULL,
+    log_index       INTEGER NOT NULL,
+    recipe_id       INTEGER REFERENCES recipes(id) ON DELETE SET NULL,
+    created_at      TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    UNIQUE (tx_hash, log_index)
+);
+CREATE INDEX IF NOT EXISTS idx_recipe_events_content_hash ON recipe_events(content_hash);
+CREATE INDEX IF NOT EXISTS idx_recipe_events_block_number ON recipe_events(block_number);
@@ -0,0 +1,3 @@
+ALTER TABLE registration_jobs DROP COLUMN batch_id;
+DROP TABLE IF EXISTS recipe_batch_proofs;
+DROP TABLE IF EXISTS registration_batches;
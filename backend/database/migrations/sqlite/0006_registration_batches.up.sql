@@ -0,0 +1,27 @@
+-- Batch anchoring (REGISTRATION_MODE=batch): Merkle roots and inclusion proofs.
+CREATE TABLE IF NOT EXISTS registration_batches (
+    id              INTEGER PRIMARY KEY AUTOINCREMENT,
+    merkle_root     VARCHAR NOT NULL,
+    leaf_count      INTEGER NOT NULL,
+    status          VARCHAR NOT NULL DEFAULT 'pending',
+    attempts        INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    locked_until    TIMESTAMP,
+    last_error      TEXT,
+    tx_hash         VARCHAR,
+    block_number    BIGINT,
+    anchored_at     TIMESTAMP,
+    created_at      TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now')),
+    updated_at      TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+CREATE INDEX IF NOT EXISTS idx_registration_batches_claim ON registration_batches(status, next_attempt_at);
+CREATE INDEX IF NOT EXISTS idx_registration_batches_root ON registration_batches(merkle_root);
+CREATE TABLE IF NOT EXISTS recipe_batch_proofs (
+    recipe_id  INTEGER PRIMARY KEY REFERENCES recipes(id) ON DELETE CASCADE,
+    batch_id   INTEGER NOT NULL REFERENCES registration_batches(id) ON DELETE CASCADE,
+    leaf_index INTEGER NOT NULL,
+    leaf       VARCHAR NOT NULL,
+    proof      TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_recipe_batch_proofs_batch ON recipe_batch_proofs(batch_id);
+ALTER TABLE registration_jobs ADD COLUMN batch_id INTEGER REFERENCES registration_batches(id);
@@ -0,0 +1,2 @@
+-- The creator's EIP-712 signature of each recipe.
+ALTER TABLE recipes ADD COLUMN creator_signature VARCHAR;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_recipes_registration_status;
+DROP INDEX IF EXISTS idx_recipes_creator;
+DROP INDEX IF EXISTS idx_recipes_title_id;
+DROP INDEX IF EXISTS idx_recipes_created_at_id;
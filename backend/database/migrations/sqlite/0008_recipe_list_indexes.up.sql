@@ -0,0 +1,6 @@
+-- Keyset pagination and filters of GET /api/recipes. The SQLite queries order by
+-- julianday(created_at), so that is what gets indexed.
+CREATE INDEX IF NOT EXISTS idx_recipes_created_at_id ON recipes(julianday(created_at), id);
+CREATE INDEX IF NOT EXISTS idx_recipes_title_id ON recipes(title, id);
+CREATE INDEX IF NOT EXISTS idx_recipes_creator ON recipes(LOWER(creator_address));
+CREATE INDEX IF NOT EXISTS idx_recipes_registration_status ON recipes(registration_status);
@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS recipes_fts_update;
+DROP TRIGGER IF EXISTS recipes_fts_delete;
+DROP TRIGGER IF EXISTS recipes_fts_insert;
+DROP TABLE IF EXISTS recipes_fts;
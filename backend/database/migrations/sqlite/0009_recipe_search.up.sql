@@ -0,0 +1,18 @@
+-- Full-text search of GET /api/recipes/search with an FTS5 index over the recipes
+-- table, kept in sync by triggers. The queries weight the columns with bm25.
+CREATE VIRTUAL TABLE IF NOT EXISTS recipes_fts USING fts5(
+    title, ingredients, steps,
+    content='recipes', content_rowid='id', tokenize='porter unicode61'
+);
+INSERT INTO recipes_fts(recipes_fts) VALUES ('rebuild');
+
+CREATE TRIGGER IF NOT EXISTS recipes_fts_insert AFTER INSERT ON recipes BEGIN
+    INSERT INTO recipes_fts(rowid, title, ingredients, steps) VALUES (new.id, new.title, new.ingredients, new.steps);
+END;
+CREATE TRIGGER IF NOT EXISTS recipes_fts_delete AFTER DELETE ON recipes BEGIN
+    INSERT INTO recipes_fts(recipes_fts, rowid, title, ingredients, steps) VALUES ('delete', old.id, old.title, old.ingredients, old.steps);
+END;
+CREATE TRIGGER IF NOT EXISTS recipes_fts_update AFTER UPDATE OF title, ingredients, steps ON recipes BEGIN
+    INSERT INTO recipes_fts(recipes_fts, rowid, title, ingredients, steps) VALUES ('delete', old.id, old.title, old.ingredients, old.steps);
+    INSERT INTO recipes_fts(rowid, title, ingredients, steps) VALUES (new.id, new.title, new.ingredients, new.steps);
+END;
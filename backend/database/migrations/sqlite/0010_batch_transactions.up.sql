@@ -0,0 +1,14 @@
+-- Every anchorBatch transaction broadcast for a batch. Fees are decimal wei strings.
+CREATE TABLE IF NOT EXISTS batch_transactions (
+    id           INTEGER PRIMARY KEY AUTOINCREMENT,
+    batch_id     INTEGER NOT NULL REFERENCES registration_batches(id) ON DELETE CASCADE,
+    tx_hash      VARCHAR NOT NULL UNIQUE,
+    from_address VARCHAR NOT NULL,
+    nonce        BIGINT NOT NULL,
+    gas_fee_cap  TEXT NOT NULL,
+    gas_tip_cap  TEXT NOT NULL,
+    replaces     VARCHAR,
+    status       VARCHAR NOT NULL DEFAULT 'pending',
+    created_at   TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f+00:00', 'now'))
+);
+CREATE INDEX IF NOT EXISTS idx_batch_transactions_batch ON batch_transactions(batch_id);
@@ -0,0 +1,167 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+
+	"proofpot-backend/models"
+)
+
+// Checkpoint is the last block an indexer has fully processed.
+type Checkpoint struct {
+	BlockNumber int64
+	BlockHash   string
+}
+
+// GetCheckpoint returns the named indexer checkpoint, or nil if the indexer never ran.
+func GetCheckpoint(db DBTX, name string) (*Checkpoint, error) {
+	var cp Checkpoint
+	err := db.QueryRow(`SELECT block_number, block_hash FROM indexer_checkpoints WHERE name = $1`, name).
+		Scan(&cp.BlockNumber, &cp.BlockHash)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		log.Printf("Error reading checkpoint %s: %v", name, err)
+		return nil, err
+	}
+	return &cp, nil
+}
+
+// SaveCheckpoint stores the named indexer checkpoint.
+func SaveCheckpoint(db DBTX, name string, cp Checkpoint) error {
+	_, err := db.Exec(
+		`INSERT INTO indexer_checkpoints (name, block_number, block_hash, updated_at) VALUES ($1, $2, $3, NOW())
+         ON CONFLICT (name) DO UPDATE SET block_number = EXCLUDED.block_number, block_hash = EXCLUDED.block_hash, updated_at = NOW()`,
+		name, cp.BlockNumber, cp.BlockHash,
+	)
+	if err != nil {
+		log.Printf("Error saving checkpoint %s: %v", name, err)
+	}
+	return err
+}
+
+// DeleteCheckpoint removes the named indexer checkpoint, so the indexer starts
+// over from its start block.
+func DeleteCheckpoint(db DBTX, name string) error {
+	_, err := db.Exec(`DELETE FROM indexer_checkpoints WHERE name = $1`, name)
+	if err != nil {
+		log.Printf("Error deleting checkpoint %s: %v", name, err)
+	}
+	return err
+}
+
+// RecordRecipeEvent stores a RecipeAdded event and links it to the matching recipe row.
+// When the recipe is known and the event's creator is the recipe's creator, its
+// registration is marked confirmed (this also covers registrations made by other
+// tools) and any job still waiting to send the same registration is completed. A
+// hash registered for someone else is left alone; the reconciler reports it as a
+// creator mismatch. Re-recording the same event is a no-op.
+func RecordRecipeEvent(db DBTX, event models.RecipeEvent) error {
+	var recipeID sql.NullInt64
+	err := db.QueryRow(
+		`INSERT INTO recipe_events (content_hash, creator_address, chain_timestamp, block_number, block_hash, tx_hash, log_index, recipe_id)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, (SELECT id FROM recipes WHERE content_hash = $1))
+         ON CONFLICT (tx_hash, log_index) DO NOTHING
+         RETURNING recipe_id`,
+		event.ContentHash, event.CreatorAddress, event.ChainTimestamp, event.BlockNumber, event.BlockHash, event.TxHash, event.LogIndex,
+	).Scan(&recipeID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil // Already recorded
+		}
+		log.Printf("Error recording RecipeAdded event %s/%d: %v", event.TxHash, event.LogIndex, err)
+		return err
+	}
+
+	if !recipeID.Valid {
+		log.Printf("Indexer: on-chain hash %s (creator %s) has no matching recipe in the database", event.ContentHash, event.CreatorAddress)
+		return nil
+	}
+
+	res, err := db.Exec(
+		`UPDATE recipes SET registration_status = 'confirmed', registration_tx_hash = $2,
+             registration_block_number = $3, registration_confirmed_at = $4
+         WHERE id = $1 AND LOWER(creator_address) = LOWER($5)`,
+		recipeID.Int64, event.TxHash, event.BlockNumber, event.ChainTimestamp, event.CreatorAddress,
+	)
+	if err != nil {
+		log.Printf("Error confirming recipe %d from event: %v", recipeID.Int64, err)
+		return err
+	}
+	matched, err := res.RowsAffected()
+	if err != nil {
+		log.Printf("Error confirming recipe %d from event: %v", recipeID.Int64, err)
+		return err
+	}
+	if matched == 0 {
+		log.Printf("WARNING: Indexer: hash %s of recipe %d was registered on chain for creator %s, not the recipe's creator (tx %s)",
+			event.ContentHash, recipeID.Int64, event.CreatorAddress, event.TxHash)
+		return nil
+	}
+	_, err = db.Exec(
+		`UPDATE registration_jobs SET status = 'done', locked_until = NULL, updated_at = NOW()
+         WHERE recipe_id = $1 AND status = 'pending'`,
+		recipeID.Int64,
+	)
+	if err != nil {
+		log.Printf("Error completing registration job of recipe %d from event: %v", recipeID.Int64, err)
+	}
+	return err
+}
+
+// DeleteRecipeEventsAfter removes events above the given block after a reorg.
+func DeleteRecipeEventsAfter(db DBTX, blockNumber int64) error {
+	_, err := db.Exec(`DELETE FROM recipe_events WHERE block_number > $1`, blockNumber)
+	if err != nil {
+		log.Printf("Error deleting recipe events after block %d: %v", blockNumber, err)
+	}
+	return err
+}
+
+// reorgedRecipes selects the recipes confirmed by an addRecipe transaction mined
+// above block $1. Batch-anchored recipes are confirmed by their batch instead.
+const reorgedRecipes = `SELECT id FROM recipes
+    WHERE registration_status = 'confirmed' AND registration_block_number > $1
+      AND id NOT IN (SELECT recipe_id FROM recipe_batch_proofs)`
+
+// ReopenRegistrationsAfter undoes the confirmation of registrations mined above
+// the given block after a reorg. Their transactions count as pending again and
+// their jobs are queued, so a worker waits for the transaction to be mined again
+// (or sends it again); the recipes go back to submitted. It returns the number
+// of recipes reopened.
+func ReopenRegistrationsAfter(db DBTX, blockNumber int64) (int64, error) {
+	_, err := db.Exec(
+		`UPDATE registration_transactions SET status = 'pending'
+         WHERE status = 'mined' AND recipe_id IN (`+reorgedRecipes+`)`,
+		blockNumber,
+	)
+	if err != nil {
+		log.Printf("Error reopening registration transactions after block %d: %v", blockNumber, err)
+		return 0, err
+	}
+	_, err = db.Exec(
+		`UPDATE registration_jobs
+         SET status = 'pending', attempts = 0, next_attempt_at = NOW(), locked_until = NULL,
+             last_error = 'registration reorged out of the chain', updated_at = NOW()
+         WHERE status = 'done' AND recipe_id IN (`+reorgedRecipes+`)`,
+		blockNumber,
+	)
+	if err != nil {
+		log.Printf("Error reopening registration jobs after block %d: %v", blockNumber, err)
+		return 0, err
+	}
+	result, err := db.Exec(
+		`UPDATE recipes
+         SET registration_status = CASE WHEN registration_tx_hash IS NULL THEN 'pending' ELSE 'submitted' END,
+             registration_block_number = NULL, registration_confirmed_at = NULL
+         WHERE id IN (`+reorgedRecipes+`)`,
+		blockNumber,
+	)
+	if err != nil {
+		log.Printf("Error reopening recipes after block %d: %v", blockNumber, err)
+		return 0, err
+	}
+	n, _ := result.RowsAffected()
+	return n, nil
+}
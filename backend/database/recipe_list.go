@@ -0,0 +1,151 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"proofpot-backend/models"
+)
+
+// ErrInvalidCursor is returned by ListRecipes for cursors that were not issued
+// for the query's sort order.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// recipeCursor is the sort key of the last recipe of a page. Cursors are handed
+// out base64-encoded so clients treat them as opaque.
+type recipeCursor struct {
+	Sort      string    `json:"s"`
+	ID        int       `json:"i"`
+	CreatedAt time.Time `json:"c"`
+	Title     string    `json:"t,omitempty"`
+}
+
+func encodeRecipeCursor(sort string, last models.RecipeListItem) string {
+	cursor := recipeCursor{Sort: sort, ID: last.ID, CreatedAt: last.CreatedAt}
+	if sort == models.RecipeSortTitle {
+		cursor.Title = last.Title
+	}
+	raw, _ := json.Marshal(cursor) // Cannot fail for these field types
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeRecipeCursor returns nil for an empty cursor (the first page).
+func decodeRecipeCursor(encoded, sort string) (*recipeCursor, error) {
+	if encoded == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor recipeCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// newRecipePage turns the up to limit+1 recipes fetched for a page into the page,
+// with a cursor if the extra recipe shows there are more.
+func newRecipePage(recipes []models.RecipeListItem, query models.RecipeListQuery) *models.RecipePage {
+	page := &models.RecipePage{Recipes: recipes}
+	if page.Recipes == nil {
+		page.Recipes = []models.RecipeListItem{}
+	}
+	if len(recipes) > query.Limit {
+		page.Recipes = recipes[:query.Limit]
+		next := encodeRecipeCursor(query.Sort, page.Recipes[query.Limit-1])
+		page.NextCursor = &next
+	}
+	return page
+}
+
+// ListRecipes returns one page of recipes matching query. It uses keyset
+// pagination on (created_at, id) or (title, id), so later pages cost the same as
+// the first one however many recipes there are.
+func ListRecipes(db DBTX, d Dialect, query models.RecipeListQuery) (*models.RecipePage, error) {
+	cursor, err := decodeRecipeCursor(query.Cursor, query.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	var conditions []string
+	var args []any
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	// SQLite stores timestamps as text that may differ in format, so compare them as numbers
+	createdAt, timeArg := "created_at", arg
+	if d == SQLite {
+		createdAt = "julianday(created_at)"
+		timeArg = func(v any) string { return "julianday(" + arg(v) + ")" }
+	}
+
+	if query.Creator != "" {
+		conditions = append(conditions, "LOWER(creator_address) = LOWER("+arg(query.Creator)+")")
+	}
+	if query.CreatedFrom != nil {
+		conditions = append(conditions, createdAt+" >= "+timeArg(*query.CreatedFrom))
+	}
+	if query.CreatedBefore != nil {
+		conditions = append(conditions, createdAt+" < "+timeArg(*query.CreatedBefore))
+	}
+	if query.Status != "" {
+		conditions = append(conditions, "registration_status = "+arg(query.Status))
+	}
+
+	var orderBy string
+	switch query.Sort {
+	case models.RecipeSortOldest:
+		orderBy = createdAt + " ASC, id ASC"
+		if cursor != nil {
+			conditions = append(conditions, fmt.Sprintf("(%s, id) > (%s, %s)", createdAt, timeArg(cursor.CreatedAt), arg(cursor.ID)))
+		}
+	case models.RecipeSortTitle:
+		orderBy = "title ASC, id ASC"
+		if cursor != nil {
+			conditions = append(conditions, fmt.Sprintf("(title, id) > (%s, %s)", arg(cursor.Title), arg(cursor.ID)))
+		}
+	default:
+		orderBy = createdAt + " DESC, id DESC"
+		if cursor != nil {
+			conditions = append(conditions, fmt.Sprintf("(%s, id) < (%s, %s)", createdAt, timeArg(cursor.CreatedAt), arg(cursor.ID)))
+		}
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	rows, err := db.Query(`SELECT id, title, creator_address, content_hash, hash_scheme, image_url, created_at,
+        registration_status, registration_tx_hash, registration_block_number, registration_confirmed_at
+        FROM recipes `+where+` ORDER BY `+orderBy+` LIMIT `+arg(query.Limit+1), args...)
+	if err != nil {
+		log.Printf("Error querying recipe page: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recipes []models.RecipeListItem
+	for rows.Next() {
+		var recipe models.RecipeListItem
+		if err := rows.Scan(&recipe.ID, &recipe.Title, &recipe.CreatorAddress, &recipe.ContentHash, &recipe.HashScheme, &recipe.ImageURL, &recipe.CreatedAt,
+			&recipe.Registration.Status, &recipe.Registration.TxHash, &recipe.Registration.BlockNumber, &recipe.Registration.ConfirmedAt); err != nil {
+			log.Printf("Error scanning recipe row: %v", err)
+			return nil, err
+		}
+		recipes = append(recipes, recipe)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating recipe rows: %v", err)
+		return nil, err
+	}
+
+	return newRecipePage(recipes, query), nil
+}
@@ -0,0 +1,147 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"proofpot-backend/models"
+)
+
+// pagingFixture stores the same recipes in a store and lets tests set the
+// fields that the store assigns itself.
+type pagingFixture struct {
+	store        RecipeStore
+	setCreatedAt func(hash string, at time.Time)
+	setStatus    func(hash, status string)
+}
+
+var pagingBase = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// pagingRecipes: hash, title, creator, days after pagingBase, status
+var pagingRecipes = []struct {
+	hash, title, creator string
+	day                  int
+	status               string
+}{
+	{"0x01", "Dal", "0xAAaa000000000000000000000000000000000001", 0, models.RegistrationConfirmed},
+	{"0x02", "Biryani", "0xbbbb000000000000000000000000000000000002", 1, models.RegistrationPending},
+	{"0x03", "Curry", "0xaaaa000000000000000000000000000000000001", 2, models.RegistrationConfirmed},
+	{"0x04", "Appam", "0xbbbb000000000000000000000000000000000002", 2, models.RegistrationFailed}, // Same time as 0x03
+	{"0x05", "Elaichi tea", "0xaaaa000000000000000000000000000000000001", 4, models.RegistrationPending},
+}
+
+func (f pagingFixture) fill(t *testing.T) {
+	t.Helper()
+	for _, r := range pagingRecipes {
+		payload := models.RecipeCreatePayload{Title: r.title, Ingredients: "i", Steps: "s", CreatorAddress: r.creator, ContentHash: r.hash}
+		if _, err := f.store.Create(payload); err != nil {
+			t.Fatal(err)
+		}
+		f.setCreatedAt(r.hash, pagingBase.AddDate(0, 0, r.day))
+		f.setStatus(r.hash, r.status)
+	}
+}
+
+// walk follows the cursors through every page and returns the titles in order.
+func (f pagingFixture) walk(t *testing.T, query models.RecipeListQuery) string {
+	t.Helper()
+	var titles []string
+	for pages := 0; ; pages++ {
+		if pages > len(pagingRecipes) {
+			t.Fatalf("%+v: cursors do not end", query)
+		}
+		page, err := f.store.List(query)
+		if err != nil {
+			t.Fatalf("List(%+v): %v", query, err)
+		}
+		if len(page.Recipes) > query.Limit {
+			t.Fatalf("page has %d recipes, limit %d", len(page.Recipes), query.Limit)
+		}
+		for _, r := range page.Recipes {
+			titles = append(titles, r.Title)
+		}
+		if page.NextCursor == nil {
+			return strings.Join(titles, ", ")
+		}
+		query.Cursor = *page.NextCursor
+	}
+}
+
+func testRecipePaging(t *testing.T, f pagingFixture) {
+	f.fill(t)
+	day := func(n int) *time.Time {
+		d := pagingBase.AddDate(0, 0, n)
+		return &d
+	}
+
+	tests := []struct {
+		name  string
+		query models.RecipeListQuery
+		want  string
+	}{
+		{"newest", models.RecipeListQuery{Sort: models.RecipeSortNewest}, "Elaichi tea, Appam, Curry, Biryani, Dal"},
+		{"oldest", models.RecipeListQuery{Sort: models.RecipeSortOldest}, "Dal, Biryani, Curry, Appam, Elaichi tea"},
+		{"title", models.RecipeListQuery{Sort: models.RecipeSortTitle}, "Appam, Biryani, Curry, Dal, Elaichi tea"},
+		{"creator", models.RecipeListQuery{Sort: models.RecipeSortNewest, Creator: "0xAAAA000000000000000000000000000000000001"}, "Elaichi tea, Curry, Dal"},
+		{"date range", models.RecipeListQuery{Sort: models.RecipeSortOldest, CreatedFrom: day(1), CreatedBefore: day(4)}, "Biryani, Curry, Appam"},
+		{"status", models.RecipeListQuery{Sort: models.RecipeSortTitle, Status: models.RegistrationConfirmed}, "Curry, Dal"},
+		{"no match", models.RecipeListQuery{Sort: models.RecipeSortNewest, Status: models.RegistrationSubmitted}, ""},
+	}
+	for _, tt := range tests {
+		for _, limit := range []int{1, 2, 10} {
+			t.Run(fmt.Sprintf("%s/limit %d", tt.name, limit), func(t *testing.T) {
+				tt.query.Limit = limit
+				if got := f.walk(t, tt.query); got != tt.want {
+					t.Errorf("got %s, want %s", got, tt.want)
+				}
+			})
+		}
+	}
+
+	first, err := f.store.List(models.RecipeListQuery{Sort: models.RecipeSortNewest, Limit: 1})
+	if err != nil || first.NextCursor == nil {
+		t.Fatalf("first page = %+v, %v", first, err)
+	}
+	for _, query := range []models.RecipeListQuery{
+		{Sort: models.RecipeSortTitle, Limit: 1, Cursor: *first.NextCursor}, // Issued for another sort
+		{Sort: models.RecipeSortNewest, Limit: 1, Cursor: "garbage"},
+	} {
+		if _, err := f.store.List(query); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("List(%+v) error = %v, want ErrInvalidCursor", query, err)
+		}
+	}
+}
+
+func TestMemoryStorePaging(t *testing.T) {
+	s := NewMemoryStore()
+	testRecipePaging(t, pagingFixture{
+		store: s,
+		setCreatedAt: func(hash string, at time.Time) {
+			s.recipes[hash].CreatedAt = at
+		},
+		setStatus: func(hash, status string) {
+			s.SetRegistration(hash, models.Registration{Status: status})
+		},
+	})
+}
+
+func TestSQLStorePagingOnSQLite(t *testing.T) {
+	db := openTestSQLite(t)
+	testRecipePaging(t, pagingFixture{
+		store: NewSQLStore(db),
+		setCreatedAt: func(hash string, at time.Time) {
+			// A local offset checks that SQLite compares instants, not text
+			if _, err := db.Exec(`UPDATE recipes SET created_at = $2 WHERE content_hash = $1`, hash, at.In(time.FixedZone("UTC-3", -3*3600))); err != nil {
+				t.Fatal(err)
+			}
+		},
+		setStatus: func(hash, status string) {
+			if _, err := db.Exec(`UPDATE recipes SET registration_status = $2 WHERE content_hash = $1`, hash, status); err != nil {
+				t.Fatal(err)
+			}
+		},
+	})
+}
@@ -3,14 +3,26 @@ package database
 import (
 	"database/sql"
 	"log"
+	"time"
 
 	"proofpot-backend/models"
 )
 
-// GetAllRecipes fetches all recipes (summary view) from the database.
+// DBTX is the subset of *sql.DB and *sql.Tx used by the repository functions,
+// so the same query code can run standalone or inside a transaction.
+type DBTX interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// GetAllRecipes fetches all recipes (summary view) from the database. The API pages
+// through recipes with ListRecipes instead.
 func GetAllRecipes(db *sql.DB) ([]models.RecipeListItem, error) {
 	// Select necessary fields including image_url
-	rows, err := db.Query("SELECT id, title, creator_address, content_hash, image_url, created_at FROM recipes ORDER BY created_at DESC")
+	rows, err := db.Query(`SELECT id, title, creator_address, content_hash, hash_scheme, image_url, created_at,
+        registration_status, registration_tx_hash, registration_block_number, registration_confirmed_at
+        FROM recipes ORDER BY created_at DESC`)
 	if err != nil {
 		log.Printf("Error querying all recipes: %v", err)
 		return nil, err
@@ -21,7 +33,8 @@ func GetAllRecipes(db *sql.DB) ([]models.RecipeListItem, error) {
 	for rows.Next() {
 		var recipe models.RecipeListItem
 		// Scan ImageURL, handling potential null values
-		if err := rows.Scan(&recipe.ID, &recipe.Title, &recipe.CreatorAddress, &recipe.ContentHash, &recipe.ImageURL, &recipe.CreatedAt); err != nil {
+		if err := rows.Scan(&recipe.ID, &recipe.Title, &recipe.CreatorAddress, &recipe.ContentHash, &recipe.HashScheme, &recipe.ImageURL, &recipe.CreatedAt,
+			&recipe.Registration.Status, &recipe.Registration.TxHash, &recipe.Registration.BlockNumber, &recipe.Registration.ConfirmedAt); err != nil {
 			log.Printf("Error scanning recipe row: %v", err)
 			return nil, err
 		}
@@ -40,7 +53,9 @@ func GetAllRecipes(db *sql.DB) ([]models.RecipeListItem, error) {
 func GetRecipeByHash(db *sql.DB, hash string) (*models.Recipe, error) {
 	var recipe models.Recipe
 	// Select all fields including image_url
-	row := db.QueryRow("SELECT id, title, ingredients, steps, creator_address, content_hash, image_url, created_at FROM recipes WHERE content_hash = $1", hash)
+	row := db.QueryRow(`SELECT id, title, ingredients, steps, creator_address, content_hash, hash_scheme, image_url, created_at,
+        registration_status, registration_tx_hash, registration_block_number, registration_confirmed_at, creator_signature
+        FROM recipes WHERE content_hash = $1`, hash)
 
 	// Scan ImageURL, handling potential null values
 	err := row.Scan(
@@ -50,8 +65,14 @@ func GetRecipeByHash(db *sql.DB, hash string) (*models.Recipe, error) {
 		&recipe.Steps,
 		&recipe.CreatorAddress,
 		&recipe.ContentHash,
+		&recipe.HashScheme,
 		&recipe.ImageURL, // Scan the ImageURL field
 		&recipe.CreatedAt,
+		&recipe.Registration.Status,
+		&recipe.Registration.TxHash,
+		&recipe.Registration.BlockNumber,
+		&recipe.Registration.ConfirmedAt,
+		&recipe.CreatorSignature,
 	)
 
 	if err != nil {
@@ -65,13 +86,14 @@ func GetRecipeByHash(db *sql.DB, hash string) (*models.Recipe, error) {
 }
 
 // InsertRecipe adds a new recipe to the database.
-func InsertRecipe(db *sql.DB, recipe models.RecipeCreatePayload) (int, error) {
+func InsertRecipe(db DBTX, recipe models.RecipeCreatePayload) (int, error) {
 	var recipeID int
 	// Include image_url in the INSERT statement and handle its value
 	err := db.QueryRow(
-		`INSERT INTO recipes (title, ingredients, steps, creator_address, content_hash, image_url)
-         VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
-		recipe.Title, recipe.Ingredients, recipe.Steps, recipe.CreatorAddress, recipe.ContentHash, recipe.ImageURL, // Pass ImageURL
+		`INSERT INTO recipes (title, ingredients, steps, creator_address, content_hash, hash_scheme, image_url, creator_signature)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		recipe.Title, recipe.Ingredients, recipe.Steps, recipe.CreatorAddress, recipe.ContentHash, recipe.HashScheme, recipe.ImageURL, // Pass ImageURL
+		recipe.CreatorSignature,
 	).Scan(&recipeID)
 
 	if err != nil {
@@ -82,3 +104,66 @@ func InsertRecipe(db *sql.DB, recipe models.RecipeCreatePayload) (int, error) {
 	log.Printf("Successfully inserted recipe with ID: %d, Hash: %s", recipeID, recipe.ContentHash)
 	return recipeID, nil
 }
+
+// CreateRecipeWithRegistrationJob inserts the recipe and its on-chain registration job
+// in a single transaction, so every stored recipe is guaranteed to be picked up by the
+// registration workers.
+func CreateRecipeWithRegistrationJob(db *sql.DB, recipe models.RecipeCreatePayload) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting recipe transaction: %v", err)
+		return 0, err
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	recipeID, err := InsertRecipe(tx, recipe)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := EnqueueRegistrationJob(tx, recipeID, recipe.ContentHash, recipe.CreatorAddress); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing recipe transaction: %v", err)
+		return 0, err
+	}
+	return recipeID, nil
+}
+
+// MarkRecipeSubmitted records that a registration transaction was broadcast for the recipe.
+func MarkRecipeSubmitted(db DBTX, recipeID int, txHash string) error {
+	_, err := db.Exec(
+		`UPDATE recipes SET registration_status = 'submitted', registration_tx_hash = $2 WHERE id = $1`,
+		recipeID, txHash,
+	)
+	if err != nil {
+		log.Printf("Error marking recipe %d as submitted: %v", recipeID, err)
+	}
+	return err
+}
+
+// MarkRecipeConfirmed records the mined registration transaction for the recipe.
+func MarkRecipeConfirmed(db DBTX, recipeID int, txHash string, blockNumber int64, confirmedAt time.Time) error {
+	_, err := db.Exec(
+		`UPDATE recipes SET registration_status = 'confirmed', registration_tx_hash = $2,
+             registration_block_number = $3, registration_confirmed_at = $4
+         WHERE id = $1`,
+		recipeID, txHash, blockNumber, confirmedAt,
+	)
+	if err != nil {
+		log.Printf("Error marking recipe %d as confirmed: %v", recipeID, err)
+	}
+	return err
+}
+
+// SetRecipeRegistrationStatus updates only the registration status (e.g. back to pending
+// before a retry, or failed once the job gives up).
+func SetRecipeRegistrationStatus(db DBTX, recipeID int, status string) error {
+	_, err := db.Exec(`UPDATE recipes SET registration_status = $2 WHERE id = $1`, recipeID, status)
+	if err != nil {
+		log.Printf("Error setting registration status of recipe %d to %s: %v", recipeID, status, err)
+	}
+	return err
+}
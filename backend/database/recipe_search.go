@@ -0,0 +1,175 @@
+package database
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"html"
+	"log"
+	"strings"
+	"unicode"
+
+	"proofpot-backend/models"
+)
+
+// Markers put around matching words by ts_headline and the FTS5 functions.
+// sanitizeHighlight escapes everything else.
+const (
+	markStart = "<mark>"
+	markEnd   = "</mark>"
+)
+
+// searchCursor is the position of the next page of a search. Ranks are not
+// stable keys, so pages are offsets into the ranked results.
+type searchCursor struct {
+	Text   string `json:"q"`
+	Offset int    `json:"o"`
+}
+
+func encodeSearchCursor(text string, offset int) string {
+	raw, _ := json.Marshal(searchCursor{Text: text, Offset: offset}) // Cannot fail for these field types
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// decodeSearchCursor returns the offset of a cursor issued for the same search
+// text, 0 for an empty cursor.
+func decodeSearchCursor(encoded, text string) (int, error) {
+	if encoded == "" {
+		return 0, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	var cursor searchCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil || cursor.Text != text || cursor.Offset < 0 {
+		return 0, ErrInvalidCursor
+	}
+	return cursor.Offset, nil
+}
+
+// newSearchPage turns the up to limit+1 results fetched at offset into a page,
+// with a cursor if the extra result shows there are more.
+func newSearchPage(results []models.RecipeSearchResult, query models.RecipeSearchQuery, offset int) *models.RecipeSearchPage {
+	page := &models.RecipeSearchPage{Results: results}
+	if page.Results == nil {
+		page.Results = []models.RecipeSearchResult{}
+	}
+	if len(results) > query.Limit {
+		page.Results = results[:query.Limit]
+		next := encodeSearchCursor(query.Text, offset+query.Limit)
+		page.NextCursor = &next
+	}
+	return page
+}
+
+// sanitizeHighlight HTML-escapes highlighted recipe text, keeping only the
+// <mark> tags around matches, so clients can render it as HTML.
+func sanitizeHighlight(highlighted string) string {
+	parts := strings.Split(highlighted, markStart)
+	var b strings.Builder
+	b.WriteString(html.EscapeString(strings.ReplaceAll(parts[0], markEnd, "")))
+	for _, part := range parts[1:] {
+		marked, rest, found := strings.Cut(part, markEnd)
+		if !found { // Unbalanced, e.g. "<mark>" typed into the recipe
+			b.WriteString(html.EscapeString(part))
+			continue
+		}
+		b.WriteString(markStart + html.EscapeString(marked) + markEnd)
+		b.WriteString(html.EscapeString(strings.ReplaceAll(rest, markEnd, "")))
+	}
+	return b.String()
+}
+
+// searchTerms splits search text into words, dropping punctuation and operators.
+func searchTerms(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// ftsMatchQuery turns search text into an FTS5 query matching recipes that
+// contain every word. Quoting each word keeps user input from being read as
+// FTS5 syntax.
+func ftsMatchQuery(text string) string {
+	terms := searchTerms(text)
+	for i, term := range terms {
+		terms[i] = `"` + term + `"`
+	}
+	return strings.Join(terms, " ")
+}
+
+// SearchRecipes returns one page of the recipes containing every word of the
+// search text, best matches first. Postgres ranks the search_vector column with
+// ts_rank, whose default weights make a title match (A) count 2.5 times an
+// ingredient match (B) and 5 times a step match (C); SQLite weights its bm25
+// ranking similarly.
+func SearchRecipes(db DBTX, d Dialect, query models.RecipeSearchQuery) (*models.RecipeSearchPage, error) {
+	offset, err := decodeSearchCursor(query.Cursor, query.Text)
+	if err != nil {
+		return nil, err
+	}
+	text := query.Text
+	if d == SQLite {
+		if text = ftsMatchQuery(text); text == "" {
+			return newSearchPage(nil, query, offset), nil // Nothing to search for
+		}
+	}
+
+	// Headlines are only computed for the rows of the page, they are the expensive part
+	rows, err := db.Query(d.query(
+		`SELECT id, title, creator_address, content_hash, hash_scheme, image_url, created_at,
+            registration_status, registration_tx_hash, registration_block_number, registration_confirmed_at,
+            rank,
+            ts_headline('english', title, tsq, 'HighlightAll=true, StartSel=<mark>, StopSel=</mark>'),
+            ts_headline('english', ingredients || E'\n' || steps, tsq,
+                'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=8, FragmentDelimiter=" ... "')
+        FROM (
+            SELECT r.id, r.title, r.ingredients, r.steps, r.creator_address, r.content_hash, r.hash_scheme, r.image_url, r.created_at,
+                r.registration_status, r.registration_tx_hash, r.registration_block_number, r.registration_confirmed_at,
+                ts_rank(r.search_vector, q.tsq) AS rank, q.tsq
+            FROM recipes r, websearch_to_tsquery('english', $1) AS q(tsq)
+            WHERE r.search_vector @@ q.tsq
+            ORDER BY rank DESC, r.id DESC
+            LIMIT $2 OFFSET $3
+        ) hits
+        ORDER BY rank DESC, id DESC`,
+		`SELECT r.id, r.title, r.creator_address, r.content_hash, r.hash_scheme, r.image_url, r.created_at,
+            r.registration_status, r.registration_tx_hash, r.registration_block_number, r.registration_confirmed_at,
+            -bm25(recipes_fts, 10.0, 4.0, 1.0) AS score,
+            highlight(recipes_fts, 0, '<mark>', '</mark>'),
+            CASE WHEN instr(highlight(recipes_fts, 1, '<mark>', ''), '<mark>') > 0
+                THEN snippet(recipes_fts, 1, '<mark>', '</mark>', ' ... ', 20)
+                ELSE snippet(recipes_fts, 2, '<mark>', '</mark>', ' ... ', 20)
+            END
+        FROM recipes_fts JOIN recipes r ON r.id = recipes_fts.rowid
+        WHERE recipes_fts MATCH $1
+        ORDER BY score DESC, r.id DESC
+        LIMIT $2 OFFSET $3`,
+	), text, query.Limit+1, offset)
+	if err != nil {
+		log.Printf("Error searching recipes for %q: %v", query.Text, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []models.RecipeSearchResult
+	for rows.Next() {
+		var result models.RecipeSearchResult
+		recipe := &result.RecipeListItem
+		if err := rows.Scan(&recipe.ID, &recipe.Title, &recipe.CreatorAddress, &recipe.ContentHash, &recipe.HashScheme, &recipe.ImageURL, &recipe.CreatedAt,
+			&recipe.Registration.Status, &recipe.Registration.TxHash, &recipe.Registration.BlockNumber, &recipe.Registration.ConfirmedAt,
+			&result.Rank, &result.TitleHighlight, &result.Snippet); err != nil {
+			log.Printf("Error scanning recipe search row: %v", err)
+			return nil, err
+		}
+		result.TitleHighlight = sanitizeHighlight(result.TitleHighlight)
+		result.Snippet = sanitizeHighlight(result.Snippet)
+		results = append(results, result)
+	}
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating recipe search rows: %v", err)
+		return nil, err
+	}
+
+	return newSearchPage(results, query, offset), nil
+}
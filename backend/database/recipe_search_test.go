@@ -0,0 +1,107 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"proofpot-backend/models"
+)
+
+func TestSanitizeHighlight(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Saffron rice", "Saffron rice"},
+		{"<mark>Saffron</mark> rice", "<mark>Saffron</mark> rice"},
+		{"<b>Bold</b> <mark>saffron</mark> & rice", "&lt;b&gt;Bold&lt;/b&gt; <mark>saffron</mark> &amp; rice"},
+		{"<mark><script></mark>", "<mark>&lt;script&gt;</mark>"},
+		{"stray </mark> and <mark>open", "stray  and open"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeHighlight(tt.in); got != tt.want {
+			t.Errorf("sanitizeHighlight(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSearchRecipesOnSQLite(t *testing.T) {
+	store := NewSQLStore(openTestSQLite(t))
+	recipes := []struct{ title, ingredients, steps string }{
+		{"Paella", "rice, prawns, a pinch of saffron", "Fry the prawns. Simmer the rice."},
+		{"Saffron <b>rice</b>", "basmati rice, butter", "Cook the rice."},
+		{"Kheer", "milk, rice, sugar", "Simmer slowly, finish with saffron threads."},
+		// Recipes without saffron, so the word is rare enough to rank on
+		{"Dal", "lentils, turmeric", "Boil."},
+		{"Toast", "bread, butter", "Toast."},
+		{"Omelette", "eggs, chives", "Whisk and fry."},
+		{"Salad", "lettuce, lemon", "Toss."},
+	}
+	for i, r := range recipes {
+		payload := models.RecipeCreatePayload{Title: r.title, Ingredients: r.ingredients, Steps: r.steps, CreatorAddress: "0xc0", ContentHash: fmt.Sprintf("0x%02x", i)}
+		if _, err := store.Create(payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	page, err := store.Search(models.RecipeSearchQuery{Text: "saffron", Limit: 10})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	var titles []string
+	for _, r := range page.Results {
+		titles = append(titles, r.Title)
+	}
+	if got := strings.Join(titles, ", "); got != "Saffron <b>rice</b>, Paella, Kheer" {
+		t.Errorf("results = %s, want title, then ingredient, then step matches", got)
+	}
+	if page.NextCursor != nil {
+		t.Errorf("single page has a cursor")
+	}
+	if first := page.Results[0]; first.TitleHighlight != "<mark>Saffron</mark> &lt;b&gt;rice&lt;/b&gt;" {
+		t.Errorf("title highlight = %q", first.TitleHighlight)
+	}
+	if snippet := page.Results[1].Snippet; !strings.Contains(snippet, "<mark>saffron</mark>") {
+		t.Errorf("ingredient snippet = %q, want the match marked", snippet)
+	}
+	if snippet := page.Results[2].Snippet; !strings.Contains(snippet, "<mark>saffron</mark> threads") {
+		t.Errorf("step snippet = %q, want the match marked", snippet)
+	}
+
+	// Every word must match; stemming finds "simmer" in "Simmer"
+	both, err := store.Search(models.RecipeSearchQuery{Text: "simmering rice", Limit: 10})
+	if err != nil || len(both.Results) != 2 {
+		t.Errorf("Search(simmering rice) = %+v, %v, want Paella and Kheer", both, err)
+	}
+	// FTS5 syntax in the input is searched for as words
+	if _, err := store.Search(models.RecipeSearchQuery{Text: `saffron" OR NEAR(`, Limit: 10}); err != nil {
+		t.Errorf("Search with FTS5 syntax: %v", err)
+	}
+	if empty, err := store.Search(models.RecipeSearchQuery{Text: "!!!", Limit: 10}); err != nil || len(empty.Results) != 0 {
+		t.Errorf("Search without words = %+v, %v", empty, err)
+	}
+
+	// Paging returns the same results in the same order
+	var paged []string
+	query := models.RecipeSearchQuery{Text: "saffron", Limit: 2}
+	for {
+		page, err := store.Search(query)
+		if err != nil {
+			t.Fatalf("Search page: %v", err)
+		}
+		for _, r := range page.Results {
+			paged = append(paged, r.Title)
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		query.Cursor = *page.NextCursor
+	}
+	if strings.Join(paged, ", ") != strings.Join(titles, ", ") {
+		t.Errorf("paged results = %v, want %v", paged, titles)
+	}
+
+	first, _ := store.Search(models.RecipeSearchQuery{Text: "saffron", Limit: 1})
+	if _, err := store.Search(models.RecipeSearchQuery{Text: "rice", Limit: 1, Cursor: *first.NextCursor}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("cursor of another search: error = %v, want ErrInvalidCursor", err)
+	}
+}
@@ -0,0 +1,416 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"time"
+
+	"proofpot-backend/models"
+)
+
+// BatchBuilder computes the Merkle root and per-recipe proofs for the jobs of a new batch.
+type BatchBuilder func(jobs []models.RegistrationJob) (root string, leaves []models.BatchLeaf, err error)
+
+// CreateRegistrationBatch moves up to maxSize due registration jobs into a new batch.
+// Nothing happens (nil, nil) while fewer than maxSize jobs are waiting and the oldest
+// of them has waited less than window. Jobs are selected, the batch and proofs are
+// stored and the jobs are marked batched in one transaction; SKIP LOCKED keeps
+// concurrent collectors from batching the same job twice.
+func CreateRegistrationBatch(db *sql.DB, maxSize int, window time.Duration, build BatchBuilder) (*models.RegistrationBatch, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting batch transaction: %v", err)
+		return nil, err
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	rows, err := tx.Query(DialectOf(db).query(
+		`SELECT id, recipe_id, content_hash, creator_address, status, attempts, next_attempt_at, locked_until, last_error, created_at
+         FROM registration_jobs
+         WHERE status = 'pending' AND next_attempt_at <= NOW()
+         ORDER BY id
+         LIMIT $1
+         FOR UPDATE SKIP LOCKED`,
+		`SELECT id, recipe_id, content_hash, creator_address, status, attempts, next_attempt_at, locked_until, last_error, created_at
+         FROM registration_jobs
+         WHERE status = 'pending' AND julianday(next_attempt_at) <= julianday('now')
+         ORDER BY id
+         LIMIT $1`,
+	), maxSize)
+	if err != nil {
+		log.Printf("Error selecting jobs for a batch: %v", err)
+		return nil, err
+	}
+	var jobs []models.RegistrationJob
+	var oldest time.Time
+	for rows.Next() {
+		var job models.RegistrationJob
+		var createdAt time.Time
+		if err := rows.Scan(&job.ID, &job.RecipeID, &job.ContentHash, &job.CreatorAddress, &job.Status,
+			&job.Attempts, &job.NextAttemptAt, &job.LockedUntil, &job.LastError, &createdAt); err != nil {
+			rows.Close()
+			log.Printf("Error scanning registration job row: %v", err)
+			return nil, err
+		}
+		if oldest.IsZero() || createdAt.Before(oldest) {
+			oldest = createdAt
+		}
+		jobs = append(jobs, job)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating registration job rows: %v", err)
+		return nil, err
+	}
+
+	if len(jobs) == 0 || (len(jobs) < maxSize && time.Since(oldest) < window) {
+		return nil, nil
+	}
+
+	root, leaves, err := build(jobs)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := models.RegistrationBatch{MerkleRoot: root, LeafCount: len(leaves), Status: models.JobStatusPending}
+	err = tx.QueryRow(
+		`INSERT INTO registration_batches (merkle_root, leaf_count) VALUES ($1, $2)
+         RETURNING id, attempts, next_attempt_at`,
+		root, len(leaves),
+	).Scan(&batch.ID, &batch.Attempts, &batch.NextAttemptAt)
+	if err != nil {
+		log.Printf("Error inserting registration batch %s: %v", root, err)
+		return nil, err
+	}
+
+	for _, leaf := range leaves {
+		proof, err := json.Marshal(leaf.Proof)
+		if err != nil {
+			return nil, err
+		}
+		// A recipe re-enqueued after a failed batch gets the proof of its new batch
+		_, err = tx.Exec(
+			`INSERT INTO recipe_batch_proofs (recipe_id, batch_id, leaf_index, leaf, proof)
+             VALUES ($1, $2, $3, $4, $5)
+             ON CONFLICT (recipe_id) DO UPDATE
+             SET batch_id = EXCLUDED.batch_id, leaf_index = EXCLUDED.leaf_index, leaf = EXCLUDED.leaf, proof = EXCLUDED.proof`,
+			leaf.RecipeID, batch.ID, leaf.LeafIndex, leaf.Leaf, string(proof),
+		)
+		if err != nil {
+			log.Printf("Error storing batch proof for recipe %d: %v", leaf.RecipeID, err)
+			return nil, err
+		}
+	}
+	for _, job := range jobs {
+		_, err = tx.Exec(
+			`UPDATE registration_jobs SET status = 'batched', batch_id = $2, locked_until = NULL, updated_at = NOW()
+             WHERE id = $1`,
+			job.ID, batch.ID,
+		)
+		if err != nil {
+			log.Printf("Error adding registration job %d to batch %d: %v", job.ID, batch.ID, err)
+			return nil, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		log.Printf("Error committing batch transaction: %v", err)
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// ClaimRegistrationBatch claims one batch that is due for anchoring, holding it for
+// the given lease. It returns nil when there is nothing to do.
+func ClaimRegistrationBatch(db *sql.DB, lease time.Duration) (*models.RegistrationBatch, error) {
+	var batch models.RegistrationBatch
+	err := db.QueryRow(DialectOf(db).query(
+		`UPDATE registration_batches
+         SET status = 'processing', attempts = attempts + 1,
+             locked_until = NOW() + make_interval(secs => $1), updated_at = NOW()
+         WHERE id = (
+             SELECT id FROM registration_batches
+             WHERE (status = 'pending' AND next_attempt_at <= NOW())
+                OR (status = 'processing' AND locked_until < NOW())
+             ORDER BY next_attempt_at
+             LIMIT 1
+             FOR UPDATE SKIP LOCKED
+         )
+         RETURNING id, merkle_root, leaf_count, status, attempts, next_attempt_at, locked_until, last_error`,
+		`UPDATE registration_batches
+         SET status = 'processing', attempts = attempts + 1,
+             locked_until = `+sqliteSecondsFromNow("$1")+`, updated_at = NOW()
+         WHERE id = (
+             SELECT id FROM registration_batches
+             WHERE (status = 'pending' AND julianday(next_attempt_at) <= julianday('now'))
+                OR (status = 'processing' AND julianday(locked_until) < julianday('now'))
+             ORDER BY julianday(next_attempt_at)
+             LIMIT 1
+         )
+         RETURNING id, merkle_root, leaf_count, status, attempts, next_attempt_at, locked_until, last_error`,
+	), lease.Seconds()).Scan(&batch.ID, &batch.MerkleRoot, &batch.LeafCount, &batch.Status, &batch.Attempts,
+		&batch.NextAttemptAt, &batch.LockedUntil, &batch.LastError)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error claiming registration batch: %v", err)
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// MarkBatchSubmitted records a broadcast anchorBatch transaction, including fee-bumped
+// replacements, and marks the batch and its recipes as submitted with it. Arguments
+// are as for RecordRegistrationTx.
+func MarkBatchSubmitted(db *sql.DB, batchID int, txHash, from string, nonce uint64, gasFeeCap, gasTipCap string, replaces string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting submission transaction for batch %d: %v", batchID, err)
+		return err
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	var replacesArg any
+	if replaces != "" {
+		replacesArg = replaces
+	}
+	_, err = tx.Exec(
+		`INSERT INTO batch_transactions (batch_id, tx_hash, from_address, nonce, gas_fee_cap, gas_tip_cap, replaces)
+         VALUES ($1, $2, $3, $4, $5, $6, $7)
+         ON CONFLICT (tx_hash) DO NOTHING`,
+		batchID, txHash, from, int64(nonce), gasFeeCap, gasTipCap, replacesArg,
+	)
+	if err != nil {
+		log.Printf("Error recording tx %s of batch %d: %v", txHash, batchID, err)
+		return err
+	}
+	if _, err := tx.Exec(`UPDATE registration_batches SET tx_hash = $2, updated_at = NOW() WHERE id = $1`, batchID, txHash); err != nil {
+		log.Printf("Error marking batch %d as submitted: %v", batchID, err)
+		return err
+	}
+	_, err = tx.Exec(
+		`UPDATE recipes SET registration_status = 'submitted', registration_tx_hash = $2
+         WHERE id IN (SELECT recipe_id FROM recipe_batch_proofs WHERE batch_id = $1)`,
+		batchID, txHash,
+	)
+	if err != nil {
+		log.Printf("Error marking recipes of batch %d as submitted: %v", batchID, err)
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetPendingBatchTxs returns the anchorBatch transactions of a batch that are
+// neither mined nor replaced, in the order they were broadcast.
+func GetPendingBatchTxs(db DBTX, batchID int) ([]models.RegistrationTx, error) {
+	rows, err := db.Query(
+		`SELECT tx_hash, from_address, nonce, gas_fee_cap, gas_tip_cap, replaces
+         FROM batch_transactions
+         WHERE batch_id = $1 AND status = 'pending'
+         ORDER BY id`,
+		batchID,
+	)
+	if err != nil {
+		log.Printf("Error loading pending txs of batch %d: %v", batchID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txs []models.RegistrationTx
+	for rows.Next() {
+		var tx models.RegistrationTx
+		var nonce int64
+		if err := rows.Scan(&tx.TxHash, &tx.FromAddress, &nonce, &tx.GasFeeCap, &tx.GasTipCap, &tx.Replaces); err != nil {
+			log.Printf("Error scanning batch tx row: %v", err)
+			return nil, err
+		}
+		tx.Nonce = uint64(nonce)
+		txs = append(txs, tx)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating batch tx rows: %v", err)
+		return nil, err
+	}
+	return txs, nil
+}
+
+// ConfirmRegistrationBatch completes the batch, its jobs and its recipes with the
+// mined anchorBatch transaction in one transaction.
+func ConfirmRegistrationBatch(db *sql.DB, batchID int, txHash string, blockNumber int64, anchoredAt time.Time) error {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting confirmation transaction for batch %d: %v", batchID, err)
+		return err
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	_, err = tx.Exec(
+		`UPDATE registration_batches SET status = 'done', tx_hash = $2, block_number = $3, anchored_at = $4,
+             locked_until = NULL, last_error = NULL, updated_at = NOW()
+         WHERE id = $1`,
+		batchID, txHash, blockNumber, anchoredAt,
+	)
+	if err != nil {
+		log.Printf("Error confirming batch %d: %v", batchID, err)
+		return err
+	}
+	_, err = tx.Exec(
+		`UPDATE batch_transactions
+         SET status = CASE WHEN tx_hash = $2 THEN 'mined' ELSE 'replaced' END
+         WHERE batch_id = $1 AND status = 'pending'`,
+		batchID, txHash,
+	)
+	if err != nil {
+		log.Printf("Error marking tx %s of batch %d as mined: %v", txHash, batchID, err)
+		return err
+	}
+	_, err = tx.Exec(
+		`UPDATE recipes SET registration_status = 'confirmed', registration_tx_hash = $2,
+             registration_block_number = $3, registration_confirmed_at = $4
+         WHERE id IN (SELECT recipe_id FROM recipe_batch_proofs WHERE batch_id = $1)`,
+		batchID, txHash, blockNumber, anchoredAt,
+	)
+	if err != nil {
+		log.Printf("Error marking recipes of batch %d as confirmed: %v", batchID, err)
+		return err
+	}
+	if err := finishBatchJobs(tx, batchID, models.JobStatusDone, nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ConfirmRegistrationBatchFromChain completes a batch whose root turned out to be
+// anchored already (e.g. an earlier attempt was mined after it timed out).
+func ConfirmRegistrationBatchFromChain(db *sql.DB, batchID int, anchoredAt time.Time) error {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting confirmation transaction for batch %d: %v", batchID, err)
+		return err
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	_, err = tx.Exec(
+		`UPDATE registration_batches SET status = 'done', anchored_at = $2, locked_until = NULL, last_error = NULL, updated_at = NOW()
+         WHERE id = $1`,
+		batchID, anchoredAt,
+	)
+	if err != nil {
+		log.Printf("Error confirming batch %d: %v", batchID, err)
+		return err
+	}
+	_, err = tx.Exec(
+		`UPDATE recipes SET registration_status = 'confirmed', registration_confirmed_at = $2
+         WHERE id IN (SELECT recipe_id FROM recipe_batch_proofs WHERE batch_id = $1)`,
+		batchID, anchoredAt,
+	)
+	if err != nil {
+		log.Printf("Error marking recipes of batch %d as confirmed: %v", batchID, err)
+		return err
+	}
+	if err := finishBatchJobs(tx, batchID, models.JobStatusDone, nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RetryRegistrationBatch releases a batch to be anchored again at nextAttempt.
+func RetryRegistrationBatch(db *sql.DB, batchID int, nextAttempt time.Time, lastError string) error {
+	return rescheduleRegistrationBatch(db, batchID, nextAttempt, lastError, 0)
+}
+
+// ReleaseRegistrationBatch is RetryRegistrationBatch for an interrupted attempt: the
+// batch is due right away and the attempt does not count towards the maximum.
+func ReleaseRegistrationBatch(db *sql.DB, batchID int, lastError string) error {
+	return rescheduleRegistrationBatch(db, batchID, time.Now(), lastError, 1)
+}
+
+func rescheduleRegistrationBatch(db *sql.DB, batchID int, nextAttempt time.Time, lastError string, refund int) error {
+	_, err := db.Exec(
+		`UPDATE registration_batches SET status = 'pending', next_attempt_at = $2, locked_until = NULL, last_error = $3,
+             attempts = attempts - $4, updated_at = NOW()
+         WHERE id = $1`,
+		batchID, nextAttempt, lastError, refund,
+	)
+	if err != nil {
+		log.Printf("Error rescheduling registration batch %d: %v", batchID, err)
+	}
+	return err
+}
+
+// FailRegistrationBatch records a terminal failure of the batch, its jobs and its recipes.
+func FailRegistrationBatch(db *sql.DB, batchID int, lastError string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting failure transaction for batch %d: %v", batchID, err)
+		return err
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	_, err = tx.Exec(
+		`UPDATE registration_batches SET status = 'failed', locked_until = NULL, last_error = $2, updated_at = NOW()
+         WHERE id = $1`,
+		batchID, lastError,
+	)
+	if err != nil {
+		log.Printf("Error failing registration batch %d: %v", batchID, err)
+		return err
+	}
+	_, err = tx.Exec(
+		`UPDATE recipes SET registration_status = 'failed'
+         WHERE id IN (SELECT recipe_id FROM recipe_batch_proofs WHERE batch_id = $1)`,
+		batchID,
+	)
+	if err != nil {
+		log.Printf("Error marking recipes of batch %d as failed: %v", batchID, err)
+		return err
+	}
+	if err := finishBatchJobs(tx, batchID, models.JobStatusFailed, &lastError); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// finishBatchJobs moves the batched jobs of a batch to a final status.
+func finishBatchJobs(db DBTX, batchID int, status string, lastError *string) error {
+	_, err := db.Exec(
+		`UPDATE registration_jobs SET status = $2, last_error = $3, updated_at = NOW()
+         WHERE batch_id = $1 AND status = 'batched'`,
+		batchID, status, lastError,
+	)
+	if err != nil {
+		log.Printf("Error updating jobs of batch %d to %s: %v", batchID, status, err)
+	}
+	return err
+}
+
+// GetBatchProof returns the inclusion proof of the recipe with the given content hash,
+// or nil if the recipe was never put in a batch.
+func GetBatchProof(db DBTX, contentHash string) (*models.RecipeBatchProof, error) {
+	var proof models.RecipeBatchProof
+	var rawProof string
+	err := db.QueryRow(
+		`SELECT r.content_hash, r.creator_address, p.leaf, p.leaf_index, p.proof,
+             b.merkle_root, b.leaf_count, b.status, b.tx_hash, b.block_number, b.anchored_at
+         FROM recipe_batch_proofs p
+         JOIN recipes r ON r.id = p.recipe_id
+         JOIN registration_batches b ON b.id = p.batch_id
+         WHERE r.content_hash = $1`,
+		contentHash,
+	).Scan(&proof.ContentHash, &proof.CreatorAddress, &proof.Leaf, &proof.LeafIndex, &rawProof,
+		&proof.MerkleRoot, &proof.LeafCount, &proof.BatchStatus, &proof.TxHash, &proof.BlockNumber, &proof.AnchoredAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		log.Printf("Error retrieving batch proof for hash %s: %v", contentHash, err)
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(rawProof), &proof.Proof); err != nil {
+		log.Printf("Error decoding batch proof for hash %s: %v", contentHash, err)
+		return nil, err
+	}
+	return &proof, nil
+}
@@ -0,0 +1,245 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+	"time"
+
+	"proofpot-backend/models"
+)
+
+// EnqueueRegistrationJob adds a pending on-chain registration job for a recipe.
+// Pass a *sql.Tx to make the job part of the recipe insert.
+func EnqueueRegistrationJob(db DBTX, recipeID int, contentHash, creatorAddress string) error {
+	_, err := db.Exec(
+		`INSERT INTO registration_jobs (recipe_id, content_hash, creator_address)
+         VALUES ($1, $2, $3)`,
+		recipeID, contentHash, creatorAddress,
+	)
+	if err != nil {
+		log.Printf("Error enqueueing registration job for hash %s: %v", contentHash, err)
+		return err
+	}
+	return nil
+}
+
+// ReenqueueRegistrationJob adds a new job for a recipe that is missing on chain and
+// sets the recipe back to pending, in one transaction.
+func ReenqueueRegistrationJob(db *sql.DB, recipeID int, contentHash, creatorAddress string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting re-enqueue transaction for recipe %d: %v", recipeID, err)
+		return err
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	if err := EnqueueRegistrationJob(tx, recipeID, contentHash, creatorAddress); err != nil {
+		return err
+	}
+	if err := SetRecipeRegistrationStatus(tx, recipeID, models.RegistrationPending); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ClaimRegistrationJobs atomically claims up to limit jobs that are due, holding each
+// for the given lease. Jobs whose lease expired (e.g. the worker crashed mid-flight)
+// are claimed again. SKIP LOCKED lets several workers and instances poll concurrently;
+// SQLite serializes writers, so its variant needs no row locks.
+func ClaimRegistrationJobs(db *sql.DB, limit int, lease time.Duration) ([]models.RegistrationJob, error) {
+	rows, err := db.Query(DialectOf(db).query(
+		`UPDATE registration_jobs
+         SET status = 'processing', attempts = attempts + 1,
+             locked_until = NOW() + make_interval(secs => $2), updated_at = NOW()
+         WHERE id IN (
+             SELECT id FROM registration_jobs
+             WHERE (status = 'pending' AND next_attempt_at <= NOW())
+                OR (status = 'processing' AND locked_until < NOW())
+             ORDER BY next_attempt_at
+             LIMIT $1
+             FOR UPDATE SKIP LOCKED
+         )
+         RETURNING id, recipe_id, content_hash, creator_address, status, attempts, next_attempt_at, locked_until, last_error`,
+		`UPDATE registration_jobs
+         SET status = 'processing', attempts = attempts + 1,
+             locked_until = `+sqliteSecondsFromNow("$2")+`, updated_at = NOW()
+         WHERE id IN (
+             SELECT id FROM registration_jobs
+             WHERE (status = 'pending' AND julianday(next_attempt_at) <= julianday('now'))
+                OR (status = 'processing' AND julianday(locked_until) < julianday('now'))
+             ORDER BY julianday(next_attempt_at)
+             LIMIT $1
+         )
+         RETURNING id, recipe_id, content_hash, creator_address, status, attempts, next_attempt_at, locked_until, last_error`,
+	), limit, lease.Seconds())
+	if err != nil {
+		log.Printf("Error claiming registration jobs: %v", err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var jobs []models.RegistrationJob
+	for rows.Next() {
+		var job models.RegistrationJob
+		if err := rows.Scan(&job.ID, &job.RecipeID, &job.ContentHash, &job.CreatorAddress, &job.Status,
+			&job.Attempts, &job.NextAttemptAt, &job.LockedUntil, &job.LastError); err != nil {
+			log.Printf("Error scanning registration job row: %v", err)
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err = rows.Err(); err != nil {
+		log.Printf("Error iterating registration job rows: %v", err)
+		return nil, err
+	}
+
+	return jobs, nil
+}
+
+// CompleteRegistrationJob marks a job as successfully registered on chain.
+func CompleteRegistrationJob(db DBTX, jobID int) error {
+	_, err := db.Exec(
+		`UPDATE registration_jobs SET status = 'done', locked_until = NULL, last_error = NULL, updated_at = NOW()
+         WHERE id = $1`,
+		jobID,
+	)
+	if err != nil {
+		log.Printf("Error completing registration job %d: %v", jobID, err)
+	}
+	return err
+}
+
+// ConfirmRegistrationJob completes the job and records the mined transaction on the
+// recipe in one transaction, so the two never disagree.
+func ConfirmRegistrationJob(db *sql.DB, jobID, recipeID int, txHash string, blockNumber int64, confirmedAt time.Time) error {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting confirmation transaction for job %d: %v", jobID, err)
+		return err
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	if err := MarkRecipeConfirmed(tx, recipeID, txHash, blockNumber, confirmedAt); err != nil {
+		return err
+	}
+	if err := CompleteRegistrationJob(tx, jobID); err != nil {
+		return err
+	}
+	if err := MarkRegistrationTxMined(tx, recipeID, txHash); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ConfirmRegistrationJobFromChain completes a job whose hash turned out to be registered
+// already (the transaction is unknown here; the indexer fills it in when enabled).
+func ConfirmRegistrationJobFromChain(db *sql.DB, jobID, recipeID int, confirmedAt time.Time) error {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting confirmation transaction for job %d: %v", jobID, err)
+		return err
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	_, err = tx.Exec(
+		`UPDATE recipes SET registration_status = 'confirmed', registration_confirmed_at = $2 WHERE id = $1`,
+		recipeID, confirmedAt,
+	)
+	if err != nil {
+		log.Printf("Error marking recipe %d as confirmed: %v", recipeID, err)
+		return err
+	}
+	if err := CompleteRegistrationJob(tx, jobID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RetryRegistrationJob releases a job back to the queue to be attempted again at
+// nextAttempt, in one transaction with its recipe's status. A recipe whose
+// transaction was broadcast stays submitted, since that transaction may still be
+// mined; others go back to pending.
+func RetryRegistrationJob(db *sql.DB, jobID, recipeID int, nextAttempt time.Time, lastError string) error {
+	return rescheduleRegistrationJob(db, jobID, recipeID, nextAttempt, lastError, 0)
+}
+
+// ReleaseRegistrationJob is RetryRegistrationJob for an attempt that was interrupted
+// rather than failed, e.g. by a shutdown: the job is due right away and the attempt
+// does not count towards the maximum.
+func ReleaseRegistrationJob(db *sql.DB, jobID, recipeID int, lastError string) error {
+	return rescheduleRegistrationJob(db, jobID, recipeID, time.Now(), lastError, 1)
+}
+
+// rescheduleRegistrationJob implements RetryRegistrationJob and ReleaseRegistrationJob;
+// refund is the number of attempts given back (claiming the job counted one).
+func rescheduleRegistrationJob(db *sql.DB, jobID, recipeID int, nextAttempt time.Time, lastError string, refund int) error {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting retry transaction for job %d: %v", jobID, err)
+		return err
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	_, err = tx.Exec(
+		`UPDATE registration_jobs SET status = 'pending', next_attempt_at = $2, locked_until = NULL, last_error = $3,
+             attempts = attempts - $4, updated_at = NOW()
+         WHERE id = $1`,
+		jobID, nextAttempt, lastError, refund,
+	)
+	if err != nil {
+		log.Printf("Error rescheduling registration job %d: %v", jobID, err)
+		return err
+	}
+	_, err = tx.Exec(
+		`UPDATE recipes
+         SET registration_status = CASE WHEN registration_tx_hash IS NULL THEN 'pending' ELSE 'submitted' END
+         WHERE id = $1`,
+		recipeID,
+	)
+	if err != nil {
+		log.Printf("Error resetting registration status of recipe %d: %v", recipeID, err)
+		return err
+	}
+	return tx.Commit()
+}
+
+// FailRegistrationJob records a terminal failure and marks the recipe as failed,
+// in one transaction; the job will not be retried.
+func FailRegistrationJob(db *sql.DB, jobID, recipeID int, lastError string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting failure transaction for job %d: %v", jobID, err)
+		return err
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	_, err = tx.Exec(
+		`UPDATE registration_jobs SET status = 'failed', locked_until = NULL, last_error = $2, updated_at = NOW()
+         WHERE id = $1`,
+		jobID, lastError,
+	)
+	if err != nil {
+		log.Printf("Error failing registration job %d: %v", jobID, err)
+		return err
+	}
+	if err := SetRecipeRegistrationStatus(tx, recipeID, models.RegistrationFailed); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// HasOpenRegistrationJob reports whether the recipe still has a job that will be
+// (re)tried, i.e. one that is pending, being processed or waiting in a batch.
+func HasOpenRegistrationJob(db DBTX, recipeID int) (bool, error) {
+	var exists bool
+	err := db.QueryRow(
+		`SELECT EXISTS(SELECT 1 FROM registration_jobs WHERE recipe_id = $1 AND status IN ('pending', 'processing', 'batched'))`,
+		recipeID,
+	).Scan(&exists)
+	if err != nil {
+		log.Printf("Error checking open registration jobs for recipe %d: %v", recipeID, err)
+		return false, err
+	}
+	return exists, nil
+}
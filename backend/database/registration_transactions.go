@@ -0,0 +1,95 @@
+package database
+
+import (
+	"database/sql"
+	"log"
+
+	"proofpot-backend/models"
+)
+
+// RecordRegistrationTx stores a broadcast registration transaction for a recipe.
+// from is the signer account that sent it; fee values are passed as decimal wei strings.
+func RecordRegistrationTx(db DBTX, recipeID int, txHash, from string, nonce uint64, gasFeeCap, gasTipCap string, replaces string) error {
+	var replacesArg any
+	if replaces != "" {
+		replacesArg = replaces
+	}
+	_, err := db.Exec(
+		`INSERT INTO registration_transactions (recipe_id, tx_hash, from_address, nonce, gas_fee_cap, gas_tip_cap, replaces)
+         VALUES ($1, $2, $3, $4, $5, $6, $7)
+         ON CONFLICT (tx_hash) DO NOTHING`,
+		recipeID, txHash, from, int64(nonce), gasFeeCap, gasTipCap, replacesArg,
+	)
+	if err != nil {
+		log.Printf("Error recording registration tx %s for recipe %d: %v", txHash, recipeID, err)
+	}
+	return err
+}
+
+// RecordRegistrationSubmission stores a broadcast registration transaction and
+// marks the recipe as submitted with it, in one transaction.
+func RecordRegistrationSubmission(db *sql.DB, recipeID int, txHash, from string, nonce uint64, gasFeeCap, gasTipCap string, replaces string) error {
+	tx, err := db.Begin()
+	if err != nil {
+		log.Printf("Error starting submission transaction for recipe %d: %v", recipeID, err)
+		return err
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	if err := RecordRegistrationTx(tx, recipeID, txHash, from, nonce, gasFeeCap, gasTipCap, replaces); err != nil {
+		return err
+	}
+	if err := MarkRecipeSubmitted(tx, recipeID, txHash); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// MarkRegistrationTxMined marks the mined transaction of a recipe; every other
+// pending transaction of the recipe was replaced or dropped.
+func MarkRegistrationTxMined(db DBTX, recipeID int, txHash string) error {
+	_, err := db.Exec(
+		`UPDATE registration_transactions
+         SET status = CASE WHEN tx_hash = $2 THEN 'mined' ELSE 'replaced' END
+         WHERE recipe_id = $1 AND status = 'pending'`,
+		recipeID, txHash,
+	)
+	if err != nil {
+		log.Printf("Error marking registration tx %s of recipe %d as mined: %v", txHash, recipeID, err)
+	}
+	return err
+}
+
+// GetPendingRegistrationTxs returns the transactions of a recipe that are neither
+// mined nor replaced, in the order they were broadcast.
+func GetPendingRegistrationTxs(db DBTX, recipeID int) ([]models.RegistrationTx, error) {
+	rows, err := db.Query(
+		`SELECT tx_hash, COALESCE(from_address, ''), nonce, gas_fee_cap, gas_tip_cap, replaces
+         FROM registration_transactions
+         WHERE recipe_id = $1 AND status = 'pending'
+         ORDER BY id`,
+		recipeID,
+	)
+	if err != nil {
+		log.Printf("Error loading pending registration txs of recipe %d: %v", recipeID, err)
+		return nil, err
+	}
+	defer rows.Close()
+
+	var txs []models.RegistrationTx
+	for rows.Next() {
+		var tx models.RegistrationTx
+		var nonce int64
+		if err := rows.Scan(&tx.TxHash, &tx.FromAddress, &nonce, &tx.GasFeeCap, &tx.GasTipCap, &tx.Replaces); err != nil {
+			log.Printf("Error scanning registration tx row: %v", err)
+			return nil, err
+		}
+		tx.Nonce = uint64(nonce)
+		txs = append(txs, tx)
+	}
+	if err := rows.Err(); err != nil {
+		log.Printf("Error iterating registration tx rows: %v", err)
+		return nil, err
+	}
+	return txs, nil
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"modernc.org/sqlite" // SQLite driver (pure Go, builds with CGO_ENABLED=0)
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+// sqliteScheme selects the SQLite backend, e.g. sqlite:///data/proofpot.db for an
+// absolute path or sqlite://proofpot.db for one relative to the working directory.
+const sqliteScheme = "sqlite://"
+
+// sqliteTimeFormat is how the driver writes time.Time values with
+// _time_format=sqlite. now() uses it too, so stored timestamps look alike. Times
+// passed in by the application may carry a local offset, so the SQLite queries
+// compare timestamps with julianday(), which normalizes them to UTC.
+const sqliteTimeFormat = "2006-01-02 15:04:05.999999999-07:00"
+
+// sqliteDefaultPragmas are run on every SQLite connection unless the URL sets the
+// same pragma. Foreign keys are off by default in SQLite but the schema relies on
+// cascades, WAL lets the API read while the workers write, and the busy timeout
+// makes concurrent writers wait instead of failing.
+var sqliteDefaultPragmas = []string{"foreign_keys(1)", "journal_mode(WAL)", "busy_timeout(5000)"}
+
+// sqliteDefaultParams are the driver parameters used unless the URL sets them.
+// Immediate transactions take the write lock up front, so two transactions never
+// deadlock upgrading from a read lock.
+var sqliteDefaultParams = map[string]string{
+	"_time_format": "sqlite",
+	"_txlock":      "immediate",
+}
+
+func init() {
+	// The queries share NOW() with Postgres; SQLite has no such function.
+	sqlite.MustRegisterScalarFunction("now", 0, func(*sqlite.FunctionContext, []driver.Value) (driver.Value, error) {
+		return time.Now().UTC().Format(sqliteTimeFormat), nil
+	})
+}
+
+// sqliteDSN turns a sqlite:// DATABASE_URL into a connection string for the
+// modernc.org/sqlite driver, which needs no cgo.
+func sqliteDSN(databaseURL string) (string, error) {
+	rest := strings.TrimPrefix(databaseURL, sqliteScheme)
+	path, rawQuery, _ := strings.Cut(rest, "?")
+	if path == "" {
+		return "", errors.New("sqlite DATABASE_URL needs a file path, e.g. sqlite:///data/proofpot.db")
+	}
+	params, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return "", fmt.Errorf("invalid sqlite DATABASE_URL parameters: %w", err)
+	}
+	for key, value := range sqliteDefaultParams {
+		if !params.Has(key) {
+			params.Set(key, value)
+		}
+	}
+	for _, pragma := range sqliteDefaultPragmas {
+		if !hasPragma(params["_pragma"], pragmaName(pragma)) {
+			params.Add("_pragma", pragma)
+		}
+	}
+	return "file:" + path + "?" + params.Encode(), nil
+}
+
+func hasPragma(pragmas []string, name string) bool {
+	for _, pragma := range pragmas {
+		if strings.EqualFold(pragmaName(pragma), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// pragmaName returns "busy_timeout" for both "busy_timeout(5000)" and "busy_timeout=5000".
+func pragmaName(pragma string) string {
+	if i := strings.IndexAny(pragma, "(="); i >= 0 {
+		pragma = pragma[:i]
+	}
+	return strings.TrimSpace(pragma)
+}
+
+// sqliteSecondsFromNow returns an SQLite expression for the UTC time param seconds
+// from now. Postgres queries write NOW() + make_interval(secs => param) instead.
+func sqliteSecondsFromNow(param string) string {
+	return fmt.Sprintf(`strftime('%%Y-%%m-%%d %%H:%%M:%%f+00:00', 'now', printf('%%+f seconds', %s))`, param)
+}
+
+// isSQLiteUniqueViolation reports whether err is a UNIQUE constraint failure.
+func isSQLiteUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	return errors.As(err, &sqliteErr) && sqliteErr.Code() == sqlite3.SQLITE_CONSTRAINT_UNIQUE
+}
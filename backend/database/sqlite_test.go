@@ -0,0 +1,223 @@
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"proofpot-backend/models"
+)
+
+// openTestSQLite opens a migrated SQLite database in a temporary directory.
+func openTestSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Open(sqliteScheme + filepath.Join(t.TempDir(), "proofpot.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := MigrateUp(db); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+	return db
+}
+
+func TestSQLiteDSN(t *testing.T) {
+	dsn, err := sqliteDSN("sqlite:///data/proofpot.db?_pragma=busy_timeout%3D100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(dsn, "file:/data/proofpot.db?") {
+		t.Errorf("dsn = %q, want the absolute path", dsn)
+	}
+	if strings.Contains(dsn, "busy_timeout%285000") || !strings.Contains(dsn, "foreign_keys%281%29") {
+		t.Errorf("dsn = %q, want the URL's busy_timeout and the default foreign_keys", dsn)
+	}
+	if _, err := sqliteDSN("sqlite://"); err == nil {
+		t.Error("sqliteDSN accepted a URL without a path")
+	}
+}
+
+func TestDialectOf(t *testing.T) {
+	// Handles of both dialects can be open at once; each keeps its own
+	sqliteDB := openTestSQLite(t)
+	postgresDB, err := Open("postgres://localhost/proofpot_test?sslmode=disable") // Not connected until used
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer postgresDB.Close()
+
+	if d := DialectOf(sqliteDB); d != SQLite {
+		t.Errorf("DialectOf(sqlite handle) = %s, want sqlite", d)
+	}
+	if d := DialectOf(postgresDB); d != Postgres {
+		t.Errorf("DialectOf(postgres handle) = %s, want postgres", d)
+	}
+	if d := NewSQLStore(sqliteDB).dialect; d != SQLite {
+		t.Errorf("SQLStore dialect = %s, want sqlite", d)
+	}
+}
+
+func TestSQLiteMigrations(t *testing.T) {
+	db := openTestSQLite(t)
+
+	pending, err := PendingMigrations(db)
+	if err != nil || len(pending) != 0 {
+		t.Fatalf("pending after MigrateUp = %v, %v", pending, err)
+	}
+	migrations, _ := Migrations(SQLite)
+	reverted, err := MigrateDown(db, len(migrations))
+	if err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+	if len(reverted) != len(migrations) {
+		t.Errorf("reverted %d migrations, want %d", len(reverted), len(migrations))
+	}
+	if _, err := MigrateUp(db); err != nil {
+		t.Fatalf("MigrateUp after reverting everything: %v", err)
+	}
+}
+
+func TestSQLStoreOnSQLite(t *testing.T) {
+	store := NewSQLStore(openTestSQLite(t))
+	payload := models.RecipeCreatePayload{
+		Title: "Pancakes", Ingredients: "eggs", Steps: "fry", CreatorAddress: "0x01",
+		ContentHash: "0xaa", HashScheme: 2, CreatorSignature: "0xsig",
+	}
+	if _, err := store.Create(payload); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if _, err := store.Create(payload); !errors.Is(err, ErrDuplicateHash) {
+		t.Errorf("duplicate Create error = %v, want ErrDuplicateHash", err)
+	}
+
+	recipe, err := store.GetByHash("0xaa")
+	if err != nil || recipe == nil {
+		t.Fatalf("GetByHash = %v, %v", recipe, err)
+	}
+	if recipe.Registration.Status != models.RegistrationPending || recipe.CreatedAt.IsZero() {
+		t.Errorf("stored recipe = %+v", recipe)
+	}
+	if page, err := store.List(models.RecipeListQuery{Sort: models.RecipeSortNewest, Limit: 10}); err != nil || len(page.Recipes) != 1 {
+		t.Errorf("List = %+v, %v", page, err)
+	}
+
+	imageURL := "https://example.com/pancakes.jpg"
+	if updated, err := store.Update("0xaa", models.RecipeUpdate{ImageURL: &imageURL}); err != nil || *updated.ImageURL != imageURL {
+		t.Errorf("Update = %+v, %v", updated, err)
+	}
+	if _, err := store.Update("0xbb", models.RecipeUpdate{ImageURL: &imageURL}); !errors.Is(err, ErrRecipeNotFound) {
+		t.Errorf("Update unknown error = %v, want ErrRecipeNotFound", err)
+	}
+
+	// Deleting cascades to the registration job
+	if err := store.Delete("0xaa"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	var jobs int
+	store.db.QueryRow(`SELECT COUNT(*) FROM registration_jobs`).Scan(&jobs)
+	if jobs != 0 {
+		t.Errorf("%d registration jobs left after Delete", jobs)
+	}
+}
+
+func TestRegistrationQueueOnSQLite(t *testing.T) {
+	db := openTestSQLite(t)
+	for _, hash := range []string{"0x01", "0x02", "0x03"} {
+		payload := models.RecipeCreatePayload{Title: hash, Ingredients: "i", Steps: "s", CreatorAddress: "0xc0", ContentHash: hash}
+		if _, err := CreateRecipeWithRegistrationJob(db, payload); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	jobs, err := ClaimRegistrationJobs(db, 1, time.Minute)
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("ClaimRegistrationJobs = %v, %v", jobs, err)
+	}
+	job := jobs[0]
+	if job.Attempts != 1 || job.LockedUntil == nil || time.Until(*job.LockedUntil) < 50*time.Second {
+		t.Errorf("claimed job = %+v, want one attempt and a one minute lease", job)
+	}
+	// A job retried later is not due, even with the retry time in a local time zone
+	nextAttempt := time.Now().Add(time.Hour).In(time.FixedZone("UTC+5", 5*3600))
+	if err := RetryRegistrationJob(db, job.ID, job.RecipeID, nextAttempt, "boom"); err != nil {
+		t.Fatal(err)
+	}
+	// A retried recipe stays submitted while its broadcast transaction may be mined
+	var status string
+	db.QueryRow(`SELECT registration_status FROM recipes WHERE id = $1`, job.RecipeID).Scan(&status)
+	if status != models.RegistrationPending {
+		t.Errorf("retried recipe without tx is %s, want pending", status)
+	}
+	if err := MarkRecipeSubmitted(db, job.RecipeID, "0xsent"); err != nil {
+		t.Fatal(err)
+	}
+	if err := RetryRegistrationJob(db, job.ID, job.RecipeID, nextAttempt, "timeout"); err != nil {
+		t.Fatal(err)
+	}
+	db.QueryRow(`SELECT registration_status FROM recipes WHERE id = $1`, job.RecipeID).Scan(&status)
+	if status != models.RegistrationSubmitted {
+		t.Errorf("retried recipe with tx is %s, want submitted", status)
+	}
+	if err := FailRegistrationJob(db, job.ID, job.RecipeID, "reverted"); err != nil {
+		t.Fatal(err)
+	}
+	var jobStatus, recipeStatus string
+	db.QueryRow(`SELECT j.status, r.registration_status FROM registration_jobs j JOIN recipes r ON r.id = j.recipe_id WHERE j.id = $1`, job.ID).Scan(&jobStatus, &recipeStatus)
+	if jobStatus != "failed" || recipeStatus != models.RegistrationFailed {
+		t.Errorf("failed job = %s, recipe %s, want both failed", jobStatus, recipeStatus)
+	}
+
+	var built []models.RegistrationJob
+	batch, err := CreateRegistrationBatch(db, 2, time.Hour, func(jobs []models.RegistrationJob) (string, []models.BatchLeaf, error) {
+		built = jobs
+		var leaves []models.BatchLeaf
+		for i, job := range jobs {
+			leaves = append(leaves, models.BatchLeaf{RecipeID: job.RecipeID, LeafIndex: i, Leaf: job.ContentHash, Proof: []string{"0xff"}})
+		}
+		return "0xroot", leaves, nil
+	})
+	if err != nil || batch == nil {
+		t.Fatalf("CreateRegistrationBatch = %v, %v", batch, err)
+	}
+	if len(built) != 2 {
+		t.Errorf("batch has %d jobs, want the 2 due ones", len(built))
+	}
+	for _, job := range built {
+		if job.ID == jobs[0].ID {
+			t.Errorf("batch contains job %d, which is not due yet", job.ID)
+		}
+	}
+
+	claimed, err := ClaimRegistrationBatch(db, time.Minute)
+	if err != nil || claimed == nil || claimed.ID != batch.ID {
+		t.Fatalf("ClaimRegistrationBatch = %+v, %v", claimed, err)
+	}
+	if again, err := ClaimRegistrationBatch(db, time.Minute); err != nil || again != nil {
+		t.Errorf("second ClaimRegistrationBatch = %+v, %v, want nothing while leased", again, err)
+	}
+	// The anchorBatch transaction and its fee-bumped replacement are resumed by the next attempt
+	if err := MarkBatchSubmitted(db, batch.ID, "0xslow", "0xfrom", 3, "100", "1", ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := MarkBatchSubmitted(db, batch.ID, "0xtx", "0xfrom", 3, "200", "2", "0xslow"); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := GetPendingBatchTxs(db, batch.ID)
+	if err != nil || len(pending) != 2 || pending[1].TxHash != "0xtx" || pending[1].Nonce != 3 || pending[1].GasFeeCap != "200" {
+		t.Fatalf("GetPendingBatchTxs = %+v, %v", pending, err)
+	}
+	if err := ConfirmRegistrationBatch(db, batch.ID, "0xtx", 7, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if pending, err := GetPendingBatchTxs(db, batch.ID); err != nil || len(pending) != 0 {
+		t.Errorf("GetPendingBatchTxs after confirmation = %+v, %v, want none", pending, err)
+	}
+	proof, err := GetBatchProof(db, built[0].ContentHash)
+	if err != nil || proof == nil || proof.BatchStatus != models.JobStatusDone || len(proof.Proof) != 1 {
+		t.Errorf("GetBatchProof = %+v, %v", proof, err)
+	}
+}
@@ -0,0 +1,139 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+
+	"proofpot-backend/models"
+
+	"github.com/lib/pq"
+)
+
+var (
+	// ErrRecipeNotFound is returned by Update and Delete for unknown content hashes.
+	ErrRecipeNotFound = errors.New("recipe not found")
+	// ErrDuplicateHash is returned by Create when a recipe with the content hash exists.
+	ErrDuplicateHash = errors.New("recipe with this content hash already exists")
+)
+
+// RecipeStore is the recipe storage used by the HTTP handlers. SQLStore is the
+// production implementation; MemoryStore keeps recipes in memory for tests
+// and local demos. Content hashes are passed in normalized form
+// (contenthash.Normalize).
+type RecipeStore interface {
+	// Create stores a new recipe together with its on-chain registration job and
+	// returns its ID.
+	Create(recipe models.RecipeCreatePayload) (int, error)
+	// GetByHash returns the recipe with the content hash, or nil if there is none.
+	GetByHash(hash string) (*models.Recipe, error)
+	// List returns one page of the recipes matching query, or ErrInvalidCursor
+	// if the query's cursor was not issued for its sort order.
+	List(query models.RecipeListQuery) (*models.RecipePage, error)
+	// Exists reports whether a recipe with the content hash is stored.
+	Exists(hash string) (bool, error)
+	// Update changes the mutable fields of a recipe and returns the updated recipe.
+	Update(hash string, update models.RecipeUpdate) (*models.Recipe, error)
+	// Delete removes a recipe and everything recorded about its registration.
+	Delete(hash string) error
+	// Search returns one page of the recipes matching the query's text, best
+	// matches first, or ErrInvalidCursor if the cursor was issued for other text.
+	Search(query models.RecipeSearchQuery) (*models.RecipeSearchPage, error)
+}
+
+// BatchProofStore reads the inclusion proofs of batch-anchored recipes.
+type BatchProofStore interface {
+	// GetBatchProof returns the recipe's proof, or nil if it was never put in a batch.
+	GetBatchProof(contentHash string) (*models.RecipeBatchProof, error)
+}
+
+// HealthChecker reports whether the storage behind the handlers is reachable.
+type HealthChecker interface {
+	// Ping returns an error if the storage cannot serve requests.
+	Ping(ctx context.Context) error
+}
+
+// SQLStore implements RecipeStore, BatchProofStore and HealthChecker on top of the
+// repository functions in this package, for Postgres and SQLite alike.
+type SQLStore struct {
+	db      *sql.DB
+	dialect Dialect
+}
+
+// NewSQLStore returns a store backed by db, a database opened by Connect or Open.
+func NewSQLStore(db *sql.DB) *SQLStore {
+	return &SQLStore{db: db, dialect: DialectOf(db)}
+}
+
+func (s *SQLStore) Create(recipe models.RecipeCreatePayload) (int, error) {
+	id, err := CreateRecipeWithRegistrationJob(s.db, recipe)
+	if isUniqueViolation(err) { // content_hash is the only unique column a new recipe can clash on
+		return 0, ErrDuplicateHash
+	}
+	return id, err
+}
+
+func (s *SQLStore) GetByHash(hash string) (*models.Recipe, error) {
+	return GetRecipeByHash(s.db, hash)
+}
+
+func (s *SQLStore) List(query models.RecipeListQuery) (*models.RecipePage, error) {
+	return ListRecipes(s.db, s.dialect, query)
+}
+
+func (s *SQLStore) Exists(hash string) (bool, error) {
+	return CheckHashExists(s.db, hash)
+}
+
+func (s *SQLStore) Update(hash string, update models.RecipeUpdate) (*models.Recipe, error) {
+	if update.ImageURL != nil {
+		result, err := s.db.Exec(`UPDATE recipes SET image_url = NULLIF($2, '') WHERE content_hash = $1`, hash, *update.ImageURL)
+		if err != nil {
+			log.Printf("Error updating recipe %s: %v", hash, err)
+			return nil, err
+		}
+		if n, err := result.RowsAffected(); err == nil && n == 0 {
+			return nil, ErrRecipeNotFound
+		}
+	}
+	recipe, err := GetRecipeByHash(s.db, hash)
+	if err == nil && recipe == nil {
+		return nil, ErrRecipeNotFound
+	}
+	return recipe, err
+}
+
+func (s *SQLStore) Delete(hash string) error {
+	// Jobs, transactions and batch proofs go with the recipe (ON DELETE CASCADE)
+	result, err := s.db.Exec(`DELETE FROM recipes WHERE content_hash = $1`, hash)
+	if err != nil {
+		log.Printf("Error deleting recipe %s: %v", hash, err)
+		return err
+	}
+	if n, err := result.RowsAffected(); err == nil && n == 0 {
+		return ErrRecipeNotFound
+	}
+	return nil
+}
+
+func (s *SQLStore) Search(query models.RecipeSearchQuery) (*models.RecipeSearchPage, error) {
+	return SearchRecipes(s.db, s.dialect, query)
+}
+
+func (s *SQLStore) GetBatchProof(contentHash string) (*models.RecipeBatchProof, error) {
+	return GetBatchProof(s.db, contentHash)
+}
+
+func (s *SQLStore) Ping(ctx context.Context) error {
+	return s.db.PingContext(ctx)
+}
+
+// isUniqueViolation reports whether err is a unique constraint failure in either dialect.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505" // unique_violation
+	}
+	return isSQLiteUniqueViolation(err)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"proofpot-backend/blockchain"
+	"proofpot-backend/certificate"
+	"proofpot-backend/contenthash"
+	"proofpot-backend/database"
+	"proofpot-backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetRecipeCertificate handles GET /api/recipes/:hash/certificate. It returns a
+// proof certificate for an anchored recipe, signed by the backend's issuer key, as a
+// downloadable JSON document. certificate.VerifyOnChain checks it without this server
+// (certificate.Verify, offline, for callers that pin this server's issuer address).
+func HandleGetRecipeCertificate(store database.RecipeStore, proofs database.BatchProofStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		getRecipeCertificate(c, store, proofs)
+	}
+}
+
+func getRecipeCertificate(c *gin.Context, store database.RecipeStore, proofs database.BatchProofStore) {
+	hash, err := contenthash.Normalize(c.Param("hash"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content hash: " + err.Error()})
+		return
+	}
+
+	recipe, err := store.GetByHash(hash)
+	if err != nil {
+		log.Printf("Error retrieving recipe by hash %s for certificate: %v", hash, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error retrieving recipe"})
+		return
+	}
+	if recipe == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+
+	// Batch-anchored recipes carry their Merkle proof
+	var batch *models.RecipeBatchProof
+	if proof, err := proofs.GetBatchProof(hash); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error retrieving proof"})
+		return
+	} else if proof != nil && proof.BatchStatus == models.JobStatusDone {
+		batch = proof
+	}
+
+	// --- Build and sign ---
+	chainID, err := blockchain.ChainID()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Blockchain connection unavailable"})
+		return
+	}
+	cert, err := certificate.New(recipe, chainID, blockchain.ContractAddress(), batch)
+	if errors.Is(err, certificate.ErrNotAnchored) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Recipe is not anchored on chain yet", "status": recipe.Registration.Status})
+		return
+	}
+	if err != nil {
+		log.Printf("Error building certificate for hash %s: %v", hash, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to build certificate"})
+		return
+	}
+	signer, err := blockchain.IssuerSigner()
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Blockchain connection unavailable"})
+		return
+	}
+	if err := cert.Sign(signer); err != nil {
+		log.Printf("Error signing certificate for hash %s: %v", hash, err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to sign certificate"})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="proofpot-certificate-%s.json"`, hash))
+	c.JSON(http.StatusOK, cert)
+}
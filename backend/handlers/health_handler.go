@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"proofpot-backend/blockchain"
+	"proofpot-backend/database"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HandleHealth handles GET /api/health. It reports database and chain connectivity.
+// Browsing only needs the database, so a missing chain connection is reported as
+// "degraded" with status 200, as is a chain with no usable signer account; a
+// database outage is a 503.
+func HandleHealth(db database.HealthChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		getHealth(c, db)
+	}
+}
+
+func getHealth(c *gin.Context, db database.HealthChecker) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	databaseStatus := "ok"
+	if err := db.Ping(ctx); err != nil {
+		log.Printf("Health check: database ping failed: %v", err)
+		databaseStatus = "unavailable"
+	}
+	chain := blockchain.CurrentStatus()
+
+	status, code := "ok", http.StatusOK
+	switch {
+	case databaseStatus != "ok":
+		status, code = "unavailable", http.StatusServiceUnavailable
+	case !chain.Connected, !chain.SignersAvailable():
+		status = "degraded"
+	}
+
+	c.JSON(code, gin.H{
+		"status":   status,
+		"database": databaseStatus,
+		"chain":    chain,
+	})
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"proofpot-backend/database"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// downDatabase is a HealthChecker whose database cannot be reached.
+type downDatabase struct{}
+
+func (downDatabase) Ping(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestHealth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		name       string
+		db         database.HealthChecker
+		wantCode   int
+		wantStatus string
+		wantDB     string
+	}{
+		// The test binary never connects to a chain
+		{"database up", database.NewMemoryStore(), http.StatusOK, "degraded", "ok"},
+		{"database down", downDatabase{}, http.StatusServiceUnavailable, "unavailable", "unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/api/health", HandleHealth(tt.db))
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/health", nil))
+
+			var body struct {
+				Status   string `json:"status"`
+				Database string `json:"database"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding %s: %v", w.Body.String(), err)
+			}
+			if w.Code != tt.wantCode || body.Status != tt.wantStatus || body.Database != tt.wantDB {
+				t.Errorf("GET /api/health = %d %+v, want %d with status %q and database %q", w.Code, body, tt.wantCode, tt.wantStatus, tt.wantDB)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"proofpot-backend/blockchain"
+	"proofpot-backend/contenthash"
+	"proofpot-backend/database"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleGetRecipeProof handles GET /api/recipes/:hash/proof. It returns the Merkle
+// inclusion proof of a recipe anchored in a batch (REGISTRATION_MODE=batch), which
+// can be checked against the root stored in the RecipeRegistry contract without
+// trusting this server.
+func HandleGetRecipeProof(store database.RecipeStore, proofs database.BatchProofStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		getRecipeProof(c, store, proofs)
+	}
+}
+
+func getRecipeProof(c *gin.Context, store database.RecipeStore, proofs database.BatchProofStore) {
+	hash, err := contenthash.Normalize(c.Param("hash"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content hash: " + err.Error()})
+		return
+	}
+
+	proof, err := proofs.GetBatchProof(hash)
+	if err != nil {
+		log.Printf("Error retrieving batch proof for hash %s: %v", hash, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error retrieving proof"})
+		return
+	}
+	if proof == nil {
+		exists, err := store.Exists(hash)
+		if err != nil {
+			log.Printf("Error checking hash existence for %s: %v", hash, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error retrieving proof"})
+			return
+		}
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe was not anchored in a batch"})
+		return
+	}
+
+	if address := blockchain.ContractAddress(); address != (common.Address{}) {
+		proof.ContractAddress = address.Hex()
+	}
+	c.JSON(http.StatusOK, proof)
+}
@@ -2,18 +2,28 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"net/http"
-	"proofpot-backend/blockchain"
+	"proofpot-backend/authorship"
+	"proofpot-backend/contenthash"
 	"proofpot-backend/database"
 	"proofpot-backend/models"
+	"strconv"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
-	"github.com/jackc/pgx/v5/pgconn" // Import for checking specific PostgreSQL errors
 )
 
 // HandleCreateRecipe handles the POST request to create a new recipe.
-func HandleCreateRecipe(c *gin.Context) {
+func HandleCreateRecipe(store database.RecipeStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		createRecipe(c, store)
+	}
+}
+
+func createRecipe(c *gin.Context, store database.RecipeStore) {
 	var payload models.RecipeCreatePayload // Bind to payload struct
 
 	// Bind and validate JSON
@@ -27,9 +37,61 @@ func HandleCreateRecipe(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing required fields"})
 		return
 	}
+	if !common.IsHexAddress(payload.CreatorAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid creator address"})
+		return
+	}
+
+	// --- Content Hash Verification ---
+	// Never trust the client-supplied hash: it must be a 32-byte value (what the
+	// contract stores) and must match the hash recomputed from the recipe content.
+	if payload.HashScheme == 0 {
+		payload.HashScheme = int(contenthash.CurrentScheme)
+	}
+	scheme, err := contenthash.ParseScheme(payload.HashScheme)
+	if err != nil || scheme == contenthash.SchemeV1 {
+		// v1 is only kept to verify recipes created before canonical hashing existed
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Unsupported content hash scheme for new recipes"})
+		return
+	}
+	normalizedHash, err := contenthash.Normalize(payload.ContentHash)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content hash: " + err.Error()})
+		return
+	}
+	expectedHash, err := contenthash.Compute(scheme, payload.Title, payload.Ingredients, payload.Steps)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content hash scheme: " + err.Error()})
+		return
+	}
+	if normalizedHash != expectedHash {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":               "Content hash does not match the recipe content",
+			"expectedContentHash": expectedHash,
+			"hashScheme":          scheme,
+		})
+		return
+	}
+	payload.ContentHash = normalizedHash
+	// --- End Content Hash Verification ---
+
+	// --- Creator Signature Verification ---
+	// The creator address is only trusted if that wallet signed the title and
+	// content hash, otherwise anyone could anchor recipes under someone else's name.
+	hashBytes, _ := contenthash.Decode(payload.ContentHash) // Validated by Normalize above
+	err = authorship.Verify(payload.Title, hashBytes, common.HexToAddress(payload.CreatorAddress), payload.CreatorSignature)
+	if errors.Is(err, authorship.ErrCreatorMismatch) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Creator signature does not match the creator address"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid creator signature: " + err.Error()})
+		return
+	}
+	// --- End Creator Signature Verification ---
 
 	// --- Step 3.5: Duplicate Hash Check ---
-	exists, err := database.CheckHashExists(payload.ContentHash)
+	exists, err := store.Exists(payload.ContentHash)
 	if err != nil {
 		log.Printf("Error checking hash existence for %s: %v", payload.ContentHash, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error checking recipe hash"})
@@ -42,13 +104,13 @@ func HandleCreateRecipe(c *gin.Context) {
 	// --- End Step 3.5 ---
 
 	// --- Step 3.6: Store Recipe in Database ---
-	// Pass database.DB and the payload
-	insertedID, err := database.InsertRecipe(database.DB, payload)
+	// The recipe and its on-chain registration job are written in one transaction,
+	// so the registration survives crashes and restarts (see the queue package).
+	insertedID, err := store.Create(payload)
 	if err != nil {
 		log.Printf("Error inserting recipe into database: %v", err)
-		// Check for specific DB errors like unique constraint violation (though CheckHashExists should prevent this)
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // 23505 is unique_violation
+		// A concurrent request can insert the same hash after the Exists check above
+		if errors.Is(err, database.ErrDuplicateHash) {
 			c.JSON(http.StatusConflict, gin.H{"error": "Recipe with this content hash already exists (database constraint)"})
 		} else {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error saving recipe"})
@@ -57,66 +119,138 @@ func HandleCreateRecipe(c *gin.Context) {
 	}
 	// --- End Step 3.6 ---
 
-	// --- Step 3.7: Trigger Smart Contract Interaction (Async) ---
-	// Launch blockchain registration in a separate goroutine
-	go func(hash, creator string) {
-		log.Printf("Starting background blockchain registration for hash: %s", hash)
-		err := blockchain.RegisterRecipeOnChain(hash, creator)
-		if err != nil {
-			// Log the error, but don't block the main request flow
-			log.Printf("ERROR: Failed background blockchain registration for hash %s: %v", hash, err)
-		} else {
-			log.Printf("Successfully completed background blockchain registration for hash: %s", hash)
-		}
-	}(payload.ContentHash, payload.CreatorAddress) // Pass necessary variables to the goroutine
+	// --- Step 3.7: Smart Contract Interaction (Async) ---
+	// Nothing to do here: the registration job committed above is picked up by the
+	// worker pool, which retries with backoff until the hash is anchored on chain.
 	// --- End Step 3.7 ---
 
-	// Respond 201 Created immediately after DB insert and launching background task
+	// Respond 201 Created immediately after the DB insert; registration happens in the background
 	c.JSON(http.StatusCreated, gin.H{
 		"id":             insertedID,
 		"title":          payload.Title,
 		"creatorAddress": payload.CreatorAddress,
 		"contentHash":    payload.ContentHash,
+		"hashScheme":     payload.HashScheme,
 		"imageUrl":       payload.ImageURL, // Include image URL if it's part of the payload
+		"registration":   models.Registration{Status: models.RegistrationPending},
 		// CreatedAt is not available here unless we re-fetch
 	})
 }
 
-// HandleGetRecipes handles the GET request to retrieve all recipes.
-func HandleGetRecipes(c *gin.Context) {
-	// Pass database.DB
-	recipes, err := database.GetAllRecipes(database.DB)
-	if err != nil {
-		log.Printf("Error retrieving recipes from database: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error retrieving recipes"})
-		return
-	}
+// Page sizes of GET /api/recipes. Larger limits are capped rather than rejected.
+const (
+	defaultRecipePageSize = 20
+	maxRecipePageSize     = 100
+)
 
-	c.JSON(http.StatusOK, recipes)
+// HandleGetRecipes handles the GET request to list recipes, one page at a time.
+// Query parameters: limit, cursor (nextCursor of the previous page), sort
+// (newest, oldest or title), creator, from and to (RFC 3339 times or dates; a
+// date in to includes the whole day) and status (registration status).
+func HandleGetRecipes(store database.RecipeStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query, err := parseRecipeListQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		page, err := store.List(query)
+		if errors.Is(err, database.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor, start again from the first page"})
+			return
+		}
+		if err != nil {
+			log.Printf("Error retrieving recipes from database: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error retrieving recipes"})
+			return
+		}
+
+		c.JSON(http.StatusOK, page)
+	}
 }
 
-// HandleGetRecipeByHash handles the GET request to retrieve a single recipe by its hash.
-func HandleGetRecipeByHash(c *gin.Context) {
-	hash := c.Param("hash")
-	if hash == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Recipe hash parameter is missing"})
-		return
+func parseRecipeListQuery(c *gin.Context) (models.RecipeListQuery, error) {
+	query := models.RecipeListQuery{
+		Sort:   c.DefaultQuery("sort", models.RecipeSortNewest),
+		Limit:  defaultRecipePageSize,
+		Cursor: c.Query("cursor"),
+		Status: c.Query("status"),
 	}
 
-	// Pass database.DB
-	recipe, err := database.GetRecipeByHash(database.DB, hash)
-	if err != nil {
-		// Use the specific error type returned by GetRecipeByHash if needed
-		// Assuming GetRecipeByHash returns nil, nil for not found
-		log.Printf("Error retrieving recipe by hash %s: %v", hash, err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error retrieving recipe"})
-		return // Return internal server error for any DB error
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return query, errors.New("limit must be a positive integer")
+		}
+		query.Limit = min(limit, maxRecipePageSize)
+	}
+	switch query.Sort {
+	case models.RecipeSortNewest, models.RecipeSortOldest, models.RecipeSortTitle:
+	default:
+		return query, fmt.Errorf("sort must be %s, %s or %s", models.RecipeSortNewest, models.RecipeSortOldest, models.RecipeSortTitle)
+	}
+	if creator := c.Query("creator"); creator != "" {
+		if !common.IsHexAddress(creator) {
+			return query, errors.New("creator must be an address")
+		}
+		query.Creator = creator
+	}
+	switch query.Status {
+	case "", models.RegistrationPending, models.RegistrationSubmitted, models.RegistrationConfirmed, models.RegistrationFailed:
+	default:
+		return query, errors.New("unknown registration status " + strconv.Quote(query.Status))
 	}
 
-	if recipe == nil { // Check if recipe is nil (indicating not found)
-		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
-		return
+	for _, bound := range []struct {
+		param  string
+		target **time.Time
+	}{{"from", &query.CreatedFrom}, {"to", &query.CreatedBefore}} {
+		raw := c.Query(bound.param)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			day, dayErr := time.Parse(time.DateOnly, raw)
+			if dayErr != nil {
+				return query, fmt.Errorf("%s must be an RFC 3339 time or a YYYY-MM-DD date", bound.param)
+			}
+			t = day
+			if bound.param == "to" {
+				t = day.AddDate(0, 0, 1) // Up to the end of that day
+			}
+		}
+		*bound.target = &t
 	}
+	if query.CreatedFrom != nil && query.CreatedBefore != nil && !query.CreatedFrom.Before(*query.CreatedBefore) {
+		return query, errors.New("from must be before to")
+	}
+	return query, nil
+}
 
-	c.JSON(http.StatusOK, recipe)
+// HandleGetRecipeByHash handles the GET request to retrieve a single recipe by its hash.
+func HandleGetRecipeByHash(store database.RecipeStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hash := c.Param("hash")
+		if hash == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Recipe hash parameter is missing"})
+			return
+		}
+
+		recipe, err := store.GetByHash(hash)
+		if err != nil {
+			// GetByHash returns nil, nil for not found, so any error is a DB error
+			log.Printf("Error retrieving recipe by hash %s: %v", hash, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error retrieving recipe"})
+			return // Return internal server error for any DB error
+		}
+
+		if recipe == nil { // Check if recipe is nil (indicating not found)
+			c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, recipe)
+	}
 }
@@ -0,0 +1,180 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"proofpot-backend/authorship"
+	"proofpot-backend/contenthash"
+	"proofpot-backend/database"
+	"proofpot-backend/models"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRouter(store database.RecipeStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/api/recipes", HandleCreateRecipe(store))
+	r.GET("/api/recipes", HandleGetRecipes(store))
+	r.GET("/api/recipes/:hash", HandleGetRecipeByHash(store))
+	return r
+}
+
+// signedPayload returns a valid create payload, signed by a fresh creator key.
+func signedPayload(t *testing.T, title string) models.RecipeCreatePayload {
+	t.Helper()
+	payload := models.RecipeCreatePayload{
+		Title:       title,
+		Ingredients: "2 eggs\n200g flour\n" + title, // The current scheme does not hash the title
+		Steps:       "Mix.\nFry.",
+		HashScheme:  int(contenthash.CurrentScheme),
+	}
+	hash, err := contenthash.Compute(contenthash.CurrentScheme, payload.Title, payload.Ingredients, payload.Steps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload.ContentHash = hash
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hashBytes, _ := contenthash.Decode(hash)
+	digest, err := authorship.Hash(payload.Title, hashBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature, err := crypto.Sign(digest.Bytes(), key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature[64] += 27
+	payload.CreatorAddress = crypto.PubkeyToAddress(key.PublicKey).Hex()
+	payload.CreatorSignature = hexutil.Encode(signature)
+	return payload
+}
+
+func do(r *gin.Engine, method, path string, body any) *httptest.ResponseRecorder {
+	var buf bytes.Buffer
+	if body != nil {
+		json.NewEncoder(&buf).Encode(body)
+	}
+	req := httptest.NewRequest(method, path, &buf)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateRecipe(t *testing.T) {
+	store := database.NewMemoryStore()
+	r := newTestRouter(store)
+	payload := signedPayload(t, "Pancakes")
+
+	if w := do(r, http.MethodPost, "/api/recipes", payload); w.Code != http.StatusCreated {
+		t.Fatalf("create = %d %s, want 201", w.Code, w.Body)
+	}
+	stored, err := store.GetByHash(payload.ContentHash)
+	if err != nil || stored == nil {
+		t.Fatalf("recipe not stored: %v", err)
+	}
+	if stored.Registration.Status != models.RegistrationPending {
+		t.Errorf("registration status = %q, want pending", stored.Registration.Status)
+	}
+
+	if w := do(r, http.MethodPost, "/api/recipes", payload); w.Code != http.StatusConflict {
+		t.Errorf("duplicate create = %d, want 409", w.Code)
+	}
+}
+
+func TestCreateRecipeRejectsBadPayloads(t *testing.T) {
+	other := signedPayload(t, "Crepes")
+	tests := []struct {
+		name   string
+		modify func(p *models.RecipeCreatePayload)
+		want   int
+	}{
+		{"missing field", func(p *models.RecipeCreatePayload) { p.Steps = "" }, http.StatusBadRequest},
+		{"bad creator address", func(p *models.RecipeCreatePayload) { p.CreatorAddress = "chef" }, http.StatusBadRequest},
+		{"hash of other content", func(p *models.RecipeCreatePayload) { p.ContentHash = other.ContentHash }, http.StatusUnprocessableEntity},
+		{"signed by someone else", func(p *models.RecipeCreatePayload) { p.CreatorAddress = other.CreatorAddress }, http.StatusForbidden},
+		{"malformed signature", func(p *models.RecipeCreatePayload) { p.CreatorSignature = "0x1234" }, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := database.NewMemoryStore()
+			payload := signedPayload(t, "Pancakes")
+			tt.modify(&payload)
+			if w := do(newTestRouter(store), http.MethodPost, "/api/recipes", payload); w.Code != tt.want {
+				t.Errorf("create = %d %s, want %d", w.Code, w.Body, tt.want)
+			}
+			if exists, _ := store.Exists(payload.ContentHash); exists {
+				t.Errorf("rejected recipe was stored")
+			}
+		})
+	}
+}
+
+func TestGetRecipesRejectsBadQueries(t *testing.T) {
+	r := newTestRouter(database.NewMemoryStore())
+	for _, query := range []string{
+		"limit=0",
+		"limit=ten",
+		"sort=rating",
+		"creator=chef",
+		"status=lost",
+		"from=yesterday",
+		"from=2025-02-01&to=2025-01-01",
+		"cursor=not-a-cursor",
+	} {
+		if w := do(r, http.MethodGet, "/api/recipes?"+query, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("GET /api/recipes?%s = %d, want 400", query, w.Code)
+		}
+	}
+}
+
+func TestGetRecipes(t *testing.T) {
+	store := database.NewMemoryStore()
+	r := newTestRouter(store)
+	first, second := signedPayload(t, "Pancakes"), signedPayload(t, "Crepes")
+	for _, p := range []models.RecipeCreatePayload{first, second} {
+		if _, err := store.Create(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := do(r, http.MethodGet, "/api/recipes?limit=1", nil)
+	var page models.RecipePage
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("list = %d %s", w.Code, w.Body)
+	}
+	if len(page.Recipes) != 1 || page.Recipes[0].Title != "Crepes" || page.NextCursor == nil {
+		t.Fatalf("first page = %+v, want the newest recipe and a cursor", page)
+	}
+	w = do(r, http.MethodGet, "/api/recipes?limit=1&cursor="+*page.NextCursor, nil)
+	page = models.RecipePage{}
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("second page = %d %s", w.Code, w.Body)
+	}
+	if len(page.Recipes) != 1 || page.Recipes[0].Title != "Pancakes" || page.NextCursor != nil {
+		t.Errorf("second page = %+v, want the older recipe and no cursor", page)
+	}
+
+	w = do(r, http.MethodGet, "/api/recipes/"+first.ContentHash, nil)
+	var recipe models.Recipe
+	if err := json.Unmarshal(w.Body.Bytes(), &recipe); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("get = %d %s", w.Code, w.Body)
+	}
+	if recipe.Title != "Pancakes" || recipe.CreatorSignature == nil {
+		t.Errorf("get returned %+v", recipe)
+	}
+
+	if w := do(r, http.MethodGet, "/api/recipes/0x"+first.ContentHash[4:]+"00", nil); w.Code != http.StatusNotFound {
+		t.Errorf("get unknown = %d, want 404", w.Code)
+	}
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"proofpot-backend/database"
+	"proofpot-backend/models"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxSearchLength bounds the search text, which is parsed on every request.
+const maxSearchLength = 200
+
+// HandleSearchRecipes handles GET /api/recipes/search?q=. It returns the recipes
+// matching the words of q, best matches first, with highlighted titles and
+// snippets. Pages work like GET /api/recipes: limit and cursor (nextCursor of
+// the previous page, only valid for the same q).
+func HandleSearchRecipes(store database.RecipeStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := models.RecipeSearchQuery{
+			Text:   strings.TrimSpace(c.Query("q")),
+			Limit:  defaultRecipePageSize,
+			Cursor: c.Query("cursor"),
+		}
+		if query.Text == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Search text parameter q is missing"})
+			return
+		}
+		if utf8.RuneCountInString(query.Text) > maxSearchLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Search text is longer than " + strconv.Itoa(maxSearchLength) + " characters"})
+			return
+		}
+		if raw := c.Query("limit"); raw != "" {
+			limit, err := strconv.Atoi(raw)
+			if err != nil || limit < 1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+				return
+			}
+			query.Limit = min(limit, maxRecipePageSize)
+		}
+
+		page, err := store.Search(query)
+		if errors.Is(err, database.ErrInvalidCursor) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor, start again from the first page"})
+			return
+		}
+		if err != nil {
+			log.Printf("Error searching recipes for %q: %v", query.Text, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error searching recipes"})
+			return
+		}
+
+		c.JSON(http.StatusOK, page)
+	}
+}
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"proofpot-backend/database"
+	"proofpot-backend/models"
+	"strings"
+	"testing"
+)
+
+func TestSearchRecipes(t *testing.T) {
+	store := database.NewMemoryStore()
+	r := newTestRouter(store)
+	r.GET("/api/recipes/search", HandleSearchRecipes(store))
+	for _, title := range []string{"Saffron rice", "Pancakes"} {
+		if _, err := store.Create(signedPayload(t, title)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	w := do(r, http.MethodGet, "/api/recipes/search?q=saffron", nil)
+	var page models.RecipeSearchPage
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil || w.Code != http.StatusOK {
+		t.Fatalf("search = %d %s", w.Code, w.Body)
+	}
+	if len(page.Results) != 1 || page.Results[0].TitleHighlight != "<mark>Saffron</mark> rice" {
+		t.Errorf("search results = %+v", page.Results)
+	}
+
+	for _, query := range []string{"", "q=", "q=saffron&limit=0", "q=saffron&cursor=x", "q=" + strings.Repeat("a", maxSearchLength+1)} {
+		if w := do(r, http.MethodGet, "/api/recipes/search?"+query, nil); w.Code != http.StatusBadRequest {
+			t.Errorf("GET /api/recipes/search?%s = %d, want 400", query, w.Code)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"proofpot-backend/blockchain"
+	"proofpot-backend/contenthash"
+	"proofpot-backend/database"
+	"proofpot-backend/models"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleVerifyRecipe handles GET /api/recipes/:hash/verify. It recomputes the content
+// hash of the stored recipe and compares the recipe with what the RecipeRegistry
+// contract recorded for that hash.
+func HandleVerifyRecipe(registry blockchain.RecipeRegistry, store database.RecipeStore, proofs database.BatchProofStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		verifyRecipe(c, registry, store, proofs)
+	}
+}
+
+func verifyRecipe(c *gin.Context, registry blockchain.RecipeRegistry, store database.RecipeStore, proofs database.BatchProofStore) {
+	hash, err := contenthash.Normalize(c.Param("hash"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid content hash: " + err.Error()})
+		return
+	}
+
+	recipe, err := store.GetByHash(hash)
+	if err != nil {
+		log.Printf("Error retrieving recipe by hash %s for verification: %v", hash, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Database error retrieving recipe"})
+		return
+	}
+	if recipe == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+
+	result := models.RecipeVerification{
+		ContentHash:     recipe.ContentHash,
+		HashScheme:      recipe.HashScheme,
+		CreatorAddress:  recipe.CreatorAddress,
+		ContractAddress: registry.Address().Hex(),
+	}
+
+	// --- Recompute the content hash with the scheme the recipe was created with ---
+	scheme, err := contenthash.ParseScheme(recipe.HashScheme)
+	if err == nil {
+		result.RecomputedHash, err = contenthash.Compute(scheme, recipe.Title, recipe.Ingredients, recipe.Steps)
+	}
+	if err != nil {
+		log.Printf("Error recomputing hash for recipe %s: %v", hash, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Unable to recompute recipe content hash"})
+		return
+	}
+	result.ContentHashValid = result.RecomputedHash == recipe.ContentHash
+
+	// --- Read the on-chain record ---
+	hashBytes, _ := contenthash.Decode(recipe.ContentHash) // Validated by Normalize above
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 15*time.Second)
+	defer cancel()
+	record, err := registry.GetRecipeRecord(ctx, hashBytes)
+	if err != nil {
+		log.Printf("Error reading on-chain record for hash %s: %v", hash, err)
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to read recipe from the blockchain"})
+		return
+	}
+
+	if !record.Registered() {
+		// Not registered on its own; it may be part of an anchored batch
+		record, err = verifyBatchInclusion(ctx, registry, proofs, recipe.ContentHash, hashBytes, &result)
+		if err != nil {
+			log.Printf("Error checking batch inclusion for hash %s: %v", hash, err)
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Unable to read recipe from the blockchain"})
+			return
+		}
+		if record == nil {
+			result.Verdict = models.VerdictNotRegistered
+			c.JSON(http.StatusOK, result)
+			return
+		}
+	}
+
+	onChainCreator := record.Creator.Hex()
+	result.OnChainCreator = &onChainCreator
+	result.OnChainTimestamp = &record.Timestamp
+
+	// --- Build the verdict ---
+	if !result.ContentHashValid {
+		result.Reasons = append(result.Reasons, "stored recipe content does not hash to its content hash")
+	}
+	if !strings.EqualFold(onChainCreator, recipe.CreatorAddress) {
+		result.Reasons = append(result.Reasons, "on-chain creator does not match the recipe creator")
+	}
+	if len(result.Reasons) == 0 {
+		result.Verdict = models.VerdictMatch
+	} else {
+		result.Verdict = models.VerdictMismatch
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// verifyBatchInclusion checks the recipe's stored batch proof with the contract. A
+// batch leaf binds the hash to the creator the proof was built for, so a valid
+// proof yields an on-chain record for that creator. It returns nil if the recipe
+// has no proof or the proof does not verify against an anchored root.
+func verifyBatchInclusion(ctx context.Context, registry blockchain.RecipeRegistry, proofs database.BatchProofStore, contentHash string, hashBytes [32]byte, result *models.RecipeVerification) (*blockchain.RecipeRecord, error) {
+	proof, err := proofs.GetBatchProof(contentHash)
+	if err != nil || proof == nil {
+		return nil, err
+	}
+
+	root := common.HexToHash(proof.MerkleRoot)
+	siblings := make([][32]byte, len(proof.Proof))
+	for i, sibling := range proof.Proof {
+		siblings[i] = common.HexToHash(sibling)
+	}
+	creator := common.HexToAddress(proof.CreatorAddress)
+	included, err := registry.VerifyBatchInclusion(ctx, root, hashBytes, creator, siblings)
+	if err != nil || !included {
+		return nil, err
+	}
+	batch, err := registry.GetBatchRecord(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	rootHex := root.Hex()
+	result.BatchRoot = &rootHex
+	return &blockchain.RecipeRecord{Creator: creator, Timestamp: batch.Timestamp}, nil
+}
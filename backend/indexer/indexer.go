@@ -0,0 +1,261 @@
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"proofpot-backend/blockchain"
+	"proofpot-backend/database"
+	"proofpot-backend/models"
+)
+
+// checkpointName identifies this indexer's row in indexer_checkpoints.
+const checkpointName = "recipe_added"
+
+// Config controls the RecipeAdded indexer. Values are read from the environment by LoadConfig.
+type Config struct {
+	StartBlock    uint64        // First block to backfill from when no checkpoint exists (INDEXER_START_BLOCK)
+	Confirmations uint64        // Blocks to wait before indexing, protects against reorgs (INDEXER_CONFIRMATIONS)
+	BatchSize     uint64        // Max blocks per eth_getLogs request (INDEXER_BATCH_SIZE)
+	PollInterval  time.Duration // Delay between polls once caught up (INDEXER_POLL_SECONDS)
+}
+
+const (
+	defaultConfirmations = 12
+	defaultBatchSize     = 2000
+	defaultPollInterval  = 15 * time.Second
+)
+
+// LoadConfig reads the indexer configuration from environment variables.
+// enabled is false when INDEXER_START_BLOCK is not set: without it there is no
+// sane place to start a backfill on a public chain.
+func LoadConfig() (cfg Config, enabled bool) {
+	start := os.Getenv("INDEXER_START_BLOCK")
+	if start == "" {
+		return cfg, false
+	}
+	startBlock, err := strconv.ParseUint(start, 10, 64)
+	if err != nil {
+		log.Printf("Warning: invalid INDEXER_START_BLOCK=%q, indexer disabled", start)
+		return cfg, false
+	}
+	cfg = Config{
+		StartBlock:    startBlock,
+		Confirmations: envUint("INDEXER_CONFIRMATIONS", defaultConfirmations),
+		BatchSize:     envUint("INDEXER_BATCH_SIZE", defaultBatchSize),
+		PollInterval:  time.Duration(envUint("INDEXER_POLL_SECONDS", uint64(defaultPollInterval/time.Second))) * time.Second,
+	}
+	return cfg, true
+}
+
+func envUint(key string, fallback uint64) uint64 {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	v, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, using default %d", key, raw, fallback)
+		return fallback
+	}
+	return v
+}
+
+// Indexer follows RecipeAdded events and mirrors them into the recipe_events table.
+type Indexer struct {
+	db  *sql.DB
+	cfg Config
+}
+
+// New creates an indexer backed by the given database.
+func New(db *sql.DB, cfg Config) *Indexer {
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaultPollInterval
+	}
+	return &Indexer{db: db, cfg: cfg}
+}
+
+// Run backfills from the checkpoint (or StartBlock) and then follows new blocks
+// until ctx is cancelled.
+func (ix *Indexer) Run(ctx context.Context) {
+	log.Printf("Starting RecipeAdded indexer (start block %d, %d confirmations)", ix.cfg.StartBlock, ix.cfg.Confirmations)
+	for {
+		// Nothing to index without a chain connection; wait for it to come back
+		if !blockchain.Connected() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(ix.cfg.PollInterval):
+			}
+			continue
+		}
+
+		caughtUp, err := ix.step(ctx)
+		if err != nil {
+			log.Printf("Indexer error: %v", err)
+		}
+
+		// Keep going without sleeping while backfilling
+		if err == nil && !caughtUp {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(ix.cfg.PollInterval):
+		}
+	}
+}
+
+// step indexes at most one batch of blocks and reports whether the indexer has
+// reached the confirmed head.
+func (ix *Indexer) step(ctx context.Context) (bool, error) {
+	head, err := blockchain.LatestBlockNumber(ctx)
+	if err != nil {
+		return true, err
+	}
+	if head < ix.cfg.Confirmations {
+		return true, nil
+	}
+	safeHead := head - ix.cfg.Confirmations
+
+	cp, err := database.GetCheckpoint(ix.db, checkpointName)
+	if err != nil {
+		return true, err
+	}
+
+	from := ix.cfg.StartBlock
+	if cp != nil {
+		// A reorg deeper than the confirmation depth would change the hash of the
+		// block we checkpointed; rewind and re-index in that case.
+		if cp, err = ix.checkReorg(ctx, cp); err != nil {
+			return true, err
+		}
+	}
+	if cp != nil {
+		from = uint64(cp.BlockNumber) + 1
+	}
+	if from > safeHead {
+		return true, nil
+	}
+
+	to := min(from+ix.cfg.BatchSize-1, safeHead)
+	events, err := blockchain.FilterRecipeAdded(ctx, from, to)
+	if err != nil {
+		return true, err
+	}
+	toHash, err := blockchain.BlockHashAt(ctx, to)
+	if err != nil {
+		return true, err
+	}
+
+	if err := ix.commit(events, database.Checkpoint{BlockNumber: int64(to), BlockHash: toHash.Hex()}); err != nil {
+		return true, err
+	}
+	if len(events) > 0 {
+		log.Printf("Indexer: recorded %d RecipeAdded events in blocks %d-%d", len(events), from, to)
+	}
+	return to == safeHead, nil
+}
+
+// commit stores a batch of events together with the new checkpoint.
+func (ix *Indexer) commit(events []blockchain.RecipeAddedEvent, cp database.Checkpoint) error {
+	tx, err := ix.db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to start indexer transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	for _, ev := range events {
+		if err := database.RecordRecipeEvent(tx, toModel(ev)); err != nil {
+			return err
+		}
+	}
+	if err := database.SaveCheckpoint(tx, checkpointName, cp); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// checkReorg rewinds the checkpoint by the confirmation depth if the checkpointed
+// block is no longer canonical, and returns the checkpoint to continue from. It
+// returns nil if indexing has to start over from StartBlock: when the rewind goes
+// below it, or the checkpoint is below it already (StartBlock was raised).
+func (ix *Indexer) checkReorg(ctx context.Context, cp *database.Checkpoint) (*database.Checkpoint, error) {
+	if cp.BlockNumber < int64(ix.cfg.StartBlock) {
+		log.Printf("Indexer: checkpoint at block %d is below the start block %d, starting over", cp.BlockNumber, ix.cfg.StartBlock)
+		return nil, database.DeleteCheckpoint(ix.db, checkpointName)
+	}
+	hash, err := blockchain.BlockHashAt(ctx, uint64(cp.BlockNumber))
+	if err != nil {
+		return nil, err
+	}
+	if hash.Hex() == cp.BlockHash {
+		return cp, nil
+	}
+
+	rewindTo := max(cp.BlockNumber-int64(max(ix.cfg.Confirmations, 1)), int64(ix.cfg.StartBlock)-1)
+	log.Printf("WARNING: Indexer detected a reorg at block %d, rewinding to block %d", cp.BlockNumber, rewindTo)
+
+	tx, err := ix.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("failed to start reorg transaction: %w", err)
+	}
+	defer tx.Rollback() // No-op once the transaction is committed
+
+	if err := database.DeleteRecipeEventsAfter(tx, rewindTo); err != nil {
+		return nil, err
+	}
+	// Registrations mined in the dropped blocks are not on chain anymore, unless
+	// they are mined again
+	reopened, err := database.ReopenRegistrationsAfter(tx, rewindTo)
+	if err != nil {
+		return nil, err
+	}
+	var rewound *database.Checkpoint
+	if rewindTo < int64(ix.cfg.StartBlock) {
+		err = database.DeleteCheckpoint(tx, checkpointName)
+	} else {
+		h, hashErr := blockchain.BlockHashAt(ctx, uint64(rewindTo))
+		if hashErr != nil {
+			return nil, hashErr
+		}
+		rewound = &database.Checkpoint{BlockNumber: rewindTo, BlockHash: h.Hex()}
+		err = database.SaveCheckpoint(tx, checkpointName, *rewound)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	if reopened > 0 {
+		log.Printf("WARNING: Indexer: %d confirmed registrations were reorged out, their jobs are queued again", reopened)
+	}
+	return rewound, nil
+}
+
+func toModel(ev blockchain.RecipeAddedEvent) models.RecipeEvent {
+	return models.RecipeEvent{
+		ContentHash:    "0x" + hex.EncodeToString(ev.RecipeHash[:]),
+		CreatorAddress: ev.Creator.Hex(),
+		ChainTimestamp: ev.Timestamp,
+		BlockNumber:    int64(ev.BlockNumber),
+		BlockHash:      ev.BlockHash.Hex(),
+		TxHash:         ev.TxHash.Hex(),
+		LogIndex:       int(ev.LogIndex),
+	}
+}
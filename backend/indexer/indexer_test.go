@@ -0,0 +1,270 @@
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"math/big"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"proofpot-backend/blockchain"
+	"proofpot-backend/database"
+	"proofpot-backend/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// testEnv is a simulated chain the blockchain package is configured for and a
+// migrated SQLite database.
+type testEnv struct {
+	chain *blockchain.SimulatedChain
+	db    *sql.DB
+	ctx   context.Context
+}
+
+func newTestEnv(t *testing.T, funded ...common.Address) *testEnv {
+	t.Helper()
+	owner, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := blockchain.NewSimulatedChain(owner, funded...)
+	if err != nil {
+		t.Fatalf("NewSimulatedChain: %v", err)
+	}
+	t.Cleanup(func() { chain.Close() })
+	if err := chain.Use(); err != nil {
+		t.Fatalf("Use: %v", err)
+	}
+
+	db, err := database.Open("sqlite://" + filepath.Join(t.TempDir(), "proofpot.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := database.MigrateUp(db); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+	return &testEnv{chain: chain, db: db, ctx: ctx}
+}
+
+// register stores a recipe with its registration job and registers it on chain
+// (one block per transaction), without telling the database about the transaction.
+func (e *testEnv) register(t *testing.T, label string) (string, *blockchain.Registration, blockchain.SubmittedTx) {
+	t.Helper()
+	hash := crypto.Keccak256Hash([]byte(label)).Hex()
+	creator := common.HexToAddress("0x00000000000000000000000000000000000000c0").Hex()
+	payload := models.RecipeCreatePayload{Title: label, Ingredients: "i", Steps: "s", CreatorAddress: creator, ContentHash: hash}
+	if _, err := database.CreateRecipeWithRegistrationJob(e.db, payload); err != nil {
+		t.Fatal(err)
+	}
+	var sub blockchain.SubmittedTx
+	reg, err := blockchain.RegisterRecipeOnChain(e.ctx, hash, creator, func(s blockchain.SubmittedTx) { sub = s })
+	if err != nil {
+		t.Fatalf("RegisterRecipeOnChain: %v", err)
+	}
+	return hash, reg, sub
+}
+
+func (e *testEnv) status(t *testing.T, hash string) models.Registration {
+	t.Helper()
+	recipe, err := database.GetRecipeByHash(e.db, hash)
+	if err != nil || recipe == nil {
+		t.Fatalf("GetRecipeByHash(%s) = %v, %v", hash, recipe, err)
+	}
+	return recipe.Registration
+}
+
+func (e *testEnv) count(t *testing.T, query string) int {
+	t.Helper()
+	var n int
+	if err := e.db.QueryRow(query).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func (e *testEnv) catchUp(t *testing.T, ix *Indexer) {
+	t.Helper()
+	for i := 0; i < 100; i++ {
+		caughtUp, err := ix.step(e.ctx)
+		if err != nil {
+			t.Fatalf("step: %v", err)
+		}
+		if caughtUp {
+			return
+		}
+	}
+	t.Fatal("indexer did not catch up")
+}
+
+func TestIndexerBackfillsAndResumes(t *testing.T) {
+	e := newTestEnv(t)
+	skipped, _, _ := e.register(t, "before the start block")
+	head, err := blockchain.LatestBlockNumber(e.ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _, _ := e.register(t, "first")
+	second, _, _ := e.register(t, "second")
+
+	// Older versions could leave this checkpoint behind after a reorg
+	if err := database.SaveCheckpoint(e.db, checkpointName, database.Checkpoint{BlockNumber: -1}); err != nil {
+		t.Fatal(err)
+	}
+	cfg := Config{StartBlock: head + 1, BatchSize: 1}
+	e.catchUp(t, New(e.db, cfg))
+
+	for _, hash := range []string{first, second} {
+		if status := e.status(t, hash).Status; status != models.RegistrationConfirmed {
+			t.Errorf("recipe %s is %s after the backfill, want confirmed", hash, status)
+		}
+	}
+	if status := e.status(t, skipped).Status; status != models.RegistrationPending {
+		t.Errorf("recipe registered before the start block is %s, want pending", status)
+	}
+
+	// A restarted indexer continues at the checkpoint
+	third, reg, _ := e.register(t, "third")
+	caughtUp, err := New(e.db, cfg).step(e.ctx)
+	if err != nil || !caughtUp {
+		t.Fatalf("step after restart = %v, %v", caughtUp, err)
+	}
+	if status := e.status(t, third); status.Status != models.RegistrationConfirmed || *status.BlockNumber != int64(reg.BlockNumber) {
+		t.Errorf("recipe registered after the restart = %+v, want confirmed in block %d", status, reg.BlockNumber)
+	}
+	if n := e.count(t, `SELECT COUNT(*) FROM recipe_events`); n != 3 {
+		t.Errorf("%d events recorded, want 3", n)
+	}
+	cp, err := database.GetCheckpoint(e.db, checkpointName)
+	if err != nil || cp == nil || cp.BlockNumber != int64(reg.BlockNumber) {
+		t.Errorf("checkpoint = %+v, %v, want block %d", cp, err, reg.BlockNumber)
+	}
+}
+
+func TestIndexerIgnoresHashRegisteredForAnotherCreator(t *testing.T) {
+	e := newTestEnv(t)
+	hash := crypto.Keccak256Hash([]byte("front-run")).Hex()
+	creator := common.HexToAddress("0x00000000000000000000000000000000000000c0").Hex()
+	payload := models.RecipeCreatePayload{Title: "front-run", Ingredients: "i", Steps: "s", CreatorAddress: creator, ContentHash: hash}
+	if _, err := database.CreateRecipeWithRegistrationJob(e.db, payload); err != nil {
+		t.Fatal(err)
+	}
+
+	// Someone else gets the hash on chain first
+	attacker := common.HexToAddress("0x00000000000000000000000000000000000000a7").Hex()
+	if _, err := blockchain.RegisterRecipeOnChain(e.ctx, hash, attacker, nil); err != nil {
+		t.Fatalf("RegisterRecipeOnChain: %v", err)
+	}
+	e.catchUp(t, New(e.db, Config{}))
+
+	if n := e.count(t, `SELECT COUNT(*) FROM recipe_events`); n != 1 {
+		t.Errorf("%d events recorded, want 1", n)
+	}
+	if status := e.status(t, hash); status.Status != models.RegistrationPending || status.TxHash != nil {
+		t.Errorf("recipe registered for another creator = %+v, want pending without a transaction", status)
+	}
+	if n := e.count(t, `SELECT COUNT(*) FROM registration_jobs WHERE status = 'pending'`); n != 1 {
+		t.Errorf("%d pending jobs, want the recipe's job left alone", n)
+	}
+}
+
+func TestIndexerReorgBelowConfirmationDepth(t *testing.T) {
+	other, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := newTestEnv(t, crypto.PubkeyToAddress(other.PublicKey))
+	forkPoint, err := e.chain.Backend.Client().HeaderByNumber(e.ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The worker registers a recipe and records the mined transaction
+	hash, reg, sub := e.register(t, "reorged")
+	jobs, err := database.ClaimRegistrationJobs(e.db, 1, time.Minute)
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("ClaimRegistrationJobs = %v, %v", jobs, err)
+	}
+	if err := database.RecordRegistrationSubmission(e.db, jobs[0].RecipeID, sub.Hash, sub.From, sub.Nonce, sub.GasFeeCap.String(), sub.GasTipCap.String(), ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := database.ConfirmRegistrationJob(e.db, jobs[0].ID, jobs[0].RecipeID, reg.TxHash, int64(reg.BlockNumber), reg.BlockTime); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 6; i++ {
+		e.chain.Backend.Commit()
+	}
+	ix := New(e.db, Config{Confirmations: 5})
+	e.catchUp(t, ix)
+	if n := e.count(t, `SELECT COUNT(*) FROM recipe_events`); n != 1 {
+		t.Fatalf("%d events recorded before the reorg, want 1", n)
+	}
+
+	// Replace every block after the deployment with a longer chain that starts
+	// with a different transaction
+	if err := e.chain.Backend.Fork(forkPoint.Hash()); err != nil {
+		t.Fatalf("Fork: %v", err)
+	}
+	client := e.chain.Backend.Client()
+	chainID, _ := client.ChainID(e.ctx)
+	transfer, err := types.SignNewTx(other, types.LatestSignerForChainID(chainID), &types.LegacyTx{
+		To: &common.Address{0x01}, Value: big.NewInt(1), Gas: 21000, GasPrice: big.NewInt(100e9),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.SendTransaction(e.ctx, transfer); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 10; i++ {
+		e.chain.Backend.Commit()
+	}
+
+	// The checkpoint is less than the confirmation depth above the start block,
+	// so the indexer starts over instead of rewinding to a negative block
+	cp, err := database.GetCheckpoint(e.db, checkpointName)
+	if err != nil || cp == nil {
+		t.Fatalf("GetCheckpoint = %v, %v", cp, err)
+	}
+	if cp, err = ix.checkReorg(e.ctx, cp); err != nil || cp != nil {
+		t.Fatalf("checkReorg = %+v, %v, want no checkpoint", cp, err)
+	}
+	if cp, _ := database.GetCheckpoint(e.db, checkpointName); cp != nil {
+		t.Errorf("checkpoint %+v kept after rewinding below the start block", cp)
+	}
+	if n := e.count(t, `SELECT COUNT(*) FROM recipe_events`); n != 0 {
+		t.Errorf("%d events kept after the reorg", n)
+	}
+	if status := e.status(t, hash); status.Status != models.RegistrationSubmitted || status.BlockNumber != nil || *status.TxHash != reg.TxHash {
+		t.Errorf("reorged recipe = %+v, want submitted with its transaction", status)
+	}
+	if n := e.count(t, `SELECT COUNT(*) FROM registration_jobs WHERE status = 'pending' AND attempts = 0`); n != 1 {
+		t.Errorf("reorged registration job not queued again")
+	}
+	if n := e.count(t, `SELECT COUNT(*) FROM registration_transactions WHERE status = 'pending'`); n != 1 {
+		t.Errorf("reorged transaction not pending again")
+	}
+
+	// Indexing goes on from the start block on the new chain
+	e.catchUp(t, ix)
+	cp, err = database.GetCheckpoint(e.db, checkpointName)
+	if err != nil || cp == nil {
+		t.Fatalf("GetCheckpoint after the reorg = %v, %v", cp, err)
+	}
+	canonical, err := blockchain.BlockHashAt(e.ctx, uint64(cp.BlockNumber))
+	if err != nil || canonical.Hex() != cp.BlockHash {
+		t.Errorf("checkpoint %+v is not on the canonical chain (%s, %v)", cp, canonical.Hex(), err)
+	}
+	// The node may have mined the dropped transaction again on the new chain
+	events := e.count(t, `SELECT COUNT(*) FROM recipe_events`)
+	if status := e.status(t, hash).Status; (events == 1) != (status == models.RegistrationConfirmed) {
+		t.Errorf("recipe is %s with %d events on the new chain", status, events)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"net/http"
 	"os"
@@ -9,6 +10,9 @@ import (
 	"proofpot-backend/blockchain" // Import the blockchain package
 	"proofpot-backend/database"   // Import the database package
 	"proofpot-backend/handlers"   // Import the handlers package
+	"proofpot-backend/indexer"    // Import the RecipeAdded indexer package
+	"proofpot-backend/queue"      // Import the registration queue package
+	"proofpot-backend/reconcile"  // Import the DB/contract reconciliation package
 	"strings"
 	"syscall"
 	"time"
@@ -24,16 +28,61 @@ func main() {
 		log.Println("Note: Error loading .env file, using system environment variables.")
 	}
 
+	// `migrate` manages the schema itself, so it runs before InitDB migrates or checks it
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		database.Connect()
+		code := runMigrate(os.Args[2:])
+		database.CloseDB()
+		os.Exit(code)
+	}
+
 	// Initialize Database
 	database.InitDB()
 	// Ensure DB connection is closed when main function exits
 	defer database.CloseDB()
 
-	// Initialize Blockchain Connection
-	if err := blockchain.InitBlockchain(); err != nil {
-		log.Fatalf("Failed to initialize blockchain connection: %v", err)
+	// Initialize Blockchain Connection. Reads never touch the chain, so unless
+	// CHAIN_REQUIRED=true the server also starts without it: registrations stay
+	// queued and the connection is retried in the background. Configuration
+	// errors and one-shot subcommands still fail fast.
+	chainErr := blockchain.InitBlockchain()
+	if chainErr != nil {
+		if len(os.Args) > 1 || os.Getenv("CHAIN_REQUIRED") == "true" || errors.Is(chainErr, blockchain.ErrChainConfig) {
+			log.Fatalf("Failed to initialize blockchain connection: %v", chainErr)
+		}
+		log.Printf("WARNING: Blockchain unavailable, starting in degraded mode: %v", chainErr)
 	}
 
+	// One-shot subcommands (e.g. `reconcile`) run instead of the server
+	if len(os.Args) > 1 {
+		code := runCommand(os.Args[1], os.Args[2:])
+		database.CloseDB()
+		os.Exit(code)
+	}
+
+	// Keep the chain connection alive (reconnects with backoff, health probes)
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	go blockchain.KeepConnected(backgroundCtx, blockchain.LoadConnectionConfig())
+
+	// Start the on-chain registration workers
+	workers := queue.NewPool(database.DB, queue.LoadConfig())
+	workers.Start(backgroundCtx)
+
+	// Start the RecipeAdded event indexer (only when INDEXER_START_BLOCK is configured)
+	if indexerCfg, enabled := indexer.LoadConfig(); enabled {
+		go indexer.New(database.DB, indexerCfg).Run(backgroundCtx)
+	} else {
+		log.Println("INDEXER_START_BLOCK not set, RecipeAdded indexer disabled")
+	}
+
+	// Start periodic reconciliation between the database and the contract
+	if reconcileCfg := reconcile.LoadConfig(); reconcileCfg.Interval > 0 {
+		go reconcile.New(database.DB, reconcileCfg).Run(backgroundCtx)
+	}
+
+	// Handlers get their storage injected, so they can be tested with database.MemoryStore
+	store := database.NewSQLStore(database.DB)
+
 	r := gin.Default()
 
 	// --- CORS Middleware ---
@@ -59,12 +108,18 @@ func main() {
 		api.GET("/ping", func(c *gin.Context) {
 			c.JSON(http.StatusOK, gin.H{"message": "pong"})
 		})
+		// Database and chain connectivity
+		api.GET("/health", handlers.HandleHealth(store))
 
 		// Recipe Routes
-		api.POST("/recipes", handlers.HandleCreateRecipe)
+		api.POST("/recipes", handlers.HandleCreateRecipe(store))
 		// --- TODO: Add GET routes here later (Step 4.1, 4.2) ---
-		api.GET("/recipes", handlers.HandleGetRecipes)
-		api.GET("/recipes/:hash", handlers.HandleGetRecipeByHash)
+		api.GET("/recipes", handlers.HandleGetRecipes(store))
+		api.GET("/recipes/search", handlers.HandleSearchRecipes(store))
+		api.GET("/recipes/:hash", handlers.HandleGetRecipeByHash(store))
+		api.GET("/recipes/:hash/verify", handlers.HandleVerifyRecipe(blockchain.Registry(), store, store))
+		api.GET("/recipes/:hash/proof", handlers.HandleGetRecipeProof(store, store))
+		api.GET("/recipes/:hash/certificate", handlers.HandleGetRecipeCertificate(store, store))
 	}
 
 	// Run the server in a goroutine so it doesn't block
@@ -97,5 +152,11 @@ func main() {
 		log.Fatal("Server forced to shutdown:", err)
 	}
 
+	// Stop claiming new registration jobs and give in-flight attempts the shutdown
+	// grace period to be mined. Unfinished jobs stay in the outbox and resume their
+	// broadcast transactions on the next start.
+	stopBackground()
+	workers.Wait()
+
 	log.Println("Server exiting")
 }
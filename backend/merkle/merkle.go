@@ -0,0 +1,89 @@
+package merkle
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Leaf returns the Merkle leaf for a recipe: keccak256(contentHash ++ creator),
+// matching abi.encodePacked(bytes32, address) in RecipeRegistry.verifyBatchInclusion.
+// Leaves are 52 bytes of preimage and inner nodes 64, so a leaf can never be
+// passed off as an inner node.
+func Leaf(contentHash [32]byte, creator common.Address) common.Hash {
+	return crypto.Keccak256Hash(contentHash[:], creator.Bytes())
+}
+
+// hashPair hashes two nodes in ascending order (sorted-pair hashing, as in
+// OpenZeppelin's MerkleProof), so proofs do not need left/right flags.
+func hashPair(a, b common.Hash) common.Hash {
+	if bytes.Compare(a[:], b[:]) > 0 {
+		a, b = b, a
+	}
+	return crypto.Keccak256Hash(a[:], b[:])
+}
+
+// Tree is a binary Merkle tree over a fixed list of leaves. A node without a
+// sibling is carried up to the next level unchanged.
+type Tree struct {
+	levels [][]common.Hash // levels[0] are the leaves, the last level is the root
+}
+
+// Build constructs the tree for leaves, in the given order.
+func Build(leaves []common.Hash) (*Tree, error) {
+	if len(leaves) == 0 {
+		return nil, fmt.Errorf("merkle tree needs at least one leaf")
+	}
+	level := append([]common.Hash(nil), leaves...)
+	tree := &Tree{levels: [][]common.Hash{level}}
+	for len(level) > 1 {
+		next := make([]common.Hash, 0, (len(level)+1)/2)
+		for i := 0; i < len(level); i += 2 {
+			if i+1 == len(level) {
+				next = append(next, level[i])
+			} else {
+				next = append(next, hashPair(level[i], level[i+1]))
+			}
+		}
+		tree.levels = append(tree.levels, next)
+		level = next
+	}
+	return tree, nil
+}
+
+// Root returns the Merkle root.
+func (t *Tree) Root() common.Hash {
+	return t.levels[len(t.levels)-1][0]
+}
+
+// Len returns the number of leaves.
+func (t *Tree) Len() int {
+	return len(t.levels[0])
+}
+
+// Proof returns the sibling hashes from leaf index up to the root.
+func (t *Tree) Proof(index int) ([]common.Hash, error) {
+	if index < 0 || index >= t.Len() {
+		return nil, fmt.Errorf("leaf index %d out of range (%d leaves)", index, t.Len())
+	}
+	proof := []common.Hash{}
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof = append(proof, level[sibling])
+		}
+		index /= 2
+	}
+	return proof, nil
+}
+
+// Verify reports whether proof shows that leaf is part of the tree with the given root.
+func Verify(root, leaf common.Hash, proof []common.Hash) bool {
+	node := leaf
+	for _, sibling := range proof {
+		node = hashPair(node, sibling)
+	}
+	return node == root
+}
@@ -0,0 +1,67 @@
+package merkle
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func testLeaves(n int) []common.Hash {
+	leaves := make([]common.Hash, n)
+	for i := range leaves {
+		leaves[i] = Leaf(crypto.Keccak256Hash([]byte(fmt.Sprintf("recipe %d", i))), common.Address{byte(i + 1)})
+	}
+	return leaves
+}
+
+func TestProofsVerify(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 4, 5, 8, 13} {
+		leaves := testLeaves(n)
+		tree, err := Build(leaves)
+		if err != nil {
+			t.Fatalf("Build(%d): %v", n, err)
+		}
+		for i, leaf := range leaves {
+			proof, err := tree.Proof(i)
+			if err != nil {
+				t.Fatalf("Proof(%d) of %d: %v", i, n, err)
+			}
+			if !Verify(tree.Root(), leaf, proof) {
+				t.Errorf("%d leaves: proof for leaf %d does not verify", n, i)
+			}
+			// A proof only works for its own leaf
+			if n > 1 && Verify(tree.Root(), leaves[(i+1)%n], proof) {
+				t.Errorf("%d leaves: proof for leaf %d verifies another leaf", n, i)
+			}
+		}
+	}
+}
+
+func TestSingleLeafRootIsLeaf(t *testing.T) {
+	leaves := testLeaves(1)
+	tree, err := Build(leaves)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.Root() != leaves[0] {
+		t.Errorf("root = %s, want the leaf %s", tree.Root().Hex(), leaves[0].Hex())
+	}
+	if proof, _ := tree.Proof(0); len(proof) != 0 {
+		t.Errorf("proof = %v, want empty", proof)
+	}
+}
+
+func TestLeafBindsCreator(t *testing.T) {
+	hash := crypto.Keccak256Hash([]byte("soup"))
+	if Leaf(hash, common.Address{1}) == Leaf(hash, common.Address{2}) {
+		t.Errorf("leaves for different creators are equal")
+	}
+}
+
+func TestBuildRejectsEmpty(t *testing.T) {
+	if _, err := Build(nil); err == nil {
+		t.Errorf("Build(nil) succeeded")
+	}
+}
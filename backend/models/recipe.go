@@ -4,24 +4,92 @@ import "time"
 
 // Recipe represents the structure of a recipe in the database and API.
 type Recipe struct {
-	ID             int       `json:"id"` // Use 'int' for SERIAL, will be populated by DB
-	Title          string    `json:"title" binding:"required"`
-	Ingredients    string    `json:"ingredients" binding:"required"`
-	Steps          string    `json:"steps" binding:"required"`
-	CreatorAddress string    `json:"creatorAddress" binding:"required"` // Matches frontend/contract terminology
-	ContentHash    string    `json:"contentHash" binding:"required"`
-	ImageURL       *string   `json:"imageUrl,omitempty"` // Added field (pointer to allow null)
-	CreatedAt      time.Time `json:"createdAt"`          // Populated by DB
+	ID             int          `json:"id"` // Use 'int' for SERIAL, will be populated by DB
+	Title          string       `json:"title" binding:"required"`
+	Ingredients    string       `json:"ingredients" binding:"required"`
+	Steps          string       `json:"steps" binding:"required"`
+	CreatorAddress string       `json:"creatorAddress" binding:"required"` // Matches frontend/contract terminology
+	ContentHash    string       `json:"contentHash" binding:"required"`
+	HashScheme     int          `json:"hashScheme"`         // contenthash.Scheme the hash was computed with
+	ImageURL       *string      `json:"imageUrl,omitempty"` // Added field (pointer to allow null)
+	CreatedAt      time.Time    `json:"createdAt"`          // Populated by DB
+	Registration   Registration `json:"registration"`       // On-chain registration state
+	// Creator's EIP-712 signature, nil for recipes created before signatures were required
+	CreatorSignature *string `json:"creatorSignature,omitempty"`
 }
 
 // RecipeListItem represents the data structure for a recipe in a list view
 type RecipeListItem struct {
-	ID             int       `json:"id"`
-	Title          string    `json:"title"`
-	CreatorAddress string    `json:"creatorAddress"`
-	ContentHash    string    `json:"contentHash"`
-	ImageURL       *string   `json:"imageUrl,omitempty"` // Added field (pointer to allow null)
-	CreatedAt      time.Time `json:"createdAt"`
+	ID             int          `json:"id"`
+	Title          string       `json:"title"`
+	CreatorAddress string       `json:"creatorAddress"`
+	ContentHash    string       `json:"contentHash"`
+	HashScheme     int          `json:"hashScheme"`
+	ImageURL       *string      `json:"imageUrl,omitempty"` // Added field (pointer to allow null)
+	CreatedAt      time.Time    `json:"createdAt"`
+	Registration   Registration `json:"registration"`
+}
+
+// Sort orders of GET /api/recipes.
+const (
+	RecipeSortNewest = "newest" // Most recently created first (default)
+	RecipeSortOldest = "oldest" // Least recently created first
+	RecipeSortTitle  = "title"  // Alphabetically by title
+)
+
+// RecipeListQuery selects one page of GET /api/recipes.
+type RecipeListQuery struct {
+	Sort          string     // One of the RecipeSort constants
+	Limit         int        // Page size
+	Cursor        string     // NextCursor of the previous page, empty for the first page
+	Creator       string     // Creator address, matched case-insensitively; empty for all
+	CreatedFrom   *time.Time // Inclusive lower bound of CreatedAt
+	CreatedBefore *time.Time // Exclusive upper bound of CreatedAt
+	Status        string     // Registration status; empty for all
+}
+
+// RecipePage is the response envelope of GET /api/recipes.
+type RecipePage struct {
+	Recipes    []RecipeListItem `json:"recipes"`
+	NextCursor *string          `json:"nextCursor"` // Null on the last page
+}
+
+// RecipeSearchQuery selects one page of GET /api/recipes/search.
+type RecipeSearchQuery struct {
+	Text   string // Search terms as typed by the user
+	Limit  int    // Page size
+	Cursor string // NextCursor of the previous page, empty for the first page
+}
+
+// RecipeSearchResult is one match of a recipe search. The highlights are HTML:
+// the recipe text is escaped and matching words are wrapped in <mark> tags.
+type RecipeSearchResult struct {
+	RecipeListItem
+	Rank           float64 `json:"rank"`           // Higher is better; only comparable within one search
+	TitleHighlight string  `json:"titleHighlight"` // The whole title
+	Snippet        string  `json:"snippet"`        // Best matching part of the ingredients and steps
+}
+
+// RecipeSearchPage is the response envelope of GET /api/recipes/search, best matches first.
+type RecipeSearchPage struct {
+	Results    []RecipeSearchResult `json:"results"`
+	NextCursor *string              `json:"nextCursor"` // Null on the last page
+}
+
+// Registration states of a recipe hash on the RecipeRegistry contract.
+const (
+	RegistrationPending   = "pending"   // Queued, no transaction broadcast yet
+	RegistrationSubmitted = "submitted" // Transaction broadcast, waiting to be mined
+	RegistrationConfirmed = "confirmed" // Transaction mined successfully
+	RegistrationFailed    = "failed"    // Registration gave up, see the job's last error
+)
+
+// Registration describes whether and where a recipe hash was anchored on chain.
+type Registration struct {
+	Status      string     `json:"status"`
+	TxHash      *string    `json:"txHash,omitempty"`
+	BlockNumber *int64     `json:"blockNumber,omitempty"`
+	ConfirmedAt *time.Time `json:"confirmedAt,omitempty"`
 }
 
 // RecipeCreatePayload defines the structure expected for creating a new recipe via the API.
@@ -32,7 +100,17 @@ type RecipeCreatePayload struct {
 	Steps          string `json:"steps" binding:"required"`
 	CreatorAddress string `json:"creatorAddress" binding:"required"`
 	ContentHash    string `json:"contentHash" binding:"required"`
+	HashScheme     int    `json:"hashScheme"` // Optional, defaults to contenthash.CurrentScheme
 	ImageURL       string `json:"imageUrl"`
+	// EIP-712 signature of the title and content hash by CreatorAddress (see the authorship package)
+	CreatorSignature string `json:"creatorSignature" binding:"required"`
+}
+
+// RecipeUpdate lists the recipe fields that can change after creation. Title and
+// content are covered by the content hash and the creator's signature, so they
+// cannot. Nil fields are left unchanged; an empty ImageURL removes the image.
+type RecipeUpdate struct {
+	ImageURL *string `json:"imageUrl"`
 }
 
 // RecipeCreateResponse defines the structure returned after successfully creating a recipe.
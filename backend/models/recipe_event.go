@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// RecipeEvent is a RecipeAdded event observed on chain by the indexer.
+type RecipeEvent struct {
+	ID             int       `json:"id"`
+	ContentHash    string    `json:"contentHash"`
+	CreatorAddress string    `json:"creatorAddress"`
+	ChainTimestamp time.Time `json:"chainTimestamp"`
+	BlockNumber    int64     `json:"blockNumber"`
+	BlockHash      string    `json:"blockHash"`
+	TxHash         string    `json:"txHash"`
+	LogIndex       int       `json:"logIndex"`
+	RecipeID       *int      `json:"recipeId,omitempty"` // Nil when the hash is not in our database
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// RegistrationBatch anchors the Merkle root of several recipe hashes with one
+// anchorBatch transaction. Batches move through the same statuses as jobs.
+type RegistrationBatch struct {
+	ID            int        `json:"id"`
+	MerkleRoot    string     `json:"merkleRoot"`
+	LeafCount     int        `json:"leafCount"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"nextAttemptAt"`
+	LockedUntil   *time.Time `json:"lockedUntil,omitempty"`
+	LastError     *string    `json:"lastError,omitempty"`
+}
+
+// BatchLeaf is one recipe's position in a batch and its inclusion proof.
+type BatchLeaf struct {
+	RecipeID  int
+	LeafIndex int
+	Leaf      string
+	Proof     []string // Sibling hashes from the leaf up to the root
+}
+
+// RecipeBatchProof is returned by GET /api/recipes/:hash/proof. Anyone can check it
+// by hashing Leaf with each Proof entry (smaller hash first) and comparing the
+// result with MerkleRoot, or by calling verifyBatchInclusion on the contract.
+type RecipeBatchProof struct {
+	ContentHash     string     `json:"contentHash"`
+	CreatorAddress  string     `json:"creatorAddress"`
+	Leaf            string     `json:"leaf"` // keccak256(contentHash ++ creatorAddress)
+	LeafIndex       int        `json:"leafIndex"`
+	Proof           []string   `json:"proof"`
+	MerkleRoot      string     `json:"merkleRoot"`
+	LeafCount       int        `json:"leafCount"`
+	BatchStatus     string     `json:"batchStatus"`
+	TxHash          *string    `json:"txHash,omitempty"`
+	BlockNumber     *int64     `json:"blockNumber,omitempty"`
+	AnchoredAt      *time.Time `json:"anchoredAt,omitempty"`
+	ContractAddress string     `json:"contractAddress,omitempty"`
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Registration job statuses as stored in the registration_jobs table.
+const (
+	JobStatusPending    = "pending"    // Waiting for a worker (first attempt or retry)
+	JobStatusProcessing = "processing" // Claimed by a worker, lease held until LockedUntil
+	JobStatusBatched    = "batched"    // Part of a registration batch that has not been anchored yet
+	JobStatusDone       = "done"       // Recipe hash anchored on chain
+	JobStatusFailed     = "failed"     // Gave up after the configured max attempts, see LastError
+)
+
+// RegistrationJob is a durable request to register a recipe hash on chain.
+type RegistrationJob struct {
+	ID             int        `json:"id"`
+	RecipeID       int        `json:"recipeId"`
+	ContentHash    string     `json:"contentHash"`
+	CreatorAddress string     `json:"creatorAddress"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `json:"nextAttemptAt"`
+	LockedUntil    *time.Time `json:"lockedUntil,omitempty"`
+	LastError      *string    `json:"lastError,omitempty"`
+}
+
+// RegistrationTx is a broadcast registration transaction of a recipe, or anchorBatch
+// transaction of a batch, including fee-bumped replacements, which share the nonce
+// of the transaction they replace.
+type RegistrationTx struct {
+	TxHash      string  `json:"txHash"`
+	FromAddress string  `json:"fromAddress"`
+	Nonce       uint64  `json:"nonce"`
+	GasFeeCap   string  `json:"gasFeeCap"` // Wei, as a decimal string
+	GasTipCap   string  `json:"gasTipCap"` // Wei, as a decimal string
+	Replaces    *string `json:"replaces,omitempty"`
+}
@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// Verification verdicts returned by GET /api/recipes/:hash/verify.
+const (
+	VerdictMatch         = "match"          // Content hash recomputes and the chain records the same creator
+	VerdictMismatch      = "mismatch"       // Content or creator disagrees with the chain, see Reasons
+	VerdictNotRegistered = "not-registered" // The contract has no record of the hash, directly or in an anchored batch
+)
+
+// RecipeVerification is the result of checking a stored recipe against the RecipeRegistry contract.
+type RecipeVerification struct {
+	ContentHash      string     `json:"contentHash"`
+	Verdict          string     `json:"verdict"`
+	Reasons          []string   `json:"reasons,omitempty"`
+	HashScheme       int        `json:"hashScheme"`
+	RecomputedHash   string     `json:"recomputedHash"`
+	ContentHashValid bool       `json:"contentHashValid"`
+	CreatorAddress   string     `json:"creatorAddress"`
+	OnChainCreator   *string    `json:"onChainCreator,omitempty"`
+	OnChainTimestamp *time.Time `json:"onChainTimestamp,omitempty"`
+	BatchRoot        *string    `json:"batchRoot,omitempty"` // Set when the recipe was anchored as part of a batch
+	ContractAddress  string     `json:"contractAddress"`
+}
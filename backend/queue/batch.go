@@ -0,0 +1,171 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"proofpot-backend/blockchain"
+	"proofpot-backend/contenthash"
+	"proofpot-backend/database"
+	"proofpot-backend/merkle"
+	"proofpot-backend/models"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// runBatches is the worker loop for ModeBatch. Each round first collects due jobs
+// into a new batch (if the window has passed or enough jobs are waiting), then
+// anchors one pending batch.
+func (p *Pool) runBatches(ctx context.Context, workerID int) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// While the chain is unreachable, jobs stay queued instead of burning attempts
+		if !blockchain.Connected() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.cfg.PollInterval):
+			}
+			continue
+		}
+
+		batch, err := database.CreateRegistrationBatch(p.db, p.cfg.BatchMaxSize, p.cfg.BatchWindow, buildBatch)
+		if err != nil {
+			log.Printf("Worker %d: could not create registration batch: %v", workerID, err)
+		} else if batch != nil {
+			log.Printf("Worker %d: created batch %d with %d recipes (root %s)", workerID, batch.ID, batch.LeafCount, batch.MerkleRoot)
+		}
+
+		batch, err = database.ClaimRegistrationBatch(p.db, p.cfg.AttemptTimeout+time.Minute)
+		if err != nil || batch == nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.cfg.PollInterval):
+			}
+			continue
+		}
+		p.processBatch(ctx, workerID, *batch)
+	}
+}
+
+// buildBatch computes the Merkle tree over the jobs, in job order. Each leaf binds
+// the content hash to its creator (see merkle.Leaf).
+func buildBatch(jobs []models.RegistrationJob) (string, []models.BatchLeaf, error) {
+	leaves := make([]common.Hash, len(jobs))
+	for i, job := range jobs {
+		hash, err := contenthash.Decode(job.ContentHash)
+		if err != nil {
+			return "", nil, fmt.Errorf("job %d: %w", job.ID, err)
+		}
+		if !common.IsHexAddress(job.CreatorAddress) {
+			return "", nil, fmt.Errorf("job %d: invalid creator address %q", job.ID, job.CreatorAddress)
+		}
+		leaves[i] = merkle.Leaf(hash, common.HexToAddress(job.CreatorAddress))
+	}
+
+	tree, err := merkle.Build(leaves)
+	if err != nil {
+		return "", nil, err
+	}
+	out := make([]models.BatchLeaf, len(jobs))
+	for i, job := range jobs {
+		proof, err := tree.Proof(i)
+		if err != nil {
+			return "", nil, err
+		}
+		hexProof := make([]string, len(proof))
+		for j, sibling := range proof {
+			hexProof[j] = sibling.Hex()
+		}
+		out[i] = models.BatchLeaf{RecipeID: job.RecipeID, LeafIndex: i, Leaf: leaves[i].Hex(), Proof: hexProof}
+	}
+	return tree.Root().Hex(), out, nil
+}
+
+func (p *Pool) processBatch(ctx context.Context, workerID int, batch models.RegistrationBatch) {
+	log.Printf("Worker %d: anchoring batch %d with %d recipes (attempt %d/%d)", workerID, batch.ID, batch.LeafCount, batch.Attempts, p.cfg.MaxAttempts)
+	root := common.HexToHash(batch.MerkleRoot)
+
+	// A previous attempt may have broadcast a transaction that can still be mined;
+	// continue with it instead of sending a second anchorBatch
+	previous, err := database.GetPendingBatchTxs(p.db, batch.ID)
+	if err != nil {
+		p.retryBatch(workerID, batch, err)
+		return
+	}
+
+	attemptCtx, cancel := p.attemptContext(ctx)
+	reg, err := blockchain.ResumeBatchAnchor(attemptCtx, root, batch.LeafCount, submittedTxs(previous), func(sub blockchain.SubmittedTx) {
+		// Called for the original transaction and for every fee-bumped replacement
+		if err := database.MarkBatchSubmitted(p.db, batch.ID, sub.Hash, sub.From, sub.Nonce, sub.GasFeeCap.String(), sub.GasTipCap.String(), sub.Replaces); err != nil {
+			log.Printf("ERROR: Worker %d: could not record tx %s of batch %d: %v", workerID, sub.Hash, batch.ID, err)
+		}
+	})
+	cancel()
+
+	if err == nil {
+		log.Printf("Worker %d: anchored batch %d on chain (block %d)", workerID, batch.ID, reg.BlockNumber)
+		if err := database.ConfirmRegistrationBatch(p.db, batch.ID, reg.TxHash, int64(reg.BlockNumber), reg.BlockTime); err != nil {
+			log.Printf("ERROR: Worker %d: batch %d was mined in tx %s but could not be marked anchored, it is claimed again when its lease expires: %v", workerID, batch.ID, reg.TxHash, err)
+		}
+		return
+	}
+
+	// An earlier attempt may have been mined after it timed out
+	if errors.Is(err, blockchain.ErrBatchAlreadyAnchored) && p.confirmBatchFromChain(batch, root) {
+		log.Printf("Worker %d: batch %d root was already anchored on chain", workerID, batch.ID)
+		return
+	}
+
+	// Shutting down: hand the batch back right away, without counting the attempt.
+	// Its recorded transaction is resumed on the next claim.
+	if ctx.Err() != nil && errors.Is(err, context.Canceled) {
+		log.Printf("Worker %d: anchoring batch %d interrupted by shutdown, it is retried on the next start", workerID, batch.ID)
+		if err := database.ReleaseRegistrationBatch(p.db, batch.ID, err.Error()); err != nil {
+			log.Printf("ERROR: Worker %d: could not release batch %d, it is claimed again when its lease expires: %v", workerID, batch.ID, err)
+		}
+		return
+	}
+
+	if !blockchain.IsRetryable(err) || batch.Attempts >= p.cfg.MaxAttempts {
+		log.Printf("ERROR: Worker %d: anchoring batch %d failed permanently after %d attempts: %v", workerID, batch.ID, batch.Attempts, err)
+		if err := database.FailRegistrationBatch(p.db, batch.ID, err.Error()); err != nil {
+			log.Printf("ERROR: Worker %d: could not mark batch %d as failed, it is claimed again when its lease expires: %v", workerID, batch.ID, err)
+		}
+		return
+	}
+
+	p.retryBatch(workerID, batch, err)
+}
+
+// retryBatch reschedules the batch with exponential backoff.
+func (p *Pool) retryBatch(workerID int, batch models.RegistrationBatch, cause error) {
+	delay := p.backoff(batch.Attempts)
+	log.Printf("Worker %d: anchoring batch %d failed, retrying in %s: %v", workerID, batch.ID, delay, cause)
+	if err := database.RetryRegistrationBatch(p.db, batch.ID, time.Now().Add(delay), cause.Error()); err != nil {
+		log.Printf("ERROR: Worker %d: could not reschedule batch %d, it is claimed again when its lease expires: %v", workerID, batch.ID, err)
+	}
+}
+
+// confirmBatchFromChain completes the batch if its root is already anchored on
+// chain. It reports whether the batch was completed.
+func (p *Pool) confirmBatchFromChain(batch models.RegistrationBatch, root common.Hash) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	record, err := blockchain.GetBatchRecord(ctx, root)
+	if err != nil || !record.Anchored() {
+		return false
+	}
+	if err := database.ConfirmRegistrationBatchFromChain(p.db, batch.ID, record.Timestamp); err != nil {
+		log.Printf("ERROR: could not mark batch %d as anchored from chain: %v", batch.ID, err)
+		return false
+	}
+	return true
+}
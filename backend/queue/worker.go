@@ -0,0 +1,328 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"proofpot-backend/blockchain"
+	"proofpot-backend/contenthash"
+	"proofpot-backend/database"
+	"proofpot-backend/models"
+)
+
+// Config controls the registration worker pool. Values are read from the environment
+// by LoadConfig; zero values are replaced by the defaults below.
+type Config struct {
+	Mode           string        // ModeSingle or ModeBatch (REGISTRATION_MODE)
+	Workers        int           // Number of concurrent workers (REGISTRATION_WORKERS)
+	MaxAttempts    int           // Attempts before a job is marked failed (REGISTRATION_MAX_ATTEMPTS)
+	PollInterval   time.Duration // How long an idle worker sleeps before polling again
+	AttemptTimeout time.Duration // Upper bound for a single on-chain registration attempt (REGISTRATION_ATTEMPT_TIMEOUT_MINUTES)
+	ShutdownGrace  time.Duration // How long an attempt may keep waiting for its receipt after shutdown starts (REGISTRATION_SHUTDOWN_GRACE_SECONDS)
+	BaseBackoff    time.Duration // Delay before the first retry, doubled on every attempt
+	MaxBackoff     time.Duration // Cap for the exponential backoff
+	BatchWindow    time.Duration // ModeBatch: longest a recipe waits for its batch to fill (BATCH_WINDOW_SECONDS)
+	BatchMaxSize   int           // ModeBatch: recipes per batch, a full batch is anchored right away (BATCH_MAX_SIZE)
+}
+
+// Registration modes selectable with REGISTRATION_MODE.
+const (
+	ModeSingle = "single" // One addRecipe transaction per recipe (default)
+	ModeBatch  = "batch"  // One anchorBatch transaction per Merkle root of many recipes, see batch.go
+)
+
+const (
+	defaultWorkers        = 2
+	defaultMaxAttempts    = 8
+	defaultPollInterval   = 2 * time.Second
+	defaultAttemptTimeout = 30 * time.Minute // Leaves room for stuck-transaction replacements
+	defaultShutdownGrace  = 20 * time.Second
+	defaultBaseBackoff    = 5 * time.Second
+	defaultMaxBackoff     = 10 * time.Minute
+	defaultBatchWindow    = 5 * time.Minute
+	defaultBatchMaxSize   = 256
+)
+
+// LoadConfig reads the worker pool configuration from environment variables.
+func LoadConfig() Config {
+	timeoutMinutes := envInt("REGISTRATION_ATTEMPT_TIMEOUT_MINUTES", int(defaultAttemptTimeout/time.Minute))
+	cfg := Config{
+		Mode:           os.Getenv("REGISTRATION_MODE"),
+		Workers:        envInt("REGISTRATION_WORKERS", defaultWorkers),
+		MaxAttempts:    envInt("REGISTRATION_MAX_ATTEMPTS", defaultMaxAttempts),
+		AttemptTimeout: time.Duration(timeoutMinutes) * time.Minute,
+		ShutdownGrace:  time.Duration(envInt("REGISTRATION_SHUTDOWN_GRACE_SECONDS", int(defaultShutdownGrace/time.Second))) * time.Second,
+		BatchWindow:    time.Duration(envInt("BATCH_WINDOW_SECONDS", int(defaultBatchWindow/time.Second))) * time.Second,
+		BatchMaxSize:   envInt("BATCH_MAX_SIZE", defaultBatchMaxSize),
+	}
+	if cfg.Mode != "" && cfg.Mode != ModeSingle && cfg.Mode != ModeBatch {
+		log.Printf("Warning: invalid REGISTRATION_MODE=%q, using %q", cfg.Mode, ModeSingle)
+		cfg.Mode = ModeSingle
+	}
+	return cfg.withDefaults()
+}
+
+func (c Config) withDefaults() Config {
+	if c.Mode == "" {
+		c.Mode = ModeSingle
+	}
+	if c.Workers <= 0 {
+		c.Workers = defaultWorkers
+	}
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = defaultMaxAttempts
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = defaultPollInterval
+	}
+	if c.AttemptTimeout <= 0 {
+		c.AttemptTimeout = defaultAttemptTimeout
+	}
+	if c.ShutdownGrace <= 0 {
+		c.ShutdownGrace = defaultShutdownGrace
+	}
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = defaultBaseBackoff
+	}
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = defaultMaxBackoff
+	}
+	if c.BatchWindow <= 0 {
+		c.BatchWindow = defaultBatchWindow
+	}
+	if c.BatchMaxSize <= 0 {
+		c.BatchMaxSize = defaultBatchMaxSize
+	}
+	return c
+}
+
+func envInt(key string, fallback int) int {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		log.Printf("Warning: invalid %s=%q, using default %d", key, raw, fallback)
+		return fallback
+	}
+	return v
+}
+
+// Pool runs workers that drain the registration_jobs outbox, one transaction per
+// job (ModeSingle) or one per batch of jobs (ModeBatch).
+type Pool struct {
+	db  *sql.DB
+	cfg Config
+	wg  sync.WaitGroup
+}
+
+// NewPool creates a worker pool backed by the given database.
+func NewPool(db *sql.DB, cfg Config) *Pool {
+	return &Pool{db: db, cfg: cfg.withDefaults()}
+}
+
+// Start launches the workers. They stop claiming new jobs once ctx is cancelled;
+// call Wait to block until in-flight attempts have finished. Attempts still waiting
+// for a receipt ShutdownGrace after ctx is cancelled give up and release their job.
+func (p *Pool) Start(ctx context.Context) {
+	run := p.run
+	if p.cfg.Mode == ModeBatch {
+		log.Printf("Starting %d registration workers in batch mode (window %s, up to %d recipes per batch)", p.cfg.Workers, p.cfg.BatchWindow, p.cfg.BatchMaxSize)
+		run = p.runBatches
+	} else {
+		log.Printf("Starting %d registration workers", p.cfg.Workers)
+	}
+	for i := 0; i < p.cfg.Workers; i++ {
+		p.wg.Add(1)
+		go func(id int) {
+			defer p.wg.Done()
+			run(ctx, id)
+		}(i + 1)
+	}
+}
+
+// Wait blocks until all workers have exited.
+func (p *Pool) Wait() {
+	p.wg.Wait()
+}
+
+func (p *Pool) run(ctx context.Context, workerID int) {
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// While the chain is unreachable, jobs stay queued instead of burning attempts
+		if !blockchain.Connected() {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.cfg.PollInterval):
+			}
+			continue
+		}
+
+		// The lease must outlive a full attempt, otherwise another worker could
+		// reclaim a job that is still being processed.
+		jobs, err := database.ClaimRegistrationJobs(p.db, 1, p.cfg.AttemptTimeout+time.Minute)
+		if err != nil || len(jobs) == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(p.cfg.PollInterval):
+			}
+			continue
+		}
+
+		for _, job := range jobs {
+			p.process(ctx, workerID, job)
+		}
+	}
+}
+
+func (p *Pool) process(ctx context.Context, workerID int, job models.RegistrationJob) {
+	log.Printf("Worker %d: registering hash %s (job %d, attempt %d/%d)", workerID, job.ContentHash, job.ID, job.Attempts, p.cfg.MaxAttempts)
+
+	// A previous attempt may have broadcast a transaction that can still be mined;
+	// continue with it instead of sending a second addRecipe
+	previous, err := database.GetPendingRegistrationTxs(p.db, job.RecipeID)
+	if err != nil {
+		p.retry(workerID, job, err)
+		return
+	}
+
+	attemptCtx, cancel := p.attemptContext(ctx)
+	reg, err := blockchain.ResumeRecipeRegistration(attemptCtx, job.ContentHash, job.CreatorAddress, submittedTxs(previous), func(sub blockchain.SubmittedTx) {
+		// Called for the original transaction and for every fee-bumped replacement
+		if err := database.RecordRegistrationSubmission(p.db, job.RecipeID, sub.Hash, sub.From, sub.Nonce, sub.GasFeeCap.String(), sub.GasTipCap.String(), sub.Replaces); err != nil {
+			log.Printf("ERROR: Worker %d: could not record tx %s of job %d: %v", workerID, sub.Hash, job.ID, err)
+		}
+	})
+	cancel()
+
+	if err == nil {
+		log.Printf("Worker %d: successfully registered hash %s on chain (block %d)", workerID, job.ContentHash, reg.BlockNumber)
+		if err := database.ConfirmRegistrationJob(p.db, job.ID, job.RecipeID, reg.TxHash, int64(reg.BlockNumber), reg.BlockTime); err != nil {
+			log.Printf("ERROR: Worker %d: job %d was mined in tx %s but could not be marked done, it is claimed again when its lease expires: %v", workerID, job.ID, reg.TxHash, err)
+		}
+		return
+	}
+
+	// The hash is already on chain, e.g. an earlier attempt was mined after it timed
+	// out or another tool registered it. That's a success if the creator matches.
+	if errors.Is(err, blockchain.ErrHashAlreadyRegistered) && p.confirmFromChain(job) {
+		log.Printf("Worker %d: hash %s was already registered on chain for its creator", workerID, job.ContentHash)
+		return
+	}
+
+	// Shutting down: hand the job back right away, without counting the attempt.
+	// Its recorded transaction is resumed on the next claim.
+	if ctx.Err() != nil && errors.Is(err, context.Canceled) {
+		log.Printf("Worker %d: registration of hash %s interrupted by shutdown, job %d resumes on the next start", workerID, job.ContentHash, job.ID)
+		if err := database.ReleaseRegistrationJob(p.db, job.ID, job.RecipeID, err.Error()); err != nil {
+			log.Printf("ERROR: Worker %d: could not release job %d, it is claimed again when its lease expires: %v", workerID, job.ID, err)
+		}
+		return
+	}
+
+	// Contract rejections and invalid input will fail the same way every time
+	if !blockchain.IsRetryable(err) {
+		log.Printf("ERROR: Worker %d: registration of hash %s failed permanently: %v", workerID, job.ContentHash, err)
+		p.fail(workerID, job, err)
+		return
+	}
+
+	if job.Attempts >= p.cfg.MaxAttempts {
+		log.Printf("ERROR: Worker %d: giving up on hash %s after %d attempts: %v", workerID, job.ContentHash, job.Attempts, err)
+		p.fail(workerID, job, err)
+		return
+	}
+
+	p.retry(workerID, job, err)
+}
+
+// retry schedules the job's next attempt after the backoff delay.
+func (p *Pool) retry(workerID int, job models.RegistrationJob, cause error) {
+	delay := p.backoff(job.Attempts)
+	log.Printf("Worker %d: registration of hash %s failed, retrying in %s: %v", workerID, job.ContentHash, delay, cause)
+	if err := database.RetryRegistrationJob(p.db, job.ID, job.RecipeID, time.Now().Add(delay), cause.Error()); err != nil {
+		log.Printf("ERROR: Worker %d: could not reschedule job %d, it is claimed again when its lease expires: %v", workerID, job.ID, err)
+	}
+}
+
+// attemptContext bounds a single on-chain attempt by AttemptTimeout. Attempts are
+// not tied to the pool context, so a shutdown never interrupts a broadcast (the
+// blockchain package does not cancel sends either); once ctx is done, the attempt
+// has ShutdownGrace left before its wait for a receipt is cancelled.
+func (p *Pool) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	attemptCtx, cancel := context.WithTimeout(context.Background(), p.cfg.AttemptTimeout)
+	stop := context.AfterFunc(ctx, func() {
+		time.AfterFunc(p.cfg.ShutdownGrace, cancel)
+	})
+	return attemptCtx, func() {
+		stop()
+		cancel()
+	}
+}
+
+// submittedTxs converts recorded transactions for ResumeRecipeRegistration and ResumeBatchAnchor.
+func submittedTxs(txs []models.RegistrationTx) []blockchain.SubmittedTx {
+	out := make([]blockchain.SubmittedTx, len(txs))
+	for i, tx := range txs {
+		out[i] = blockchain.SubmittedTx{Hash: tx.TxHash, From: tx.FromAddress, Nonce: tx.Nonce}
+		out[i].GasFeeCap, _ = new(big.Int).SetString(tx.GasFeeCap, 10)
+		out[i].GasTipCap, _ = new(big.Int).SetString(tx.GasTipCap, 10)
+		if tx.Replaces != nil {
+			out[i].Replaces = *tx.Replaces
+		}
+	}
+	return out
+}
+
+// fail marks the job and its recipe as failed for good.
+func (p *Pool) fail(workerID int, job models.RegistrationJob, cause error) {
+	if err := database.FailRegistrationJob(p.db, job.ID, job.RecipeID, cause.Error()); err != nil {
+		log.Printf("ERROR: Worker %d: could not mark job %d as failed, it is claimed again when its lease expires: %v", workerID, job.ID, err)
+	}
+}
+
+// confirmFromChain completes the job if the registry already records the job's hash
+// for the job's creator. It reports whether the job was completed.
+func (p *Pool) confirmFromChain(job models.RegistrationJob) bool {
+	hash, err := contenthash.Decode(job.ContentHash)
+	if err != nil {
+		return false
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	record, err := blockchain.GetRecipeRecord(ctx, hash)
+	if err != nil || !record.Registered() || !strings.EqualFold(record.Creator.Hex(), job.CreatorAddress) {
+		return false
+	}
+	if err := database.ConfirmRegistrationJobFromChain(p.db, job.ID, job.RecipeID, record.Timestamp); err != nil {
+		log.Printf("ERROR: could not mark job %d as done from chain: %v", job.ID, err)
+		return false
+	}
+	return true
+}
+
+// backoff returns the delay before the next attempt: BaseBackoff * 2^(attempts-1), capped at MaxBackoff.
+func (p *Pool) backoff(attempts int) time.Duration {
+	delay := p.cfg.BaseBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= p.cfg.MaxBackoff {
+			return p.cfg.MaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,149 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"proofpot-backend/blockchain"
+	"proofpot-backend/blockchain/contracts"
+	"proofpot-backend/database"
+	"proofpot-backend/models"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func jobState(t *testing.T, db *sql.DB, recipeID int) (status string, attempts int) {
+	t.Helper()
+	if err := db.QueryRow(`SELECT status, attempts FROM registration_jobs WHERE recipe_id = $1`, recipeID).Scan(&status, &attempts); err != nil {
+		t.Fatal(err)
+	}
+	return status, attempts
+}
+
+func waitForJob(t *testing.T, db *sql.DB, recipeID int, status string) {
+	t.Helper()
+	deadline := time.Now().Add(20 * time.Second)
+	for time.Now().Before(deadline) {
+		if got, _ := jobState(t, db, recipeID); got == status {
+			return
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	got, _ := jobState(t, db, recipeID)
+	t.Fatalf("job of recipe %d is %s, want %s", recipeID, got, status)
+}
+
+// TestShutdownReleasesJobAndRestartResumesTx stops the pool while a job waits for
+// its transaction to be mined, then checks that the next pool completes the job
+// with that transaction instead of sending another one.
+func TestShutdownReleasesJobAndRestartResumesTx(t *testing.T) {
+	owner, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := blockchain.NewSimulatedChain(owner)
+	if err != nil {
+		t.Fatalf("NewSimulatedChain: %v", err)
+	}
+	t.Cleanup(func() { chain.Close() })
+	if err := chain.Use(); err != nil {
+		t.Fatalf("Use: %v", err)
+	}
+	db, err := database.Open("sqlite://" + filepath.Join(t.TempDir(), "proofpot.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := database.MigrateUp(db); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+
+	hash := crypto.Keccak256Hash([]byte("interrupted"))
+	creator := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	recipeID, err := database.CreateRecipeWithRegistrationJob(db, models.RecipeCreatePayload{
+		Title: "interrupted", Ingredients: "i", Steps: "s", CreatorAddress: creator.Hex(), ContentHash: hash.Hex(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An earlier attempt broadcast a transaction that is still pending: sent
+	// without mining a block and recorded like the worker does
+	chainID, err := chain.Backend.Client().ChainID(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts, err := bind.NewKeyedTransactorWithChainID(owner, chainID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	registry, err := contracts.NewRecipeRegistry(chain.Contract, chain.Backend.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := registry.AddRecipe(opts, hash, creator)
+	if err != nil {
+		t.Fatalf("AddRecipe: %v", err)
+	}
+	if err := database.RecordRegistrationSubmission(db, recipeID, tx.Hash().Hex(), opts.From.Hex(), tx.Nonce(), tx.GasFeeCap().String(), tx.GasTipCap().String(), ""); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := Config{Workers: 1, PollInterval: 10 * time.Millisecond, ShutdownGrace: 100 * time.Millisecond}
+	ctx, stop := context.WithCancel(context.Background())
+	pool := NewPool(db, cfg)
+	pool.Start(ctx)
+	waitForJob(t, db, recipeID, models.JobStatusProcessing)
+
+	// The attempt waits for the receipt; shutdown cuts that short after the grace period
+	stop()
+	stopped := make(chan struct{})
+	go func() {
+		pool.Wait()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(10 * time.Second):
+		t.Fatal("pool did not stop after the shutdown grace period")
+	}
+	if status, attempts := jobState(t, db, recipeID); status != models.JobStatusPending || attempts != 0 {
+		t.Fatalf("job after shutdown is %s after %d attempts, want pending without a counted attempt", status, attempts)
+	}
+	recipe, err := database.GetRecipeByHash(db, hash.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recipe.Registration.Status != models.RegistrationSubmitted || recipe.Registration.TxHash == nil || *recipe.Registration.TxHash != tx.Hash().Hex() {
+		t.Fatalf("registration after shutdown = %+v, want submitted with %s", recipe.Registration, tx.Hash().Hex())
+	}
+
+	// Mined while the backend was down; the next pool picks the transaction up
+	chain.Backend.Commit()
+	ctx, stop = context.WithCancel(context.Background())
+	defer stop()
+	pool = NewPool(db, cfg)
+	pool.Start(ctx)
+	waitForJob(t, db, recipeID, models.JobStatusDone)
+	stop()
+	pool.Wait()
+
+	recipe, err = database.GetRecipeByHash(db, hash.Hex())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recipe.Registration.Status != models.RegistrationConfirmed || *recipe.Registration.TxHash != tx.Hash().Hex() {
+		t.Errorf("registration = %+v, want confirmed with %s", recipe.Registration, tx.Hash().Hex())
+	}
+	if _, attempts := jobState(t, db, recipeID); attempts != 1 {
+		t.Errorf("job took %d attempts, want 1 (the interrupted one does not count)", attempts)
+	}
+	if nonce, err := chain.Backend.Client().NonceAt(context.Background(), opts.From, nil); err != nil || nonce != tx.Nonce()+1 {
+		t.Errorf("owner nonce = %d, %v, want %d (no second transaction)", nonce, err, tx.Nonce()+1)
+	}
+}
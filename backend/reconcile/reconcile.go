@@ -0,0 +1,302 @@
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"proofpot-backend/blockchain"
+	"proofpot-backend/contenthash"
+	"proofpot-backend/database"
+	"proofpot-backend/merkle"
+	"proofpot-backend/models"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Config controls the reconciler. Values are read from the environment by LoadConfig.
+type Config struct {
+	Interval    time.Duration // Time between periodic runs, 0 disables them (RECONCILE_INTERVAL_MINUTES)
+	Repair      bool          // Re-enqueue recipes missing on chain (RECONCILE_REPAIR)
+	StartBlock  *uint64       // First block to scan for on-chain hashes missing in the DB (INDEXER_START_BLOCK)
+	BatchSize   uint64        // Max blocks per eth_getLogs request when scanning
+	GracePeriod time.Duration // How long a registration may stay in flight before it counts as drift (RECONCILE_GRACE_MINUTES)
+}
+
+const (
+	defaultBatchSize = 2000
+	// Long enough for a job to go through its retries with backoff and
+	// stuck-transaction replacements
+	defaultGracePeriod = 2 * time.Hour
+)
+
+// LoadConfig reads the reconciler configuration from environment variables.
+func LoadConfig() Config {
+	cfg := Config{
+		Repair:      os.Getenv("RECONCILE_REPAIR") == "true",
+		BatchSize:   defaultBatchSize,
+		GracePeriod: defaultGracePeriod,
+	}
+	if raw := os.Getenv("RECONCILE_INTERVAL_MINUTES"); raw != "" {
+		minutes, err := strconv.Atoi(raw)
+		if err != nil {
+			log.Printf("Warning: invalid RECONCILE_INTERVAL_MINUTES=%q, periodic reconciliation disabled", raw)
+		} else {
+			cfg.Interval = time.Duration(minutes) * time.Minute
+		}
+	}
+	if raw := os.Getenv("RECONCILE_GRACE_MINUTES"); raw != "" {
+		minutes, err := strconv.Atoi(raw)
+		if err != nil || minutes < 0 {
+			log.Printf("Warning: invalid RECONCILE_GRACE_MINUTES=%q, using default %s", raw, defaultGracePeriod)
+		} else {
+			cfg.GracePeriod = time.Duration(minutes) * time.Minute
+		}
+	}
+	if raw := os.Getenv("INDEXER_START_BLOCK"); raw != "" {
+		start, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			log.Printf("Warning: invalid INDEXER_START_BLOCK=%q, on-chain scan disabled", raw)
+		} else {
+			cfg.StartBlock = &start
+		}
+	}
+	return cfg
+}
+
+// CreatorMismatch is a recipe whose on-chain creator differs from the database.
+type CreatorMismatch struct {
+	ContentHash    string `json:"contentHash"`
+	DBCreator      string `json:"dbCreator"`
+	OnChainCreator string `json:"onChainCreator"`
+}
+
+// OrphanHash is a hash registered on chain that has no recipe in the database.
+type OrphanHash struct {
+	ContentHash string `json:"contentHash"`
+	Creator     string `json:"creator"`
+	TxHash      string `json:"txHash"`
+	BlockNumber uint64 `json:"blockNumber"`
+}
+
+// Report summarizes the drift found (and repaired) by a reconciliation run.
+type Report struct {
+	StartedAt         time.Time         `json:"startedAt"`
+	Checked           int               `json:"checked"`
+	MissingOnChain    []string          `json:"missingOnChain"`    // DB recipes the contract does not know
+	InFlight          []string          `json:"inFlight"`          // Not on chain yet, but still being registered within the grace period
+	Failed            []string          `json:"failed"`            // Not on chain because their registration failed; the API shows them as failed
+	Reenqueued        []string          `json:"reenqueued"`        // Subset of MissingOnChain that got a new job
+	MissingInDB       []OrphanHash      `json:"missingInDb"`       // On-chain hashes without a recipe row
+	CreatorMismatches []CreatorMismatch `json:"creatorMismatches"` // Same hash, different creator
+	ScannedChain      bool              `json:"scannedChain"`      // False when no start block is configured
+	Errors            []string          `json:"errors,omitempty"`
+}
+
+// HasDrift reports whether the run found anything that needs attention.
+func (r *Report) HasDrift() bool {
+	return len(r.MissingOnChain) > 0 || len(r.MissingInDB) > 0 || len(r.CreatorMismatches) > 0
+}
+
+// Reconciler compares the recipes table with the RecipeRegistry contract.
+type Reconciler struct {
+	db  *sql.DB
+	cfg Config
+}
+
+// New creates a reconciler backed by the given database.
+func New(db *sql.DB, cfg Config) *Reconciler {
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = defaultBatchSize
+	}
+	return &Reconciler{db: db, cfg: cfg}
+}
+
+// Run reconciles every Interval until ctx is cancelled.
+func (r *Reconciler) Run(ctx context.Context) {
+	log.Printf("Starting periodic reconciliation every %s (repair: %t)", r.cfg.Interval, r.cfg.Repair)
+	ticker := time.NewTicker(r.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !blockchain.Connected() {
+				log.Println("Skipping reconciliation: blockchain not connected")
+				continue
+			}
+			report, err := r.Once(ctx)
+			if err != nil {
+				log.Printf("Reconciliation failed: %v", err)
+				continue
+			}
+			r.logReport(report)
+		}
+	}
+}
+
+// Once performs a single reconciliation pass. Recipes the workers are still
+// registering only count as missing on chain once they are older than the grace
+// period; failed registrations are listed separately.
+func (r *Reconciler) Once(ctx context.Context) (*Report, error) {
+	report := &Report{StartedAt: time.Now().UTC()}
+
+	recipes, err := database.GetAllRecipes(r.db)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load recipes: %w", err)
+	}
+
+	known := make(map[string]bool, len(recipes))
+	for _, recipe := range recipes {
+		known[strings.ToLower(recipe.ContentHash)] = true
+		if err := r.checkRecipe(ctx, recipe, report); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", recipe.ContentHash, err))
+		}
+		report.Checked++
+	}
+
+	if r.cfg.StartBlock != nil {
+		if err := r.scanChain(ctx, known, report); err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("chain scan: %v", err))
+		} else {
+			report.ScannedChain = true
+		}
+	}
+
+	return report, nil
+}
+
+// checkRecipe compares one recipe row with its on-chain record.
+func (r *Reconciler) checkRecipe(ctx context.Context, recipe models.RecipeListItem, report *Report) error {
+	hash, err := contenthash.Decode(recipe.ContentHash)
+	if err != nil {
+		return err
+	}
+	record, err := blockchain.GetRecipeRecord(ctx, hash)
+	if err != nil {
+		return err
+	}
+
+	if record.Registered() {
+		if !strings.EqualFold(record.Creator.Hex(), recipe.CreatorAddress) {
+			report.CreatorMismatches = append(report.CreatorMismatches, CreatorMismatch{
+				ContentHash:    recipe.ContentHash,
+				DBCreator:      recipe.CreatorAddress,
+				OnChainCreator: record.Creator.Hex(),
+			})
+		}
+		return nil
+	}
+
+	// Recipes anchored in a batch are only known to the contract by their batch root
+	if inBatch, err := r.anchoredInBatch(ctx, recipe); err != nil || inBatch {
+		return err
+	}
+
+	open, err := database.HasOpenRegistrationJob(r.db, recipe.ID)
+	if err != nil {
+		return err
+	}
+	inFlight := open || recipe.Registration.Status == models.RegistrationPending || recipe.Registration.Status == models.RegistrationSubmitted
+	switch {
+	case inFlight && time.Since(recipe.CreatedAt) < r.cfg.GracePeriod:
+		report.InFlight = append(report.InFlight, recipe.ContentHash)
+		return nil
+	case recipe.Registration.Status == models.RegistrationFailed && !open:
+		// Repeating a registration that failed for good is left to an operator
+		report.Failed = append(report.Failed, recipe.ContentHash)
+		return nil
+	default:
+		report.MissingOnChain = append(report.MissingOnChain, recipe.ContentHash)
+	}
+	// Don't pile up jobs for recipes the workers are still trying to register
+	if !r.cfg.Repair || open {
+		return nil
+	}
+	if err := database.ReenqueueRegistrationJob(r.db, recipe.ID, recipe.ContentHash, recipe.CreatorAddress); err != nil {
+		return err
+	}
+	report.Reenqueued = append(report.Reenqueued, recipe.ContentHash)
+	return nil
+}
+
+// anchoredInBatch reports whether the recipe's batch proof leads to a root the contract
+// has anchored. Recipes in a batch that is still open are reported like recipes with
+// an open job, and not re-enqueued for the same reason.
+func (r *Reconciler) anchoredInBatch(ctx context.Context, recipe models.RecipeListItem) (bool, error) {
+	proof, err := database.GetBatchProof(r.db, recipe.ContentHash)
+	if err != nil || proof == nil || proof.BatchStatus != models.JobStatusDone {
+		return false, err
+	}
+
+	hash, err := contenthash.Decode(recipe.ContentHash)
+	if err != nil {
+		return false, err
+	}
+	siblings := make([]common.Hash, len(proof.Proof))
+	for i, sibling := range proof.Proof {
+		siblings[i] = common.HexToHash(sibling)
+	}
+	root := common.HexToHash(proof.MerkleRoot)
+	if !merkle.Verify(root, merkle.Leaf(hash, common.HexToAddress(recipe.CreatorAddress)), siblings) {
+		return false, nil
+	}
+	record, err := blockchain.GetBatchRecord(ctx, root)
+	if err != nil {
+		return false, err
+	}
+	return record.Anchored(), nil
+}
+
+// scanChain walks RecipeAdded events from StartBlock and flags hashes that are not in the database.
+func (r *Reconciler) scanChain(ctx context.Context, known map[string]bool, report *Report) error {
+	head, err := blockchain.LatestBlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	for from := *r.cfg.StartBlock; from <= head; from += r.cfg.BatchSize {
+		to := min(from+r.cfg.BatchSize-1, head)
+		events, err := blockchain.FilterRecipeAdded(ctx, from, to)
+		if err != nil {
+			return err
+		}
+		for _, ev := range events {
+			hash := "0x" + hex.EncodeToString(ev.RecipeHash[:])
+			if known[hash] {
+				continue
+			}
+			report.MissingInDB = append(report.MissingInDB, OrphanHash{
+				ContentHash: hash,
+				Creator:     ev.Creator.Hex(),
+				TxHash:      ev.TxHash.Hex(),
+				BlockNumber: ev.BlockNumber,
+			})
+		}
+	}
+	return nil
+}
+
+func (r *Reconciler) logReport(report *Report) {
+	if !report.HasDrift() && len(report.Errors) == 0 {
+		log.Printf("Reconciliation: %d recipes checked, no drift (%d still registering, %d failed)", report.Checked, len(report.InFlight), len(report.Failed))
+		return
+	}
+	log.Printf("Reconciliation: %d recipes checked, %d missing on chain (%d re-enqueued), %d still registering, %d failed, %d on-chain hashes missing in DB, %d creator mismatches, %d errors",
+		report.Checked, len(report.MissingOnChain), len(report.Reenqueued), len(report.InFlight), len(report.Failed), len(report.MissingInDB), len(report.CreatorMismatches), len(report.Errors))
+	for _, m := range report.CreatorMismatches {
+		log.Printf("Reconciliation: creator mismatch for %s: db %s, chain %s", m.ContentHash, m.DBCreator, m.OnChainCreator)
+	}
+	for _, o := range report.MissingInDB {
+		log.Printf("Reconciliation: on-chain hash %s (creator %s, tx %s) not in database", o.ContentHash, o.Creator, o.TxHash)
+	}
+	for _, e := range report.Errors {
+		log.Printf("Reconciliation error: %s", e)
+	}
+}
@@ -0,0 +1,219 @@
+package reconcile
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"proofpot-backend/blockchain"
+	"proofpot-backend/database"
+	"proofpot-backend/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var creator = common.HexToAddress("0x00000000000000000000000000000000000000c0").Hex()
+
+// testEnv is a simulated chain the blockchain package is configured for and a
+// migrated SQLite database.
+type testEnv struct {
+	db  *sql.DB
+	ctx context.Context
+}
+
+func newTestEnv(t *testing.T) *testEnv {
+	t.Helper()
+	owner, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, err := blockchain.NewSimulatedChain(owner)
+	if err != nil {
+		t.Fatalf("NewSimulatedChain: %v", err)
+	}
+	t.Cleanup(func() { chain.Close() })
+	if err := chain.Use(); err != nil {
+		t.Fatalf("Use: %v", err)
+	}
+
+	db, err := database.Open("sqlite://" + filepath.Join(t.TempDir(), "proofpot.db"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := database.MigrateUp(db); err != nil {
+		t.Fatalf("MigrateUp: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	t.Cleanup(cancel)
+	return &testEnv{db: db, ctx: ctx}
+}
+
+// store creates a recipe with its registration job and returns its hash and ID.
+func (e *testEnv) store(t *testing.T, label, creatorAddress string) (string, int) {
+	t.Helper()
+	hash := crypto.Keccak256Hash([]byte(label)).Hex()
+	payload := models.RecipeCreatePayload{Title: label, Ingredients: "i", Steps: "s", CreatorAddress: creatorAddress, ContentHash: hash}
+	id, err := database.CreateRecipeWithRegistrationJob(e.db, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash, id
+}
+
+func (e *testEnv) registerOnChain(t *testing.T, hash, creatorAddress string) {
+	t.Helper()
+	if _, err := blockchain.RegisterRecipeOnChain(e.ctx, hash, creatorAddress, nil); err != nil {
+		t.Fatalf("RegisterRecipeOnChain: %v", err)
+	}
+}
+
+func (e *testEnv) exec(t *testing.T, query string, args ...any) {
+	t.Helper()
+	if _, err := e.db.Exec(query, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReconcileClassifiesDrift(t *testing.T) {
+	e := newTestEnv(t)
+
+	// Confirmed in the DB, but the contract never saw it
+	missingOnChain, id := e.store(t, "missing on chain", creator)
+	e.exec(t, `UPDATE registration_jobs SET status = 'done' WHERE recipe_id = $1`, id)
+	e.exec(t, `UPDATE recipes SET registration_status = 'confirmed', registration_tx_hash = '0xlost' WHERE id = $1`, id)
+
+	// Registered on chain with a creator that differs from the row
+	mismatch, id := e.store(t, "creator mismatch", creator)
+	e.registerOnChain(t, mismatch, common.HexToAddress("0x00000000000000000000000000000000000000c1").Hex())
+	e.exec(t, `UPDATE registration_jobs SET status = 'done' WHERE recipe_id = $1`, id)
+
+	// Registered on chain without a recipe row
+	orphan := crypto.Keccak256Hash([]byte("missing in db")).Hex()
+	e.registerOnChain(t, orphan, creator)
+
+	// Fine: registered with the stored creator
+	registered, id := e.store(t, "registered", creator)
+	e.registerOnChain(t, registered, creator)
+	e.exec(t, `UPDATE registration_jobs SET status = 'done' WHERE recipe_id = $1`, id)
+
+	// Just created, its job has not run yet
+	inFlight, _ := e.store(t, "in flight", creator)
+
+	// Still submitted long after its job gave up
+	stale, id := e.store(t, "stale", creator)
+	e.exec(t, `UPDATE registration_jobs SET status = 'done' WHERE recipe_id = $1`, id)
+	e.exec(t, `UPDATE recipes SET registration_status = 'submitted', created_at = $2 WHERE id = $1`, id, time.Now().Add(-3*time.Hour))
+
+	failed, id := e.store(t, "failed", creator)
+	if err := database.FailRegistrationJob(e.db, jobID(t, e.db, id), id, "execution reverted"); err != nil {
+		t.Fatal(err)
+	}
+
+	start := uint64(0)
+	report, err := New(e.db, Config{StartBlock: &start, GracePeriod: time.Hour}).Once(e.ctx)
+	if err != nil {
+		t.Fatalf("Once: %v", err)
+	}
+	if len(report.Errors) > 0 {
+		t.Fatalf("errors: %v", report.Errors)
+	}
+	if report.Checked != 6 || !report.ScannedChain {
+		t.Errorf("checked %d, scanned chain %t, want 6 and true", report.Checked, report.ScannedChain)
+	}
+	if !sameHashes(report.MissingOnChain, []string{missingOnChain, stale}) {
+		t.Errorf("MissingOnChain = %v, want %s and %s", report.MissingOnChain, missingOnChain, stale)
+	}
+	if !sameHashes(report.InFlight, []string{inFlight}) {
+		t.Errorf("InFlight = %v, want %s", report.InFlight, inFlight)
+	}
+	if !sameHashes(report.Failed, []string{failed}) {
+		t.Errorf("Failed = %v, want %s", report.Failed, failed)
+	}
+	if len(report.CreatorMismatches) != 1 || report.CreatorMismatches[0].ContentHash != mismatch {
+		t.Errorf("CreatorMismatches = %+v, want %s", report.CreatorMismatches, mismatch)
+	}
+	if len(report.MissingInDB) != 1 || report.MissingInDB[0].ContentHash != orphan {
+		t.Errorf("MissingInDB = %+v, want %s", report.MissingInDB, orphan)
+	}
+	if len(report.Reenqueued) != 0 {
+		t.Errorf("Reenqueued = %v without repair", report.Reenqueued)
+	}
+	if !report.HasDrift() {
+		t.Error("HasDrift() = false")
+	}
+}
+
+func TestReconcileInFlightIsNotDrift(t *testing.T) {
+	e := newTestEnv(t)
+	registered, id := e.store(t, "registered", creator)
+	e.registerOnChain(t, registered, creator)
+	e.exec(t, `UPDATE registration_jobs SET status = 'done' WHERE recipe_id = $1`, id)
+	pending, _ := e.store(t, "pending", creator)
+	submitted, id := e.store(t, "submitted", creator)
+	e.exec(t, `UPDATE recipes SET registration_status = 'submitted', registration_tx_hash = '0xsent' WHERE id = $1`, id)
+
+	report, err := New(e.db, Config{GracePeriod: time.Hour, Repair: true}).Once(e.ctx)
+	if err != nil {
+		t.Fatalf("Once: %v", err)
+	}
+	if report.HasDrift() || len(report.Errors) > 0 {
+		t.Fatalf("report = %+v, want no drift", report)
+	}
+	if !sameHashes(report.InFlight, []string{pending, submitted}) {
+		t.Errorf("InFlight = %v, want %s and %s", report.InFlight, pending, submitted)
+	}
+	if len(report.Reenqueued) != 0 {
+		t.Errorf("Reenqueued = %v, want nothing for in-flight recipes", report.Reenqueued)
+	}
+}
+
+func TestReconcileRepairReenqueuesMissing(t *testing.T) {
+	e := newTestEnv(t)
+	missing, id := e.store(t, "missing on chain", creator)
+	e.exec(t, `UPDATE registration_jobs SET status = 'done' WHERE recipe_id = $1`, id)
+	e.exec(t, `UPDATE recipes SET registration_status = 'confirmed' WHERE id = $1`, id)
+
+	report, err := New(e.db, Config{GracePeriod: time.Hour, Repair: true}).Once(e.ctx)
+	if err != nil {
+		t.Fatalf("Once: %v", err)
+	}
+	if !sameHashes(report.Reenqueued, []string{missing}) {
+		t.Errorf("Reenqueued = %v, want %s", report.Reenqueued, missing)
+	}
+	open, err := database.HasOpenRegistrationJob(e.db, id)
+	if err != nil || !open {
+		t.Errorf("HasOpenRegistrationJob = %t, %v, want a new job", open, err)
+	}
+	if recipe, err := database.GetRecipeByHash(e.db, missing); err != nil || recipe.Registration.Status != models.RegistrationPending {
+		t.Errorf("re-enqueued recipe = %+v, %v, want pending", recipe, err)
+	}
+
+	// The new job makes it in flight again instead of being enqueued twice
+	report, err = New(e.db, Config{GracePeriod: time.Hour, Repair: true}).Once(e.ctx)
+	if err != nil {
+		t.Fatalf("Once: %v", err)
+	}
+	if !sameHashes(report.InFlight, []string{missing}) || len(report.Reenqueued) != 0 {
+		t.Errorf("second run: InFlight = %v, Reenqueued = %v", report.InFlight, report.Reenqueued)
+	}
+}
+
+func jobID(t *testing.T, db *sql.DB, recipeID int) int {
+	t.Helper()
+	var id int
+	if err := db.QueryRow(`SELECT id FROM registration_jobs WHERE recipe_id = $1`, recipeID).Scan(&id); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// sameHashes compares hash lists regardless of order; recipes are listed newest first.
+func sameHashes(got, want []string) bool {
+	return slices.Equal(slices.Sorted(slices.Values(got)), slices.Sorted(slices.Values(want)))
+}
@@ -13,6 +13,13 @@ contract RecipeRegistry is Ownable {
     // Mapping from recipe content hash to the block timestamp it was added
     mapping(bytes32 => uint256) public recipeTimestamps;
 
+    // Accounts the owner has authorized to add recipes (the backend's signer pool)
+    mapping(address => bool) public registrars;
+
+    // Mapping from a batch Merkle root to the block timestamp it was anchored
+    // Each leaf is keccak256(abi.encodePacked(recipeHash, creator))
+    mapping(bytes32 => uint256) public batchRoots;
+
     // Event emitted when a new recipe is added
     event RecipeAdded(
         bytes32 indexed recipeHash,
@@ -20,12 +27,41 @@ contract RecipeRegistry is Ownable {
         uint256 timestamp
     );
 
-    // Function for the backend (owner) to add a new recipe hash on behalf of a creator
+    // Event emitted when a batch of recipe hashes is anchored by its Merkle root
+    event BatchAnchored(
+        bytes32 indexed root,
+        uint256 leafCount,
+        uint256 timestamp
+    );
+
+    // Event emitted when the owner grants or revokes the registrar role
+    event RegistrarUpdated(address indexed account, bool authorized);
+
+    // Error for addRecipe calls from an account that is neither owner nor registrar
+    error UnauthorizedRegistrar(address account);
+
+    // Restricts a function to the owner and authorized registrars
+    modifier onlyRegistrar() {
+        if (msg.sender != owner() && !registrars[msg.sender]) {
+            revert UnauthorizedRegistrar(msg.sender);
+        }
+        _;
+    }
+
+    // Function for the owner to grant or revoke the registrar role
+    // Lets the backend spread registrations over several keys, each with its own nonce sequence
+    function setRegistrar(address _account, bool _authorized) public onlyOwner {
+        require(_account != address(0), "Registrar address cannot be zero");
+        registrars[_account] = _authorized;
+        emit RegistrarUpdated(_account, _authorized);
+    }
+
+    // Function for the backend (owner or a registrar) to add a new recipe hash on behalf of a creator
     // Checks if the hash already exists
     // Stores the provided creator address as the owner
     // Stores the current block timestamp
     // Emits the RecipeAdded event
-    function addRecipe(bytes32 _recipeHash, address _creator) public onlyOwner {
+    function addRecipe(bytes32 _recipeHash, address _creator) public onlyRegistrar {
         require(
             recipeOwners[_recipeHash] == address(0),
             "Recipe hash already exists"
@@ -36,6 +72,36 @@ contract RecipeRegistry is Ownable {
         emit RecipeAdded(_recipeHash, _creator, block.timestamp);
     }
 
+    // Function for the backend (owner or a registrar) to anchor a batch of recipes with one transaction
+    // Only the Merkle root is stored; inclusion proofs are served off chain
+    function anchorBatch(bytes32 _root, uint256 _leafCount) public onlyRegistrar {
+        require(_root != bytes32(0), "Batch root cannot be zero");
+        require(batchRoots[_root] == 0, "Batch root already anchored");
+        batchRoots[_root] = block.timestamp;
+        emit BatchAnchored(_root, _leafCount, block.timestamp);
+    }
+
+    // Checks that a recipe hash and creator are part of an anchored batch
+    // Proofs use sorted-pair hashing, so no left/right flags are needed
+    function verifyBatchInclusion(
+        bytes32 _root,
+        bytes32 _recipeHash,
+        address _creator,
+        bytes32[] calldata _proof
+    ) public view returns (bool) {
+        if (batchRoots[_root] == 0) {
+            return false;
+        }
+        bytes32 node = keccak256(abi.encodePacked(_recipeHash, _creator));
+        for (uint256 i = 0; i < _proof.length; i++) {
+            bytes32 sibling = _proof[i];
+            node = node < sibling
+                ? keccak256(abi.encodePacked(node, sibling))
+                : keccak256(abi.encodePacked(sibling, node));
+        }
+        return node == _root;
+    }
+
     // Constructor to set the initial owner (deployer)
     constructor(address initialOwner) Ownable(initialOwner) {
         // The deployer address is automatically set as the owner by Ownable constructor
@@ -0,0 +1,28 @@
+import { artifacts } from "hardhat";
+import fs from "fs";
+import path from "path";
+
+// Writes the compiled ABI and bytecode of the contracts the Go backend talks to
+// into backend/blockchain/contracts, where `go generate` turns them into typed
+// bindings. Run after `npx hardhat compile` whenever a contract changes.
+const CONTRACTS = ["RecipeRegistry"];
+const OUT_DIR = path.join(__dirname, "..", "..", "backend", "blockchain", "contracts");
+
+async function main() {
+    fs.mkdirSync(OUT_DIR, { recursive: true });
+
+    for (const name of CONTRACTS) {
+        const artifact = await artifacts.readArtifact(name);
+
+        fs.writeFileSync(path.join(OUT_DIR, `${name}.abi`), JSON.stringify(artifact.abi) + "\n");
+        // abigen expects the bytecode without the 0x prefix
+        fs.writeFileSync(path.join(OUT_DIR, `${name}.bin`), artifact.bytecode.replace(/^0x/, "") + "\n");
+
+        console.log(`Exported ${name} ABI and bytecode to ${OUT_DIR}`);
+    }
+}
+
+main().catch((error) => {
+    console.error(error);
+    process.exitCode = 1;
+});
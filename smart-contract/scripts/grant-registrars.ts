@@ -0,0 +1,37 @@
+import { ethers } from "hardhat";
+
+// Grants the registrar role on an existing RecipeRegistry to the backend's signer
+// accounts. Must be run with the owner key, e.g.:
+//   RECIPE_REGISTRY_CONTRACT_ADDRESS=0x... REGISTRAR_ADDRESSES=0xabc...,0xdef... \
+//     npx hardhat run scripts/grant-registrars.ts --network sepolia
+// Set REVOKE=true to revoke the role instead.
+async function main() {
+    const contractAddress = process.env.RECIPE_REGISTRY_CONTRACT_ADDRESS;
+    const registrars = (process.env.REGISTRAR_ADDRESSES || "")
+        .split(",")
+        .map((address) => address.trim())
+        .filter((address) => address !== "");
+    const authorized = process.env.REVOKE !== "true";
+
+    if (!contractAddress || registrars.length === 0) {
+        throw new Error("Set RECIPE_REGISTRY_CONTRACT_ADDRESS and REGISTRAR_ADDRESSES");
+    }
+
+    const [owner] = await ethers.getSigners();
+    const recipeRegistry = await ethers.getContractAt("RecipeRegistry", contractAddress, owner);
+
+    for (const registrar of registrars) {
+        if ((await recipeRegistry.registrars(registrar)) === authorized) {
+            console.log(`${registrar} already ${authorized ? "authorized" : "revoked"}, skipping`);
+            continue;
+        }
+        const tx = await recipeRegistry.setRegistrar(registrar, authorized);
+        await tx.wait();
+        console.log(`${authorized ? "Granted" : "Revoked"} registrar role for ${registrar} (tx ${tx.hash})`);
+    }
+}
+
+main().catch((error) => {
+    console.error(error);
+    process.exitCode = 1;
+});
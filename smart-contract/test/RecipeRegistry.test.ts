@@ -80,11 +80,20 @@ describe("RecipeRegistry", function () {
                 .to.be.revertedWith("Recipe hash already exists");
         });
 
-        it("Should fail if called by a non-owner", async function () {
+        it("Should fail if called by an account that is neither owner nor registrar", async function () {
             // Attempt to call addRecipe from nonOwner account
             const registryAsNonOwner = recipeRegistry.connect(nonOwner);
             await expect(registryAsNonOwner.addRecipe(recipeHash, addr1.address))
-                .to.be.revertedWithCustomError(recipeRegistry, "OwnableUnauthorizedAccount"); // Use custom error for Ownable v5+
+                .to.be.revertedWithCustomError(recipeRegistry, "UnauthorizedRegistrar")
+                .withArgs(nonOwner.address);
+        });
+
+        it("Should allow an authorized registrar to add a recipe hash", async function () {
+            await recipeRegistry.setRegistrar(nonOwner.address, true);
+
+            await expect(recipeRegistry.connect(nonOwner).addRecipe(recipeHash, addr1.address))
+                .to.emit(recipeRegistry, "RecipeAdded");
+            expect(await recipeRegistry.recipeOwners(recipeHash)).to.equal(addr1.address);
         });
 
         it("Should fail if the creator address is the zero address", async function () {
@@ -93,4 +102,88 @@ describe("RecipeRegistry", function () {
                 .to.be.revertedWith("Creator address cannot be zero");
         });
     });
-}); 
\ No newline at end of file
+
+    describe("setRegistrar", function () {
+        it("Should let the owner grant and revoke the registrar role", async function () {
+            await expect(recipeRegistry.setRegistrar(nonOwner.address, true))
+                .to.emit(recipeRegistry, "RegistrarUpdated")
+                .withArgs(nonOwner.address, true);
+            expect(await recipeRegistry.registrars(nonOwner.address)).to.equal(true);
+
+            await expect(recipeRegistry.setRegistrar(nonOwner.address, false))
+                .to.emit(recipeRegistry, "RegistrarUpdated")
+                .withArgs(nonOwner.address, false);
+            expect(await recipeRegistry.registrars(nonOwner.address)).to.equal(false);
+
+            // A revoked registrar can no longer add recipes
+            await expect(recipeRegistry.connect(nonOwner).addRecipe(recipeHash, addr1.address))
+                .to.be.revertedWithCustomError(recipeRegistry, "UnauthorizedRegistrar");
+        });
+
+        it("Should fail if called by a non-owner", async function () {
+            await expect(recipeRegistry.connect(nonOwner).setRegistrar(nonOwner.address, true))
+                .to.be.revertedWithCustomError(recipeRegistry, "OwnableUnauthorizedAccount"); // Use custom error for Ownable v5+
+        });
+
+        it("Should fail for the zero address", async function () {
+            await expect(recipeRegistry.setRegistrar(ethers.ZeroAddress, true))
+                .to.be.revertedWith("Registrar address cannot be zero");
+        });
+    });
+
+    describe("anchorBatch", function () {
+        // Two-leaf tree with sorted-pair hashing, as built by the backend
+        const leafFor = (hash: string, creator: string) =>
+            ethers.solidityPackedKeccak256(["bytes32", "address"], [hash, creator]);
+        const hashPair = (a: string, b: string) =>
+            BigInt(a) < BigInt(b)
+                ? ethers.solidityPackedKeccak256(["bytes32", "bytes32"], [a, b])
+                : ethers.solidityPackedKeccak256(["bytes32", "bytes32"], [b, a]);
+
+        let otherHash: string;
+        let root: string;
+
+        beforeEach(async function () {
+            otherHash = ethers.keccak256(ethers.toUtf8Bytes("Other Recipe Content"));
+            root = hashPair(leafFor(recipeHash, addr1.address), leafFor(otherHash, nonOwner.address));
+        });
+
+        it("Should store the root and emit BatchAnchored", async function () {
+            const tx = await recipeRegistry.anchorBatch(root, 2);
+            const receipt = await tx.wait();
+            const block = await ethers.provider.getBlock(receipt!.blockNumber);
+
+            await expect(tx)
+                .to.emit(recipeRegistry, "BatchAnchored")
+                .withArgs(root, 2, block!.timestamp);
+            expect(await recipeRegistry.batchRoots(root)).to.equal(block!.timestamp);
+        });
+
+        it("Should verify inclusion proofs against an anchored root", async function () {
+            const proof = [leafFor(otherHash, nonOwner.address)];
+            expect(await recipeRegistry.verifyBatchInclusion(root, recipeHash, addr1.address, proof)).to.equal(false);
+
+            await recipeRegistry.anchorBatch(root, 2);
+            expect(await recipeRegistry.verifyBatchInclusion(root, recipeHash, addr1.address, proof)).to.equal(true);
+            // The leaf binds the creator
+            expect(await recipeRegistry.verifyBatchInclusion(root, recipeHash, nonOwner.address, proof)).to.equal(false);
+        });
+
+        it("Should fail if the root was already anchored", async function () {
+            await recipeRegistry.anchorBatch(root, 2);
+            await expect(recipeRegistry.anchorBatch(root, 2))
+                .to.be.revertedWith("Batch root already anchored");
+        });
+
+        it("Should fail for the zero root", async function () {
+            await expect(recipeRegistry.anchorBatch(ethers.ZeroHash, 0))
+                .to.be.revertedWith("Batch root cannot be zero");
+        });
+
+        it("Should fail if called by an account that is neither owner nor registrar", async function () {
+            await expect(recipeRegistry.connect(nonOwner).anchorBatch(root, 2))
+                .to.be.revertedWithCustomError(recipeRegistry, "UnauthorizedRegistrar")
+                .withArgs(nonOwner.address);
+        });
+    });
+});
@@ -0,0 +1,29 @@
+// Checks the shared hashing vectors of backend/contenthash (run with npm test).
+import { test } from 'node:test';
+import assert from 'node:assert/strict';
+import { createHash } from 'node:crypto';
+import { readFileSync } from 'node:fs';
+import { schemePreimage } from './contentHash.ts';
+
+interface Vector {
+  name: string;
+  scheme: number;
+  title: string;
+  ingredients: string;
+  steps: string;
+  preimage: string;
+  hash: string;
+}
+
+const vectors: Vector[] = JSON.parse(
+  readFileSync(new URL('../../backend/contenthash/testdata/vectors.json', import.meta.url), 'utf8'),
+);
+
+for (const v of vectors) {
+  test(v.name, () => {
+    const preimage = schemePreimage(v.scheme, v.title, v.ingredients, v.steps);
+    assert.equal(preimage, v.preimage);
+    // Same as ethers.sha256(ethers.toUtf8Bytes(preimage)) in CreateRecipePage
+    assert.equal('0x' + createHash('sha256').update(preimage, 'utf8').digest('hex'), v.hash);
+  });
+}
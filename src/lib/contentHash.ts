@@ -0,0 +1,36 @@
+// Canonical recipe content hashing, mirroring backend/contenthash. New recipes use
+// scheme v2. Keep both implementations in sync: the backend recomputes and rejects
+// mismatches. backend/contenthash/testdata/vectors.json pins both (npm test).
+
+export const CONTENT_HASH_SCHEME = 2;
+
+// NFC-normalize, convert CRLF/CR to LF, trim every line and drop blank lines.
+export function canonicalize(text: string): string {
+  return text
+    .normalize('NFC')
+    .replace(/\r\n?/g, '\n')
+    .split('\n')
+    .map((line) => line.trim())
+    .filter((line) => line !== '')
+    .join('\n');
+}
+
+// Bytes (as a string) that are sha256-hashed to produce the content hash.
+export function contentHashPreimage(ingredients: string, steps: string): string {
+  return canonicalize(ingredients) + '\n\n' + canonicalize(steps);
+}
+
+// Preimage under a given scheme, e.g. to check a stored recipe's hashScheme.
+// v1 hashed the raw text; v3 adds the canonical title as the first section.
+export function schemePreimage(scheme: number, title: string, ingredients: string, steps: string): string {
+  switch (scheme) {
+    case 1:
+      return ingredients + '\n' + steps;
+    case 2:
+      return contentHashPreimage(ingredients, steps);
+    case 3:
+      return canonicalize(title) + '\n\n' + contentHashPreimage(ingredients, steps);
+    default:
+      throw new Error(`Unknown content hash scheme ${scheme}`);
+  }
+}
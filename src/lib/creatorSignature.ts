@@ -0,0 +1,26 @@
+// EIP-712 creator signature over a recipe, mirroring backend/authorship.
+// Keep both in sync: the backend rejects recipes whose signature does not
+// recover to the creator address.
+import { ethers } from 'ethers';
+
+export const CREATOR_SIGNATURE_DOMAIN: ethers.TypedDataDomain = {
+  name: 'ProofPot',
+  version: '1',
+};
+
+export const CREATOR_SIGNATURE_TYPES: Record<string, ethers.TypedDataField[]> = {
+  RecipeSubmission: [
+    { name: 'title', type: 'string' },
+    { name: 'contentHash', type: 'bytes32' },
+  ],
+};
+
+// Asks the connected wallet to sign the recipe title and content hash.
+export async function signRecipe(account: string, title: string, contentHash: string): Promise<string> {
+  if (!window.ethereum) {
+    throw new Error('No wallet available to sign the recipe');
+  }
+  const provider = new ethers.BrowserProvider(window.ethereum);
+  const signer = await provider.getSigner(account);
+  return signer.signTypedData(CREATOR_SIGNATURE_DOMAIN, CREATOR_SIGNATURE_TYPES, { title, contentHash });
+}
@@ -27,6 +27,8 @@ import {
   TooltipTrigger,
 } from "@/components/ui/tooltip";
 import { ethers } from 'ethers';
+import { contentHashPreimage, CONTENT_HASH_SCHEME } from '@/lib/contentHash';
+import { signRecipe } from '@/lib/creatorSignature';
 
 const CreateRecipePage = () => {
   const navigate = useNavigate();
@@ -133,13 +135,28 @@ const CreateRecipePage = () => {
       return;
     }
 
-    const contentToHash = ingredients.join('\n') + '\n' + steps.join('\n');
+    const contentToHash = contentHashPreimage(ingredients.join('\n'), steps.join('\n'));
     console.log("Content being hashed:", JSON.stringify(contentToHash));
     const contentHash = ethers.sha256(ethers.toUtf8Bytes(contentToHash));
     console.log("Calculated contentHash:", contentHash);
 
     setIsSubmitting(true);
 
+    let creatorSignature: string;
+    try {
+      // The backend only accepts recipes signed by the creator's wallet
+      creatorSignature = await signRecipe(account, form.title.trim(), contentHash);
+    } catch (error) {
+      console.error('Error signing recipe:', error);
+      toast({
+        title: "Signature required",
+        description: "Please sign the recipe in your wallet to prove you are its creator.",
+        variant: "destructive"
+      });
+      setIsSubmitting(false);
+      return;
+    }
+
     try {
       const recipePayload = {
         title: form.title.trim(),
@@ -148,6 +165,8 @@ const CreateRecipePage = () => {
         steps: steps,
         creatorAddress: account,
         contentHash: contentHash,
+        hashScheme: CONTENT_HASH_SCHEME,
+        creatorSignature: creatorSignature,
 
         creatorName: form.creatorName.trim(),
         imageUrl: form.imageUrl || undefined,
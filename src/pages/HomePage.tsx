@@ -1,5 +1,5 @@
 import { useState, useEffect } from 'react';
-import { getRecipes } from '@/services/recipeService';
+import { getRecipes, searchRecipes } from '@/services/recipeService';
 import { RecipeListItem } from '@/types/recipe';
 import RecipeCard from '@/components/RecipeCard';
 import { Button } from '@/components/ui/button';
@@ -10,18 +10,37 @@ import { toast } from '@/components/ui/use-toast';
 
 const HomePage = () => {
   const [recipes, setRecipes] = useState<RecipeListItem[]>([]);
+  const [nextCursor, setNextCursor] = useState<string | null>(null);
   const [isLoading, setIsLoading] = useState(true);
+  const [isLoadingMore, setIsLoadingMore] = useState(false);
   const [error, setError] = useState<string | null>(null);
   const [searchTerm, setSearchTerm] = useState('');
+  const [searchText, setSearchText] = useState('');
   const navigate = useNavigate();
 
+  // Wait for typing to pause before searching on the server
+  useEffect(() => {
+    const timer = setTimeout(() => setSearchText(searchTerm.trim()), 300);
+    return () => clearTimeout(timer);
+  }, [searchTerm]);
+
+  // Lists all recipes, or the best search matches while there is search text
+  const fetchPage = async (cursor?: string | null) => {
+    if (!searchText) {
+      return getRecipes(cursor);
+    }
+    const page = await searchRecipes(searchText, cursor);
+    return { recipes: page.results, nextCursor: page.nextCursor };
+  };
+
   useEffect(() => {
     const loadRecipes = async () => {
       setIsLoading(true);
       setError(null);
       try {
-        const data = await getRecipes();
-        setRecipes(data);
+        const page = await fetchPage();
+        setRecipes(page.recipes);
+        setNextCursor(page.nextCursor);
       } catch (err) {
         console.error('Failed to load recipes:', err);
         const errorMsg = err instanceof Error ? err.message : "An unknown error occurred.";
@@ -37,14 +56,29 @@ const HomePage = () => {
     };
 
     loadRecipes();
-  }, []);
+  }, [searchText]);
 
-  const filteredRecipes = recipes.filter(recipe =>
-    recipe.title.toLowerCase().includes(searchTerm.toLowerCase()) ||
-    (recipe.tags && recipe.tags.some(tag => tag.toLowerCase().includes(searchTerm.toLowerCase())))
-  );
+  const loadMore = async () => {
+    if (!nextCursor) return;
+    setIsLoadingMore(true);
+    try {
+      const page = await fetchPage(nextCursor);
+      setRecipes((current) => [...current, ...page.recipes]);
+      setNextCursor(page.nextCursor);
+    } catch (err) {
+      console.error('Failed to load more recipes:', err);
+      toast({
+        title: "Error loading recipes",
+        description: err instanceof Error ? err.message : "An unknown error occurred.",
+        variant: "destructive",
+      });
+    } finally {
+      setIsLoadingMore(false);
+    }
+  };
 
-  if (isLoading) {
+  // Keep the search box mounted while search results load
+  if (isLoading && recipes.length === 0 && !searchTerm) {
     return (
       <div className="container mx-auto py-12 text-center">
         <div className="animate-pulse">
@@ -59,7 +93,7 @@ const HomePage = () => {
     );
   }
 
-  if (error && recipes.length === 0) {
+  if (error && recipes.length === 0 && !searchText) {
     return (
       <div className="container mx-auto py-12 text-center text-destructive">
         <h2 className="text-xl font-medium mb-2">Failed to Load Recipes</h2>
@@ -92,7 +126,7 @@ const HomePage = () => {
         </div>
       </div>
 
-      {!isLoading && filteredRecipes.length === 0 ? (
+      {!isLoading && recipes.length === 0 ? (
         <div className="text-center py-12">
           <h2 className="text-xl font-medium mb-2">No recipes found</h2>
           <p className="text-muted-foreground mb-4">Try adjusting your search terms or create the first recipe!</p>
@@ -100,11 +134,19 @@ const HomePage = () => {
         </div>
       ) : (
         <div className="grid grid-cols-1 md:grid-cols-2 lg:grid-cols-3 gap-6">
-          {filteredRecipes.map((recipe) => (
+          {recipes.map((recipe) => (
             <RecipeCard key={recipe.id} recipe={recipe} />
           ))}
         </div>
       )}
+
+      {nextCursor && (
+        <div className="text-center mt-8">
+          <Button variant="outline" onClick={loadMore} disabled={isLoadingMore}>
+            {isLoadingMore ? 'Loading...' : 'Load more'}
+          </Button>
+        </div>
+      )}
     </div>
   );
 };
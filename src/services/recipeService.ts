@@ -1,4 +1,4 @@
-import { Recipe, RecipeListItem, RecipeCreationApiResponse } from '@/types/recipe';
+import { Recipe, RecipeListItem, RecipePage, RecipeSearchPage, RecipeCreationApiResponse } from '@/types/recipe';
 // import { v4 as uuidv4 } from 'uuid'; // No longer needed for mock
 
 // Base URL for the API - Use environment variable
@@ -123,6 +123,8 @@ interface RecipeComponentPayload {
   steps: string[];       // Array from component
   creatorAddress: string;
   contentHash: string;
+  hashScheme?: number;
+  creatorSignature: string; // EIP-712 signature by creatorAddress (see src/lib/creatorSignature.ts)
   // Include other optional fields from component form state if needed
   imageUrl?: string;
   // tags?: string[];
@@ -137,7 +139,9 @@ interface RecipeApiPayload {
   steps: string;       // Joined string for backend
   creatorAddress: string;
   contentHash: string;
+  hashScheme?: number; // Content hash scheme version (see src/lib/contentHash.ts)
   imageUrl?: string;   // Added imageUrl field (optional)
+  creatorSignature: string; // EIP-712 signature of title and contentHash by creatorAddress
   // Omit other fields not present in the Go `models.Recipe` struct
 }
 
@@ -188,21 +192,49 @@ const transformBackendRecipeToListItem = (backendListItem: any): RecipeListItem
 
 // --- Service functions --- 
 
-// Updated getRecipes to fetch from backend and return RecipeListItem[]
-export const getRecipes = async (): Promise<RecipeListItem[]> => {
-  // Use the base URL defined above
-  const response = await fetch(`${API_BASE_URL}/recipes`);
+// Fetches one page of recipes, newest first. Pass the previous page's nextCursor
+// to continue where it ended.
+export const getRecipes = async (cursor?: string | null): Promise<RecipePage> => {
+  const params = new URLSearchParams();
+  if (cursor) {
+    params.set('cursor', cursor);
+  }
+  const query = params.toString();
+  const response = await fetch(`${API_BASE_URL}/recipes${query ? `?${query}` : ''}`);
   if (!response.ok) {
     console.error('Fetch error:', response.status, await response.text()); // Log error details
     throw new Error('Failed to fetch recipes');
   }
   const data = await response.json();
-  // Handle cases where backend might return null instead of an empty array
-  if (!data) {
-    return [];
-  }
   // Transform each item using the specific list item transformer
-  return data.map(transformBackendRecipeToListItem);
+  return {
+    recipes: (data?.recipes ?? []).map(transformBackendRecipeToListItem),
+    nextCursor: data?.nextCursor ?? null,
+  };
+};
+
+// Searches recipe titles, ingredients and steps, best matches first. Pass the
+// previous page's nextCursor, with the same text, to continue where it ended.
+export const searchRecipes = async (text: string, cursor?: string | null): Promise<RecipeSearchPage> => {
+  const params = new URLSearchParams({ q: text });
+  if (cursor) {
+    params.set('cursor', cursor);
+  }
+  const response = await fetch(`${API_BASE_URL}/recipes/search?${params.toString()}`);
+  if (!response.ok) {
+    console.error('Search error:', response.status, await response.text());
+    throw new Error('Failed to search recipes');
+  }
+  const data = await response.json();
+  return {
+    results: (data?.results ?? []).map((result: any) => ({
+      ...transformBackendRecipeToListItem(result),
+      rank: result?.rank ?? 0,
+      titleHighlight: result?.titleHighlight ?? '',
+      snippet: result?.snippet ?? '',
+    })),
+    nextCursor: data?.nextCursor ?? null,
+  };
 };
 
 // New function to fetch by hash, returns full Recipe
@@ -244,7 +276,9 @@ export const addRecipe = async (componentPayload: RecipeComponentPayload): Promi
     steps: componentPayload.steps.join('\n'),
     creatorAddress: componentPayload.creatorAddress,
     contentHash: componentPayload.contentHash,
+    hashScheme: componentPayload.hashScheme,
     imageUrl: componentPayload.imageUrl, // Add the imageUrl from the component payload
+    creatorSignature: componentPayload.creatorSignature,
   };
   console.log(`[RecipeService] Sending POST to ${API_ENDPOINT} with payload:`, apiPayload);
 
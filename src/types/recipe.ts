@@ -29,6 +29,25 @@ export interface RecipeListItem {
   tags?: string[];
 }
 
+// One page of GET /api/recipes; pass nextCursor back to get the next page
+export interface RecipePage {
+  recipes: RecipeListItem[];
+  nextCursor: string | null;
+}
+
+// A recipe found by GET /api/recipes/search. The highlights are HTML in which
+// only <mark> tags are left unescaped.
+export interface RecipeSearchResult extends RecipeListItem {
+  rank: number;
+  titleHighlight: string;
+  snippet: string;
+}
+
+export interface RecipeSearchPage {
+  results: RecipeSearchResult[];
+  nextCursor: string | null;
+}
+
 // Type for the specific response from POST /api/recipes
 export interface RecipeCreationApiResponse {
   id: number;